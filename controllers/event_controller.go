@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// eventKeepAliveInterval is how often a comment is written to idle event streams, to keep connections open
+const eventKeepAliveInterval = 30 * time.Second
+
+// GetEvents will stream events as Server-Sent Events, filtered by the leg_id, match_id, venue_id, office_id and types parameters
+func GetEvents(w http.ResponseWriter, r *http.Request) {
+	var filter models.EventFilter
+	for name, value := range map[string]*null.Int{"leg_id": &filter.LegID, "match_id": &filter.MatchID, "venue_id": &filter.VenueID, "office_id": &filter.OfficeID} {
+		if param := r.URL.Query().Get(name); param != "" {
+			id, err := strconv.Atoi(param)
+			if err != nil {
+				log.Printf("Invalid %s parameter", name)
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			*value = null.IntFrom(int64(id))
+		}
+	}
+	if param := r.URL.Query().Get("types"); param != "" {
+		filter.Types = strings.Split(param, ",")
+	}
+	streamEvents(w, r, filter)
+}
+
+// GetLegEvents will stream events for the given leg
+func GetLegEvents(w http.ResponseWriter, r *http.Request) {
+	streamScopedEvents(w, r, func(filter *models.EventFilter, id null.Int) { filter.LegID = id })
+}
+
+// GetMatchEvents will stream events for the given match
+func GetMatchEvents(w http.ResponseWriter, r *http.Request) {
+	streamScopedEvents(w, r, func(filter *models.EventFilter, id null.Int) { filter.MatchID = id })
+}
+
+// GetVenueEvents will stream events for matches played at the given venue
+func GetVenueEvents(w http.ResponseWriter, r *http.Request) {
+	streamScopedEvents(w, r, func(filter *models.EventFilter, id null.Int) { filter.VenueID = id })
+}
+
+// GetVenueLights will stream the light cues for the given venue, so all clients show the same lighting
+func GetVenueLights(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id, err := strconv.Atoi(params["id"])
+	if err != nil {
+		log.Println("Invalid id parameter")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	streamEvents(w, r, models.EventFilter{VenueID: null.IntFrom(int64(id)), Types: []string{models.EVENTLIGHTCUE}})
+}
+
+// GetOfficeEvents will stream events for matches played in the given office
+func GetOfficeEvents(w http.ResponseWriter, r *http.Request) {
+	streamScopedEvents(w, r, func(filter *models.EventFilter, id null.Int) { filter.OfficeID = id })
+}
+
+// streamScopedEvents will stream events with the filter scoped by the id parameter
+func streamScopedEvents(w http.ResponseWriter, r *http.Request, scope func(*models.EventFilter, null.Int)) {
+	params := mux.Vars(r)
+	id, err := strconv.Atoi(params["id"])
+	if err != nil {
+		log.Println("Invalid id parameter")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var filter models.EventFilter
+	scope(&filter, null.IntFrom(int64(id)))
+	if param := r.URL.Query().Get("types"); param != "" {
+		filter.Types = strings.Split(param, ",")
+	}
+	streamEvents(w, r, filter)
+}
+
+// streamEvents will write all events matching the given filter until the client disconnects. Clients can resume from
+// a sequence number given either in the Last-Event-ID header or the since parameter
+func streamEvents(w http.ResponseWriter, r *http.Request, filter models.EventFilter) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	since := r.Header.Get("Last-Event-ID")
+	if param := r.URL.Query().Get("since"); param != "" {
+		since = param
+	}
+	var sequence uint64
+	if since != "" {
+		var err error
+		sequence, err = strconv.ParseUint(since, 10, 64)
+		if err != nil {
+			log.Println("Invalid since parameter")
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	SetHeaders(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	sub, missed := models.Events.Subscribe(filter, sequence)
+	defer models.Events.Unsubscribe(sub)
+	for _, event := range missed {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				// Client was not keeping up, so close the stream to let it resume
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent will write the given event in Server-Sent Events format
+func writeEvent(w http.ResponseWriter, event *models.Event) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Unable to serialize event %d: %s", event.Sequence, err)
+		return nil
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Sequence, event.Type, b)
+	return err
+}
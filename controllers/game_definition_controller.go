@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/kcapp/api/data"
+	"github.com/kcapp/api/models"
+)
+
+// AddGameDefinition will create a new custom game definition
+func AddGameDefinition(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	var def models.GameDefinition
+	err := json.NewDecoder(r.Body).Decode(&def)
+	if err != nil {
+		log.Println("Unable to deserialize game definition json", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = def.ValidateInput()
+	if err != nil {
+		log.Println("Invalid game definition", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := data.AddGameDefinition(def)
+	if err != nil {
+		log.Println("Unable to add game definition", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	created, err := data.GetGameDefinition(int(id))
+	if err != nil {
+		log.Println("Unable to get game definition", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(created)
+}
+
+// GetGameDefinitions will return all custom game definitions
+func GetGameDefinitions(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	defs, err := data.GetGameDefinitions()
+	if err != nil {
+		log.Println("Unable to get game definitions", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(defs)
+}
+
+// GetGameDefinition will return the given custom game definition
+func GetGameDefinition(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	params := mux.Vars(r)
+	id, err := strconv.Atoi(params["id"])
+	if err != nil {
+		log.Println("Invalid id parameter")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	def, err := data.GetGameDefinition(id)
+	if err != nil {
+		log.Println("Unable to get game definition", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(def)
+}
@@ -112,119 +112,73 @@ func GetStatisticsForLeg(w http.ResponseWriter, r *http.Request) {
 	if leg.LegType != nil {
 		matchType = leg.LegType.ID
 	}
-	if matchType == models.SHOOTOUT {
-		stats, err := data.GetShootoutStatisticsForLeg(legID)
-		if err != nil {
-			log.Println("Unable to get shootout statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if matchType == models.CRICKET {
-		stats, err := data.GetCricketStatisticsForLeg(legID)
-		if err != nil {
-			log.Println("Unable to get cricket statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if matchType == models.DARTSATX {
-		stats, err := data.GetDartsAtXStatisticsForLeg(legID)
-		if err != nil {
-			log.Println("Unable to get Darts At X statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if matchType == models.AROUNDTHECLOCK {
-		stats, err := data.GetAroundTheClockStatisticsForLeg(legID)
-		if err != nil {
-			log.Println("Unable to get Around the Clock statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if matchType == models.AROUNDTHEWORLD {
-		stats, err := data.GetAroundTheWorldStatisticsForLeg(legID)
-		if err != nil {
-			log.Println("Unable to get Around the World statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if matchType == models.SHANGHAI {
-		stats, err := data.GetShanghaiStatisticsForLeg(legID)
-		if err != nil {
-			log.Println("Unable to get Shanghai statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if matchType == models.TICTACTOE {
-		stats, err := data.GetTicTacToeStatisticsForLeg(legID)
-		if err != nil {
-			log.Println("Unable to get Tic Tac Toe statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if matchType == models.BERMUDATRIANGLE {
-		stats, err := data.GetBermudaTriangleStatisticsForLeg(legID)
-		if err != nil {
-			log.Println("Unable to get Bermuda Triangle statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if matchType == models.FOURTWENTY {
-		stats, err := data.Get420StatisticsForLeg(legID)
-		if err != nil {
-			log.Println("Unable to get 420 statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if matchType == models.KILLBULL {
-		stats, err := data.GetKillBullStatisticsForLeg(legID)
-		if err != nil {
-			log.Println("Unable to get Kill Bull statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if matchType == models.GOTCHA {
-		stats, err := data.GetGotchaStatisticsForLeg(legID)
-		if err != nil {
-			log.Println("Unable to get Gotcha statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if matchType == models.JDCPRACTICE {
-		stats, err := data.GetJDCPracticeStatisticsForLeg(legID)
-		if err != nil {
-			log.Println("Unable to get JDC Practice statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if matchType == models.KNOCKOUT {
-		stats, err := data.GetKnockoutStatisticsForLeg(legID)
-		if err != nil {
-			log.Println("Unable to get Knockout statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else {
-		stats, err := data.GetX01StatisticsForLeg(legID)
+	rules, err := models.GetGameRules(matchType)
+	if err != nil {
+		log.Println("Unknown match type", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	stats, err := rules.GetStatisticsForLeg(legID)
+	if err != nil {
+		log.Println("Unable to get statistics", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(stats)
+}
+
+// GetLegState will return the state of the given leg after the visit given by the visit parameter, or after the last visit if not given
+func GetLegState(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	params := mux.Vars(r)
+	legID, err := strconv.Atoi(params["id"])
+	if err != nil {
+		log.Println("Invalid id parameter")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	leg, err := data.GetLeg(legID)
+	if err != nil {
+		log.Println("Unable to get leg", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	visit := len(leg.Visits)
+	if param := r.URL.Query().Get("visit"); param != "" {
+		visit, err = strconv.Atoi(param)
 		if err != nil {
-			log.Println("Unable to get x01 statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			log.Println("Invalid visit parameter")
+			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		json.NewEncoder(w).Encode(stats)
 	}
+	state, err := data.GetLegState(legID, visit)
+	if err != nil {
+		log.Printf("[%d] Unable to get leg state after visit %d: %s", legID, visit, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(state)
+}
+
+// GetCheckoutSuggestion will return suggested routes for the current player to check out in the given leg
+func GetCheckoutSuggestion(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	params := mux.Vars(r)
+	legID, err := strconv.Atoi(params["id"])
+	if err != nil {
+		log.Println("Invalid id parameter")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	weighted := r.URL.Query().Get("weighted") == "true"
+	suggestion, err := data.GetCheckoutSuggestion(legID, weighted)
+	if err != nil {
+		log.Println("Unable to get checkout suggestion", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(suggestion)
 }
 
 // ChangePlayerOrder will modify the order of players for the given leg
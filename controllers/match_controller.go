@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
@@ -27,6 +28,10 @@ func NewMatch(w http.ResponseWriter, r *http.Request) {
 	match, err := data.NewMatch(matchInput)
 	if err != nil {
 		log.Println("Unable to start new match", err)
+		if errors.Is(err, models.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -53,6 +58,10 @@ func ReMatch(w http.ResponseWriter, r *http.Request) {
 	match, err = data.NewMatch(*match)
 	if err != nil {
 		log.Println("Unable to rematch: ", err)
+		if errors.Is(err, models.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -183,119 +192,56 @@ func GetStatisticsForMatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if match.MatchType.ID == models.SHOOTOUT {
-		stats, err := data.GetShootoutStatisticsForMatch(matchID)
-		if err != nil {
-			log.Printf("Unable to get shootout statistics for match %d: %s", matchID, err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if match.MatchType.ID == models.CRICKET {
-		stats, err := data.GetCricketStatisticsForMatch(matchID)
-		if err != nil {
-			log.Printf("Unable to get cricket statistics for match %d: %s", matchID, err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if match.MatchType.ID == models.DARTSATX {
-		stats, err := data.GetDartsAtXStatisticsForMatch(matchID)
-		if err != nil {
-			log.Printf("Unable to get darts at x statistics for match %d: %s", matchID, err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if match.MatchType.ID == models.AROUNDTHECLOCK {
-		stats, err := data.GetAroundTheClockStatisticsForMatch(matchID)
-		if err != nil {
-			log.Printf("Unable to get around the clock statistics for match %d: %s", matchID, err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if match.MatchType.ID == models.AROUNDTHEWORLD {
-		stats, err := data.GetAroundTheWorldStatisticsForMatch(matchID)
-		if err != nil {
-			log.Printf("Unable to get around the world statistics for match %d: %s", matchID, err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if match.MatchType.ID == models.SHANGHAI {
-		stats, err := data.GetShanghaiStatisticsForMatch(matchID)
-		if err != nil {
-			log.Printf("Unable to get shanghai statistics for match %d: %s", matchID, err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if match.MatchType.ID == models.TICTACTOE {
-		stats, err := data.GetTicTacToeStatisticsForMatch(matchID)
-		if err != nil {
-			log.Printf("Unable to get tic tac toe statistics for match %d: %s", matchID, err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if match.MatchType.ID == models.BERMUDATRIANGLE {
-		stats, err := data.GetBermudaTriangleStatisticsForMatch(matchID)
-		if err != nil {
-			log.Printf("Unable to get bermuda triangle statistics for match %d: %s", matchID, err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if match.MatchType.ID == models.FOURTWENTY {
-		stats, err := data.Get420StatisticsForMatch(matchID)
-		if err != nil {
-			log.Printf("Unable to get 420 statistics for match %d: %s", matchID, err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if match.MatchType.ID == models.KILLBULL {
-		stats, err := data.GetKillBullStatisticsForMatch(matchID)
-		if err != nil {
-			log.Printf("Unable to get Kill Bull statistics for match %d: %s", matchID, err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if match.MatchType.ID == models.GOTCHA {
-		stats, err := data.GetGotchaStatisticsForMatch(matchID)
-		if err != nil {
-			log.Printf("Unable to get Gotcha statistics for match %d: %s", matchID, err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if match.MatchType.ID == models.JDCPRACTICE {
-		stats, err := data.GetJDCPracticeStatisticsForMatch(matchID)
-		if err != nil {
-			log.Printf("Unable to get JDC Practice statistics for match %d: %s", matchID, err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else if match.MatchType.ID == models.KNOCKOUT {
-		stats, err := data.GetKnockoutStatisticsForMatch(matchID)
-		if err != nil {
-			log.Printf("Unable to get Knockout statistics for match %d: %s", matchID, err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-	} else {
-		stats, err := data.GetX01StatisticsForMatch(matchID)
-		if err != nil {
-			log.Printf("Unable to get x01 statistics for match %d: %s", matchID, err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
+	rules, err := models.GetGameRules(match.MatchType.ID)
+	if err != nil {
+		log.Printf("Unknown match type for match %d: %s", matchID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	stats, err := rules.GetStatisticsForMatch(matchID)
+	if err != nil {
+		log.Printf("Unable to get statistics for match %d: %s", matchID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(stats)
+}
+
+// GetStatisticsForSet will return statistics for all players in the given set of the given match
+func GetStatisticsForSet(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	params := mux.Vars(r)
+	matchID, err := strconv.Atoi(params["id"])
+	if err != nil {
+		log.Println("Invalid id parameter")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	set, err := strconv.Atoi(params["set"])
+	if err != nil {
+		log.Println("Invalid set parameter")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	match, err := data.GetMatch(matchID)
+	if err != nil {
+		log.Printf("Unable to get Match %d", matchID)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if match.MatchType.ID != models.X01 && match.MatchType.ID != models.X01HANDICAP {
+		log.Printf("Set statistics are not available for match type %d", match.MatchType.ID)
+		http.Error(w, "Set statistics are only available for X01 matches", http.StatusBadRequest)
+		return
+	}
+	stats, err := data.GetX01StatisticsForSet(matchID, set)
+	if err != nil {
+		log.Printf("Unable to get statistics for set %d of match %d: %s", set, matchID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
+	json.NewEncoder(w).Encode(stats)
 }
 
 // GetMatchesModes will return all match modes
@@ -141,6 +141,16 @@ func GetPlayerStatistics(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	statistics.X01 = x01
+
+	bobs27, err := data.GetBobs27StatisticsForPlayer(id)
+	if err != nil {
+		log.Println("Unable to get player Bob's 27 statistics")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if bobs27.LegsPlayed > 0 {
+		statistics.Bobs27 = bobs27
+	}
 	json.NewEncoder(w).Encode(statistics)
 }
 
@@ -161,162 +171,19 @@ func GetPlayerMatchTypeStatistics(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	switch matchType {
-	case models.X01:
-		stats, err := data.GetX01StatisticsForPlayer(id, models.X01)
-		if err != nil {
-			log.Println("Unable to get X01 statistics for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.SHOOTOUT:
-		stats, err := data.GetShootoutStatisticsForPlayer(id)
-		if err != nil {
-			log.Println("Unable to get Cricket statistics for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.X01HANDICAP:
-		stats, err := data.GetX01StatisticsForPlayer(id, models.X01HANDICAP)
-		if err != nil {
-			log.Println("Unable to get X01 handicap statistics for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.CRICKET:
-		stats, err := data.GetCricketStatisticsForPlayer(id)
-		if err != nil {
-			log.Println("Unable to get Cricket statistics for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.DARTSATX:
-		stats, err := data.GetDartsAtXStatisticsForPlayer(id)
-		if err != nil {
-			log.Println("Unable to get Darts at X statistics for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.AROUNDTHEWORLD:
-		stats, err := data.GetAroundTheWorldStatisticsForPlayer(id)
-		if err != nil {
-			log.Println("Unable to get Around The World Statistics for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.SHANGHAI:
-		stats, err := data.GetShanghaiStatisticsForPlayer(id)
-		if err != nil {
-			log.Println("Unable to get Shanghai Statistics for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.AROUNDTHECLOCK:
-		stats, err := data.GetAroundTheClockStatisticsForPlayer(id)
-		if err != nil {
-			log.Println("Unable to get Around the Clock Statistics for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.TICTACTOE:
-		stats, err := data.GetTicTacToeStatisticsForPlayer(id)
-		if err != nil {
-			log.Println("Unable to get Tic Tac Toe Statistics for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.BERMUDATRIANGLE:
-		stats, err := data.GetBermudaTriangleStatisticsForPlayer(id)
-		if err != nil {
-			log.Println("Unable to get Bermuda Triangle Statistics for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.FOURTWENTY:
-		stats, err := data.Get420StatisticsForPlayer(id)
-		if err != nil {
-			log.Println("Unable to get 420 Statistics for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.KILLBULL:
-		stats, err := data.GetKillBullStatisticsForPlayer(id)
-		if err != nil {
-			log.Println("Unable to get Kill Bull Statistics for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.GOTCHA:
-		stats, err := data.GetGotchaStatisticsForPlayer(id)
-		if err != nil {
-			log.Println("Unable to get Gotcha Statistics for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.JDCPRACTICE:
-		stats, err := data.GetJDCPracticeStatisticsForPlayer(id)
-		if err != nil {
-			log.Println("Unable to get JDC Practice Statistics for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.KNOCKOUT:
-		stats, err := data.GetKnockoutStatisticsForPlayer(id)
-		if err != nil {
-			log.Println("Unable to get Knockout Statistics for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	default:
+	rules, err := models.GetGameRules(matchType)
+	if err != nil {
 		log.Println("Unknown match type parameter")
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	stats, err := rules.GetStatisticsForPlayer(id)
+	if err != nil {
+		log.Println("Unable to get statistics for player", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(stats)
 }
 
 // GetPlayerMatchTypeHistory will return history of match statistics for the given player
@@ -342,161 +209,19 @@ func GetPlayerMatchTypeHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	switch matchType {
-	case models.X01:
-		legs, err := data.GetX01HistoryForPlayer(id, limit, models.X01)
-		if err != nil {
-			log.Println("Unable to get X01 history for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(legs)
-		return
-
-	case models.SHOOTOUT:
-		legs, err := data.GetShootoutHistoryForPlayer(id, limit)
-		if err != nil {
-			log.Println("Unable to get Shootout history for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(legs)
-		return
-
-	case models.X01HANDICAP:
-		legs, err := data.GetX01HistoryForPlayer(id, limit, models.X01HANDICAP)
-		if err != nil {
-			log.Println("Unable to get X01 handicap history for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(legs)
-		return
-
-	case models.CRICKET:
-		legs, err := data.GetCricketHistoryForPlayer(id, limit)
-		if err != nil {
-			log.Println("Unable to get Cricket history for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(legs)
-		return
-
-	case models.DARTSATX:
-		legs, err := data.GetDartsAtXHistoryForPlayer(id, limit)
-		if err != nil {
-			log.Println("Unable to get Darts at X history for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(legs)
-		return
-
-	case models.AROUNDTHEWORLD:
-		legs, err := data.GetAroundTheWorldHistoryForPlayer(id, limit)
-		if err != nil {
-			log.Println("Unable to get Around The World history for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(legs)
-		return
-
-	case models.SHANGHAI:
-		legs, err := data.GetShanghaiHistoryForPlayer(id, limit)
-		if err != nil {
-			log.Println("Unable to get Shanghai history for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(legs)
-		return
-
-	case models.AROUNDTHECLOCK:
-		legs, err := data.GetAroundTheClockHistoryForPlayer(id, limit)
-		if err != nil {
-			log.Println("Unable to get Around the Clock history for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(legs)
-		return
-
-	case models.TICTACTOE:
-		legs, err := data.GetTicTacToeHistoryForPlayer(id, limit)
-		if err != nil {
-			log.Println("Unable to get Tic Tac Toe history for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(legs)
-		return
-
-	case models.BERMUDATRIANGLE:
-		legs, err := data.GetBermudaTriangleHistoryForPlayer(id, limit)
-		if err != nil {
-			log.Println("Unable to get Bermuda Triangle history for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(legs)
-		return
-
-	case models.FOURTWENTY:
-		legs, err := data.Get420HistoryForPlayer(id, limit)
-		if err != nil {
-			log.Println("Unable to get 420 history for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(legs)
-		return
-
-	case models.KILLBULL:
-		legs, err := data.GetKillBullHistoryForPlayer(id, limit)
-		if err != nil {
-			log.Println("Unable to get Kill Bull history for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(legs)
-		return
-
-	case models.GOTCHA:
-		legs, err := data.GetGotchaHistoryForPlayer(id, limit)
-		if err != nil {
-			log.Println("Unable to get Gotcha history for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(legs)
-		return
-	case models.JDCPRACTICE:
-		legs, err := data.GetJDCPracticeHistoryForPlayer(id, limit)
-		if err != nil {
-			log.Println("Unable to get JDC Practice history for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(legs)
-		return
-
-	case models.KNOCKOUT:
-		legs, err := data.GetKnockoutHistoryForPlayer(id, limit)
-		if err != nil {
-			log.Println("Unable to get Knockout history for player", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(legs)
-		return
-
-	default:
+	rules, err := models.GetGameRules(matchType)
+	if err != nil {
 		log.Println("Unknown match type parameter")
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	legs, err := rules.GetHistoryForPlayer(id, limit)
+	if err != nil {
+		log.Println("Unable to get history for player", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(legs)
 }
 
 // GetPlayerX01PreviousStatistics will return statistics for the given player
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
@@ -47,6 +48,12 @@ func ModifyVisit(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	err = visit.ValidateInput()
+	if err != nil {
+		log.Println("Invalid visit", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	err = data.ModifyVisit(visit)
 	if err != nil {
@@ -91,3 +98,118 @@ func DeleteLastVisit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// AddBotVisit will generate and add a visit for the current player of the given leg, which must be a bot
+func AddBotVisit(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	params := mux.Vars(r)
+	legID, err := strconv.Atoi(params["id"])
+	if err != nil {
+		log.Println("Invalid id parameter")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	visit, err := data.AddBotVisit(legID)
+	if err != nil {
+		log.Printf(`[%d] Unable to add bot visit (%s)`, legID, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(visit)
+}
+
+// AddDart will add a single dart to the pending visit of the given leg
+func AddDart(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	params := mux.Vars(r)
+	legID, err := strconv.Atoi(params["id"])
+	if err != nil {
+		log.Println("Invalid id parameter")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var dart models.Dart
+	err = json.NewDecoder(r.Body).Decode(&dart)
+	if err != nil {
+		log.Println("Unable to deserialize body", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = dart.ValidateInput()
+	if err != nil {
+		log.Println("Invalid dart", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pending, err := data.AddDart(legID, dart)
+	if err != nil {
+		log.Printf(`[%d] Unable to add dart (%s)`, legID, err)
+		if errors.Is(err, models.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(pending)
+}
+
+// UndoDart will remove the last dart from the pending visit of the given leg
+func UndoDart(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	params := mux.Vars(r)
+	legID, err := strconv.Atoi(params["id"])
+	if err != nil {
+		log.Println("Invalid id parameter")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	pending, err := data.UndoDart(legID)
+	if err != nil {
+		log.Println("Unable to undo dart", err)
+		if errors.Is(err, models.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(pending)
+}
+
+// GetPendingVisit will return darts thrown in the pending visit of the given leg
+func GetPendingVisit(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	params := mux.Vars(r)
+	legID, err := strconv.Atoi(params["id"])
+	if err != nil {
+		log.Println("Invalid id parameter")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(data.GetPendingVisit(legID))
+}
+
+// EndVisit will write the pending visit of the given leg, counting remaining darts as misses
+func EndVisit(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	params := mux.Vars(r)
+	legID, err := strconv.Atoi(params["id"])
+	if err != nil {
+		log.Println("Invalid id parameter")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	pending, err := data.EndVisit(legID)
+	if err != nil {
+		log.Println("Unable to end visit", err)
+		if errors.Is(err, models.ErrInvalidInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(pending)
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/kcapp/api/data"
+	"github.com/kcapp/api/models"
+)
+
+// HandleSmartboardEvent will apply a raw event from a smartboard bridge to the active leg of the venue of the smartboard
+func HandleSmartboardEvent(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	params := mux.Vars(r)
+	uuid := params["uuid"]
+
+	var event models.SmartboardEvent
+	err := json.NewDecoder(r.Body).Decode(&event)
+	if err != nil {
+		log.Println("Unable to deserialize smartboard event json", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = event.ValidateInput()
+	if err != nil {
+		log.Println("Invalid smartboard event", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pending, err := data.HandleSmartboardEvent(uuid, event)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "No venue with smartboard "+uuid, http.StatusNotFound)
+			return
+		}
+		log.Printf("Unable to handle event from smartboard %s (%s)", uuid, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(pending)
+}
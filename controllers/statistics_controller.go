@@ -22,162 +22,19 @@ func GetStatistics(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	switch matchType {
-	case models.X01:
-		statistics, err := data.GetX01Statistics(params["from"], params["to"], matchType, 301, 501)
-		if err != nil {
-			log.Println("Unable to get X01 statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(statistics)
-		return
-
-	case models.SHOOTOUT:
-		stats, err := data.GetShootoutStatistics(params["from"], params["to"])
-		if err != nil {
-			log.Println("Unable to get Shootout statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.X01HANDICAP:
-		statistics, err := data.GetX01Statistics(params["from"], params["to"], matchType, 301, 501)
-		if err != nil {
-			log.Println("Unable to get X01 handicap statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(statistics)
-		return
-
-	case models.CRICKET:
-		stats, err := data.GetCricketStatistics(params["from"], params["to"])
-		if err != nil {
-			log.Println("Unable to get Cricket statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.DARTSATX:
-		stats, err := data.GetDartsAtXStatistics(params["from"], params["to"])
-		if err != nil {
-			log.Println("Unable to get Darts At X statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.AROUNDTHEWORLD:
-		stats, err := data.GetAroundTheWorldStatistics(params["from"], params["to"])
-		if err != nil {
-			log.Println("Unable to get Around The World statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.SHANGHAI:
-		stats, err := data.GetShanghaiStatistics(params["from"], params["to"])
-		if err != nil {
-			log.Println("Unable to get Shanghai statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.AROUNDTHECLOCK:
-		stats, err := data.GetAroundTheClockStatistics(params["from"], params["to"])
-		if err != nil {
-			log.Println("Unable to get Around The Clock statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.TICTACTOE:
-		stats, err := data.GetTicTacToeStatistics(params["from"], params["to"])
-		if err != nil {
-			log.Println("Unable to get Tic Tac Toe statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.BERMUDATRIANGLE:
-		stats, err := data.GetBermudaTriangleStatistics(params["from"], params["to"])
-		if err != nil {
-			log.Println("Unable to get Bermuda Triangle statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.FOURTWENTY:
-		stats, err := data.Get420Statistics(params["from"], params["to"])
-		if err != nil {
-			log.Println("Unable to get 420 Statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.KILLBULL:
-		stats, err := data.GetKillBullStatistics(params["from"], params["to"])
-		if err != nil {
-			log.Println("Unable to get Kill Bull Statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.GOTCHA:
-		stats, err := data.GetGotchaStatistics(params["from"], params["to"])
-		if err != nil {
-			log.Println("Unable to get Gotcha Statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.JDCPRACTICE:
-		stats, err := data.GetJDCPracticeStatistics(params["from"], params["to"])
-		if err != nil {
-			log.Println("Unable to get JDC Practice Statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	case models.KNOCKOUT:
-		stats, err := data.GetKnockoutStatistics(params["from"], params["to"])
-		if err != nil {
-			log.Println("Unable to get Knockout Statistics", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		json.NewEncoder(w).Encode(stats)
-		return
-
-	default:
+	rules, err := models.GetGameRules(matchType)
+	if err != nil {
 		log.Println("Unknown match type parameter")
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	stats, err := rules.GetStatistics(params["from"], params["to"])
+	if err != nil {
+		log.Println("Unable to get statistics", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(stats)
 }
 
 // GetGlobalStatistics will return some global statistics for all matches
@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/kcapp/api/data"
+	"github.com/kcapp/api/models"
+)
+
+// AddWebhook will create a new webhook
+func AddWebhook(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	var webhook models.Webhook
+	err := json.NewDecoder(r.Body).Decode(&webhook)
+	if err != nil {
+		log.Println("Unable to deserialize webhook json", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = webhook.ValidateInput()
+	if err == nil && webhook.Secret == "" {
+		err = errors.New("secret cannot be empty")
+	}
+	if err != nil {
+		log.Println("Invalid webhook", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := data.AddWebhook(webhook)
+	if err != nil {
+		log.Println("Unable to add webhook", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	created, err := data.GetWebhook(int(id))
+	if err != nil {
+		log.Println("Unable to get webhook", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(created)
+}
+
+// UpdateWebhook will update the given webhook
+func UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	params := mux.Vars(r)
+	id, err := strconv.Atoi(params["id"])
+	if err != nil {
+		log.Println("Invalid id parameter")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var webhook models.Webhook
+	err = json.NewDecoder(r.Body).Decode(&webhook)
+	if err != nil {
+		log.Println("Unable to deserialize webhook json", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = webhook.ValidateInput()
+	if err != nil {
+		log.Println("Invalid webhook", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = data.UpdateWebhook(id, webhook)
+	if err != nil {
+		log.Println("Unable to update webhook", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// DeleteWebhook will delete the given webhook
+func DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	params := mux.Vars(r)
+	id, err := strconv.Atoi(params["id"])
+	if err != nil {
+		log.Println("Invalid id parameter")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = data.DeleteWebhook(id)
+	if err != nil {
+		log.Println("Unable to delete webhook", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetWebhooks will return all webhooks
+func GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	webhooks, err := data.GetWebhooks()
+	if err != nil {
+		log.Println("Unable to get webhooks", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(webhooks)
+}
+
+// GetWebhook will return the given webhook
+func GetWebhook(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	params := mux.Vars(r)
+	id, err := strconv.Atoi(params["id"])
+	if err != nil {
+		log.Println("Invalid id parameter")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	webhook, err := data.GetWebhook(id)
+	if err != nil {
+		log.Println("Unable to get webhook", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// GetWebhookDeliveries will return the latest deliveries of the given webhook
+func GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	SetHeaders(w)
+	params := mux.Vars(r)
+	id, err := strconv.Atoi(params["id"])
+	if err != nil {
+		log.Println("Invalid id parameter")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit := 100
+	if param := r.URL.Query().Get("limit"); param != "" {
+		limit, err = strconv.Atoi(param)
+		if err != nil {
+			log.Println("Invalid limit parameter")
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	deliveries, err := data.GetWebhookDeliveries(id, limit)
+	if err != nil {
+		log.Println("Unable to get webhook deliveries", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(deliveries)
+}
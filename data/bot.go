@@ -0,0 +1,59 @@
+package data
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/kcapp/api/models"
+)
+
+// AddBotVisit will generate a visit for the current player of the given leg, and add it as any other visit. The current player must be a bot
+func AddBotVisit(legID int) (*models.Visit, error) {
+	leg, err := GetLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+	if leg.IsFinished {
+		return nil, errors.New("leg already finished")
+	}
+	match, err := GetMatch(leg.MatchID)
+	if err != nil {
+		return nil, err
+	}
+	matchType := match.MatchType.ID
+	if leg.LegType != nil {
+		matchType = leg.LegType.ID
+	}
+	if matchType != models.X01 && matchType != models.X01HANDICAP {
+		return nil, errors.New("bots can only throw in X01 legs")
+	}
+
+	players, err := GetPlayersScore(legID)
+	if err != nil {
+		return nil, err
+	}
+	player := players[leg.CurrentPlayerID]
+	if player.BotConfig == nil {
+		return nil, errors.New("current player is not a bot")
+	}
+	bot, err := getBotThrower(player.BotConfig)
+	if err != nil {
+		return nil, err
+	}
+	return AddVisit(models.GenerateBotVisit(bot, leg, player.PlayerID, player.CurrentScore))
+}
+
+// getBotThrower will return a bot mimicking the configured player if set, otherwise a bot throwing with the configured skill level
+func getBotThrower(config *models.BotConfig) (models.BotThrower, error) {
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+	if config.PlayerID.Valid {
+		visits, err := GetPlayerVisits(int(config.PlayerID.Int64))
+		if err != nil {
+			return nil, err
+		}
+		hits, _ := models.GetHitsMap(visits)
+		return &models.MimicBot{Hits: hits, DoubleRates: models.GetDoubleHitRates(hits, minDoubleAttempts), Rand: random}, nil
+	}
+	return &models.FixedSkillBot{Skill: int(config.Skill.Int64), Rand: random}, nil
+}
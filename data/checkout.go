@@ -0,0 +1,130 @@
+package data
+
+import (
+	"errors"
+
+	"github.com/kcapp/api/models"
+)
+
+const (
+	// minDoubleAttempts is the number of darts a player must have thrown at a double before the hit rate is used
+	minDoubleAttempts = 10
+	// doubleRateLegs is the number of most recent legs of a player used to calculate the double hit rates when checking out
+	doubleRateLegs = 100
+)
+
+// GetCheckoutSuggestion will return suggested routes for the current player to check out in the given leg, taking darts
+// already thrown in the visit into account. Routes can be weighted by the double hit rates of the player
+func GetCheckoutSuggestion(legID int, weighted bool) (*models.CheckoutSuggestion, error) {
+	leg, err := GetLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+	if leg.IsFinished {
+		return nil, errors.New("leg already finished")
+	}
+	match, err := GetMatch(leg.MatchID)
+	if err != nil {
+		return nil, err
+	}
+	matchType := match.MatchType.ID
+	if leg.LegType != nil {
+		matchType = leg.LegType.ID
+	}
+	if matchType != models.X01 && matchType != models.X01HANDICAP {
+		return nil, errors.New("checkout suggestions are only available for X01 legs")
+	}
+
+	players, err := GetPlayersScore(legID)
+	if err != nil {
+		return nil, err
+	}
+	suggestion := &models.CheckoutSuggestion{
+		LegID:       legID,
+		PlayerID:    leg.CurrentPlayerID,
+		Score:       players[leg.CurrentPlayerID].CurrentScore,
+		DartsLeft:   3,
+		OutshotType: leg.GetOutshotTypeID(),
+	}
+	if pending := GetPendingVisit(legID); pending != nil && pending.PlayerID == leg.CurrentPlayerID {
+		for _, dart := range pending.Darts {
+			suggestion.Score -= dart.GetScore()
+		}
+		suggestion.DartsLeft -= len(pending.Darts)
+	}
+
+	var doubleRates map[int]float64
+	if weighted {
+		doubleRates, err = getDoubleHitRates(leg.CurrentPlayerID)
+		if err != nil {
+			return nil, err
+		}
+		suggestion.IsWeighted = len(doubleRates) > 0
+	}
+	suggestion.IsBogey = models.IsBogeyNumber(suggestion.Score, suggestion.OutshotType)
+	suggestion.Routes = models.GetCheckoutRoutes(suggestion.Score, suggestion.DartsLeft, suggestion.OutshotType, doubleRates)
+	return suggestion, nil
+}
+
+// getDoubleHitRates will return the rate of darts hitting each double out of the darts thrown at it when checking out, in the most
+// recent double out X01 legs of the given player
+func getDoubleHitRates(playerID int) (map[int]float64, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			s.leg_id, l.starting_score, s.player_id, s.thrower_id,
+			s.first_dart, s.first_dart_multiplier,
+			s.second_dart, s.second_dart_multiplier,
+			s.third_dart, s.third_dart_multiplier,
+			s.is_bust
+		FROM score s
+			JOIN (
+				SELECT l.id, l.starting_score
+				FROM leg l
+					JOIN player2leg p2l ON p2l.leg_id = l.id
+					JOIN matches m ON m.id = l.match_id
+					LEFT JOIN leg_parameters lp ON lp.leg_id = l.id
+				WHERE p2l.player_id = ?
+					AND IFNULL(l.leg_type_id, m.match_type_id) = 1 -- X01
+					AND IFNULL(lp.outshot_type_id, 1) = 1 AND lp.inshot_type_id IS NULL -- Double Out without inshot
+				ORDER BY l.id DESC
+				LIMIT ?) l ON l.id = s.leg_id
+		WHERE s.player_id = ?
+		ORDER BY s.leg_id, s.id`, playerID, doubleRateLegs, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	legs := make([]int, 0)
+	startingScores := make(map[int]int)
+	legVisits := make(map[int][]*models.Visit)
+	for rows.Next() {
+		var startingScore int
+		v := new(models.Visit)
+		v.FirstDart = new(models.Dart)
+		v.SecondDart = new(models.Dart)
+		v.ThirdDart = new(models.Dart)
+		err := rows.Scan(&v.LegID, &startingScore, &v.PlayerID, &v.ThrowerID,
+			&v.FirstDart.Value, &v.FirstDart.Multiplier,
+			&v.SecondDart.Value, &v.SecondDart.Multiplier,
+			&v.ThirdDart.Value, &v.ThirdDart.Multiplier,
+			&v.IsBust)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := legVisits[v.LegID]; !ok {
+			legs = append(legs, v.LegID)
+			startingScores[v.LegID] = startingScore
+		}
+		legVisits[v.LegID] = append(legVisits[v.LegID], v)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	attempts := make(map[int]*models.DoubleAttempts)
+	for _, legID := range legs {
+		models.CountDoubleAttempts(legVisits[legID], startingScores[legID], playerID, attempts)
+	}
+	return models.GetCheckoutDoubleRates(attempts, minDoubleAttempts), nil
+}
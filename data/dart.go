@@ -0,0 +1,190 @@
+package data
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/kcapp/api/models"
+)
+
+var (
+	pendingVisits     = make(map[int]*models.PendingVisit)
+	pendingVisitsLock sync.Mutex
+)
+
+// AddDart will add the given dart to the pending visit of the current player in the given leg. The visit is written
+// when all three darts are thrown, or as soon as a dart busts or finishes the leg
+func AddDart(legID int, dart models.Dart) (*models.PendingVisit, error) {
+	pendingVisitsLock.Lock()
+	defer pendingVisitsLock.Unlock()
+
+	leg, err := getUnfinishedLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+	pending := getCurrentPendingVisit(leg)
+	if pending == nil {
+		pending = models.NewPendingVisit(legID, leg.CurrentPlayerID)
+		pendingVisits[legID] = pending
+	}
+	err = pending.AddDart(dart)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", models.ErrInvalidInput, err)
+	}
+
+	err = checkPendingVisit(leg, pending)
+	if err != nil {
+		pending.UndoDart()
+		return nil, err
+	}
+	if pending.IsComplete() || pending.IsBust || pending.IsFinished {
+		return commitPendingVisit(pending, false)
+	}
+	return pending, nil
+}
+
+// UndoDart will remove the last dart from the pending visit of the current player in the given leg
+func UndoDart(legID int) (*models.PendingVisit, error) {
+	pendingVisitsLock.Lock()
+	defer pendingVisitsLock.Unlock()
+
+	leg, err := GetLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+	pending := getCurrentPendingVisit(leg)
+	if pending == nil {
+		return nil, fmt.Errorf("%w: no darts to undo", models.ErrInvalidInput)
+	}
+	err = pending.UndoDart()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", models.ErrInvalidInput, err)
+	}
+	log.Printf("[%d] Removed last dart of pending visit for player %d", legID, pending.PlayerID)
+	return pending, nil
+}
+
+// EndVisit will write the pending visit of the given leg, counting darts not thrown as misses
+func EndVisit(legID int) (*models.PendingVisit, error) {
+	pendingVisitsLock.Lock()
+	defer pendingVisitsLock.Unlock()
+
+	leg, err := getUnfinishedLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+	pending := getCurrentPendingVisit(leg)
+	if pending == nil || len(pending.Darts) == 0 {
+		return nil, fmt.Errorf("%w: no darts thrown in visit", models.ErrInvalidInput)
+	}
+	return commitPendingVisit(pending, true)
+}
+
+// SkipVisit will write a visit of three misses for the current player of the given leg, or the pending visit if any darts are thrown
+func SkipVisit(legID int) (*models.PendingVisit, error) {
+	pendingVisitsLock.Lock()
+	defer pendingVisitsLock.Unlock()
+
+	leg, err := getUnfinishedLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+	pending := getCurrentPendingVisit(leg)
+	if pending == nil {
+		pending = models.NewPendingVisit(legID, leg.CurrentPlayerID)
+	}
+	return commitPendingVisit(pending, true)
+}
+
+// NextVisit will write the pending visit of the given leg if any darts are thrown. Otherwise a visit of three misses is written
+// for the current player if skipEmpty is set, or nothing is done if not
+func NextVisit(legID int, skipEmpty bool) (*models.PendingVisit, error) {
+	pendingVisitsLock.Lock()
+	defer pendingVisitsLock.Unlock()
+
+	leg, err := GetLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+	pending := getCurrentPendingVisit(leg)
+	if pending == nil || len(pending.Darts) == 0 {
+		if !skipEmpty {
+			return nil, nil
+		}
+		pending = models.NewPendingVisit(legID, leg.CurrentPlayerID)
+	}
+	if leg.IsFinished {
+		return nil, fmt.Errorf("%w: leg already finished", models.ErrInvalidInput)
+	}
+	return commitPendingVisit(pending, true)
+}
+
+// GetPendingVisit will return the pending visit of the given leg, or nil if no darts are thrown
+func GetPendingVisit(legID int) *models.PendingVisit {
+	pendingVisitsLock.Lock()
+	defer pendingVisitsLock.Unlock()
+	return pendingVisits[legID]
+}
+
+// checkPendingVisit will check if the last dart of the pending visit busted or finished the leg
+func checkPendingVisit(leg *models.Leg, pending *models.PendingVisit) error {
+	rules, err := models.GetGameRules(leg.LegType.ID)
+	if err != nil {
+		return err
+	}
+	players, err := GetPlayersScore(leg.ID)
+	if err != nil {
+		return err
+	}
+
+	// Some legs finish after a given number of visits, so check if the leg would finish without any darts
+	// to only count it as finished if the darts thrown finished it
+	empty := models.NewPendingVisit(leg.ID, pending.PlayerID).GetVisit(true)
+	scores := copyPlayers(players)
+	rules.HandleVisit(leg, scores, &empty)
+	finishedWithoutDarts := rules.IsLegFinished(leg, scores, &empty)
+
+	visit := pending.GetVisit(false)
+	scores = copyPlayers(players)
+	rules.HandleVisit(leg, scores, &visit)
+	pending.IsBust = visit.IsBust
+	pending.IsFinished = !visit.IsBust && !finishedWithoutDarts && rules.IsLegFinished(leg, scores, &visit)
+	return nil
+}
+
+// getUnfinishedLeg will return the given leg, or an error if the leg is already finished
+func getUnfinishedLeg(legID int) (*models.Leg, error) {
+	leg, err := GetLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+	if leg.IsFinished {
+		return nil, fmt.Errorf("%w: leg already finished", models.ErrInvalidInput)
+	}
+	return leg, nil
+}
+
+// getCurrentPendingVisit will return the pending visit of the given leg. Visits might have been added without darts,
+// so a pending visit not thrown by the current player is stale, and is removed
+func getCurrentPendingVisit(leg *models.Leg) *models.PendingVisit {
+	pending := pendingVisits[leg.ID]
+	if pending != nil && pending.PlayerID != leg.CurrentPlayerID {
+		log.Printf("[%d] Removing stale pending visit of player %d", leg.ID, pending.PlayerID)
+		delete(pendingVisits, leg.ID)
+		return nil
+	}
+	return pending
+}
+
+// commitPendingVisit will write the pending visit to the database, and remove it from pending visits.
+// The pending visit is removed even if it could not be written, so that darts are not added to an invalid visit
+func commitPendingVisit(pending *models.PendingVisit, missRemaining bool) (*models.PendingVisit, error) {
+	delete(pendingVisits, pending.LegID)
+	visit, err := AddVisit(pending.GetVisit(missRemaining))
+	if err != nil {
+		return nil, err
+	}
+	pending.Visit = visit
+	return pending, nil
+}
@@ -0,0 +1,86 @@
+package data
+
+import (
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// publishLegEvent will publish an event of the given type for the given leg, scoped to the match, venue and office of the leg
+func publishLegEvent(eventType string, legID int, data interface{}) {
+	models.Events.Publish(getLegEvent(eventType, legID, data))
+}
+
+// getLegEvent will return an event of the given type for the given leg, reading the match, venue and office of the leg.
+// Since this queries the database, it should not be called while holding addVisitLock
+func getLegEvent(eventType string, legID int, data interface{}) models.Event {
+	event := models.Event{Type: eventType, LegID: null.IntFrom(int64(legID)), Data: data}
+	err := models.DB.QueryRow(`
+		SELECT l.match_id, m.venue_id, m.office_id
+		FROM leg l JOIN matches m ON m.id = l.match_id
+		WHERE l.id = ?`, legID).Scan(&event.MatchID, &event.VenueID, &event.OfficeID)
+	if err != nil {
+		log.Printf("[%d] Unable to get scope of %s event: %s", legID, eventType, err)
+	}
+	return event
+}
+
+// newLegEvent will return an event of the given type for the given leg, scoped to the venue and office of the given match
+func newLegEvent(eventType string, legID int, match *models.Match, data interface{}) models.Event {
+	return models.Event{Type: eventType, LegID: null.IntFrom(int64(legID)), MatchID: null.IntFrom(int64(match.ID)), VenueID: match.VenueID,
+		OfficeID: match.OfficeID, Data: data}
+}
+
+// publishMatchEvent will publish an event of the given type for the given match, scoped to the venue and office of the match
+func publishMatchEvent(eventType string, matchID int, data interface{}) {
+	event := models.Event{Type: eventType, MatchID: null.IntFrom(int64(matchID)), Data: data}
+	err := models.DB.QueryRow("SELECT venue_id, office_id FROM matches WHERE id = ?", matchID).Scan(&event.VenueID, &event.OfficeID)
+	if err != nil {
+		log.Printf("Unable to get scope of %s event for match %d: %s", eventType, matchID, err)
+	}
+	models.Events.Publish(event)
+}
+
+// publishHighestCheckout will publish a highest checkout event if the given checkout is the highest X01 or X01 Handicap checkout of the player
+func publishHighestCheckout(legID int, match *models.Match, playerID int, checkout int) {
+	var previous null.Int
+	err := models.DB.QueryRow(`
+		SELECT
+			MAX(IFNULL(s.first_dart * s.first_dart_multiplier, 0) +
+				IFNULL(s.second_dart * s.second_dart_multiplier, 0) +
+				IFNULL(s.third_dart * s.third_dart_multiplier, 0)) AS 'checkout'
+		FROM score s
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+		WHERE l.winner_id = s.player_id AND s.player_id = ? AND l.id <> ?
+			AND s.id = (SELECT MAX(id) FROM score WHERE leg_id = l.id)
+			AND IFNULL(l.leg_type_id, m.match_type_id) IN (1, 3) -- X01, X01 Handicap`, playerID, legID).Scan(&previous)
+	if err != nil {
+		log.Printf("[%d] Unable to get highest checkout for player %d: %s", legID, playerID, err)
+		return
+	}
+	if previous.Valid && int(previous.Int64) >= checkout {
+		return
+	}
+	models.Events.Publish(newLegEvent(models.EVENTHIGHESTCHECKOUT, legID, match, models.HighestCheckoutEvent{PlayerID: playerID, Checkout: checkout, PreviousCheckout: previous}))
+}
+
+// consumeEvents will call the given handler for each event matching the filter published after it was called, resuming after
+// the last handled event if the subscription is closed because events are not handled fast enough
+func consumeEvents(filter models.EventFilter, handler func(*models.Event)) {
+	sub, missed := models.Events.Subscribe(filter, 0)
+	sequence := sub.Sequence
+	for {
+		for _, event := range missed {
+			handler(event)
+			sequence = event.Sequence
+		}
+		for event := range sub.Events {
+			handler(event)
+			sequence = event.Sequence
+		}
+		log.Printf("Resuming event handling after event %d", sequence)
+		sub, missed = models.Events.Resume(filter, sequence)
+	}
+}
@@ -0,0 +1,144 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+	"github.com/kcapp/api/util"
+)
+
+// AddGameDefinition will add a new custom game definition, returning the id of the new definition
+func AddGameDefinition(def models.GameDefinition) (int64, error) {
+	tx, err := models.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := tx.Exec("INSERT INTO game_definition (name, description, office_id, scoring_rule, win_condition, created_at) VALUES (?, ?, ?, ?, ?, NOW())",
+		def.Name, def.Description, def.OfficeID, def.ScoringRule, def.WinCondition)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	defID, err := res.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	err = insertTargets(tx, null.IntFrom(defID), null.IntFromPtr(nil), def.Targets)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+
+	log.Printf("Created new game definition (%d) %s", defID, def.Name)
+	tx.Commit()
+	return defID, nil
+}
+
+// GetGameDefinitions will return all custom game definitions
+func GetGameDefinitions() ([]*models.GameDefinition, error) {
+	rows, err := models.DB.Query("SELECT id, name, description, office_id, scoring_rule, win_condition, created_at FROM game_definition ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	defs := make([]*models.GameDefinition, 0)
+	m := make(map[int]*models.GameDefinition)
+	for rows.Next() {
+		def := new(models.GameDefinition)
+		err := rows.Scan(&def.ID, &def.Name, &def.Description, &def.OfficeID, &def.ScoringRule, &def.WinCondition, &def.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		def.Targets = make([]models.Target, 0)
+		defs = append(defs, def)
+		m[def.ID] = def
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	targets, err := getTargets("SELECT game_definition_id, value, target_values, multipliers, score FROM game_target WHERE game_definition_id IS NOT NULL ORDER BY game_definition_id, round")
+	if err != nil {
+		return nil, err
+	}
+	for defID, t := range targets {
+		if def, ok := m[defID]; ok {
+			def.Targets = t
+		}
+	}
+	return defs, nil
+}
+
+// GetGameDefinition will return the custom game definition with the given id
+func GetGameDefinition(id int) (*models.GameDefinition, error) {
+	def := new(models.GameDefinition)
+	err := models.DB.QueryRow("SELECT id, name, description, office_id, scoring_rule, win_condition, created_at FROM game_definition WHERE id = ?", id).
+		Scan(&def.ID, &def.Name, &def.Description, &def.OfficeID, &def.ScoringRule, &def.WinCondition, &def.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := getTargets("SELECT game_definition_id, value, target_values, multipliers, score FROM game_target WHERE game_definition_id = ? ORDER BY round", id)
+	if err != nil {
+		return nil, err
+	}
+	def.Targets = targets[def.ID]
+	return def, nil
+}
+
+// insertTargets will write the target of each round of either the given game definition or the given leg. All targets are stored
+// in the same table, so that Halve-It legs and game definitions support the same targets
+func insertTargets(tx *sql.Tx, defID null.Int, legID null.Int, targets []models.Target) error {
+	for i, target := range targets {
+		multipliers := make([]int, len(target.GetMultipliers()))
+		for j, multiplier := range target.GetMultipliers() {
+			multipliers[j] = int(multiplier)
+		}
+		values := util.IntArrayToString(target.Values)
+		_, err := tx.Exec("INSERT INTO game_target (game_definition_id, leg_id, round, value, target_values, multipliers, score) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			defID, legID, i+1, target.Value, null.NewString(values, values != ""), util.IntArrayToString(multipliers), target.GetScore())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getTargets will return the targets of each game definition or leg, from a query returning definition or leg, value, values, multipliers and score
+func getTargets(query string, args ...interface{}) (map[int][]models.Target, error) {
+	rows, err := models.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	targets := make(map[int][]models.Target)
+	for rows.Next() {
+		var id, value, score int
+		var values null.String
+		var multipliers string
+		err := rows.Scan(&id, &value, &values, &multipliers, &score)
+		if err != nil {
+			return nil, err
+		}
+		var v []int
+		if values.Valid {
+			v = util.StringToIntArray(values.String)
+		}
+		m := make([]int64, 0)
+		for _, multiplier := range util.StringToIntArray(multipliers) {
+			m = append(m, int64(multiplier))
+		}
+		targets[id] = append(targets[id], models.NewCustomTarget(value, v, m, score))
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return targets, nil
+}
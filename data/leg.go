@@ -2,6 +2,7 @@ package data
 
 import (
 	"database/sql"
+	"errors"
 	"log"
 	"sort"
 
@@ -60,22 +61,17 @@ func NewLeg(matchID int, startingScore int, players []int, matchType *int) (*mod
 		for _, player := range scores {
 			handicaps[player.PlayerID] = player.Handicap
 		}
-	} else if *matchType == models.TICTACTOE {
-		params := match.Legs[0].Parameters
-		params.GenerateTicTacToeNumbers(startingScore)
-		_, err = tx.Exec("INSERT INTO leg_parameters (leg_id, outshot_type_id, number_1, number_2, number_3, number_4, number_5, number_6, number_7, number_8, number_9) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
-			legID, params.OutshotType.ID, params.Numbers[0], params.Numbers[1], params.Numbers[2], params.Numbers[3], params.Numbers[4], params.Numbers[5], params.Numbers[6], params.Numbers[7], params.Numbers[8])
-		if err != nil {
-			tx.Rollback()
-			return nil, err
-		}
-	} else if *matchType == models.KNOCKOUT {
-		params := match.Legs[0].Parameters
-		_, err = tx.Exec("INSERT INTO leg_parameters (leg_id, starting_lives) VALUES (?, ?)", legID, params.StartingLives)
-		if err != nil {
-			tx.Rollback()
-			return nil, err
-		}
+	}
+
+	rules, err := models.GetGameRules(*matchType)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	err = rules.InsertLegParameters(tx, &models.Leg{ID: int(legID), StartingScore: startingScore, Players: players, Parameters: match.Legs[0].Parameters})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
 	}
 
 	for idx, playerID := range players {
@@ -89,6 +85,7 @@ func NewLeg(matchID int, startingScore int, players []int, matchType *int) (*mod
 	}
 	tx.Commit()
 	log.Printf("[%d] Started new leg", legID)
+	models.Events.Publish(newLegEvent(models.EVENTLEGSTARTED, int(legID), match, nil))
 
 	return GetLeg(int(legID))
 }
@@ -109,60 +106,21 @@ func FinishLeg(visit models.Visit) error {
 	}
 
 	// Update leg with winner
-	winnerID := null.IntFrom(int64(visit.PlayerID))
 	matchType := match.MatchType.ID
 	if leg.LegType != nil {
 		matchType = leg.LegType.ID
 	}
-	if matchType == models.SHOOTOUT || matchType == models.DARTSATX || matchType == models.AROUNDTHEWORLD ||
-		(matchType == models.SHANGHAI && !visit.IsShanghai()) || matchType == models.BERMUDATRIANGLE ||
-		matchType == models.JDCPRACTICE {
-		// For certain game types we need to check the scores of each player to determine which player won the leg with the highest score
-		scores, err := GetPlayersScore(visit.LegID)
-		if err != nil {
-			return err
-		}
-		highScore := 0
-		isDraw := false
-		for playerID, player := range scores {
-			if player.CurrentScore == highScore {
-				isDraw = true
-			}
-			if player.CurrentScore > highScore {
-				highScore = player.CurrentScore
-				winnerID = null.IntFrom(int64(playerID))
-				isDraw = false
-			}
-		}
-		if isDraw {
-			winnerID = null.IntFromPtr(nil)
-		}
-	} else if matchType == models.FOURTWENTY {
-		scores, err := GetPlayersScore(visit.LegID)
-		if err != nil {
-			return err
-		}
-		lowestScore := 421
-		for playerID, player := range scores {
-			if player.CurrentScore < lowestScore {
-				lowestScore = player.CurrentScore
-				winnerID = null.IntFrom(int64(playerID))
-			}
-		}
-	} else if matchType == models.TICTACTOE && !leg.Parameters.IsTicTacToeWinner(visit.PlayerID) {
-		// If current player did not win, this game is a draw
-		winnerID = null.IntFromPtr(nil)
-	} else if matchType == models.KNOCKOUT {
-		scores, err := GetPlayersScore(visit.LegID)
-		if err != nil {
-			return err
-		}
-		for _, player := range scores {
-			if player.Lives.Int64 > 0 {
-				winnerID = null.IntFrom(int64(player.PlayerID))
-			}
-		}
+	rules, err := models.GetGameRules(matchType)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	scores, err := GetPlayersScore(visit.LegID)
+	if err != nil {
+		tx.Rollback()
+		return err
 	}
+	winnerID := rules.GetWinner(leg, scores, visit)
 
 	_, err = tx.Exec(`UPDATE leg SET current_player_id = ?, winner_id = ?, is_finished = 1, end_time = NOW() WHERE id = ?`, visit.PlayerID, winnerID, visit.LegID)
 	if err != nil {
@@ -171,239 +129,10 @@ func FinishLeg(visit models.Visit) error {
 	}
 	log.Printf("[%d] Finished with player %d winning", visit.LegID, winnerID.ValueOrZero())
 
-	if matchType == models.SHOOTOUT {
-		statisticsMap, err := CalculateShootoutStatistics(visit.LegID)
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		for playerID, stats := range statisticsMap {
-			_, err = tx.Exec(`
-				INSERT INTO statistics_shootout(leg_id, player_id, score, ppd, 60s_plus, 100s_plus, 140s_plus, 180s)
-				VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, visit.LegID, playerID, stats.Score, stats.PPD, stats.Score60sPlus,
-				stats.Score100sPlus, stats.Score140sPlus, stats.Score180s)
-			if err != nil {
-				tx.Rollback()
-				return err
-			}
-			log.Printf("[%d] Inserting shootout statistics for player %d", visit.LegID, playerID)
-		}
-	} else if matchType == models.CRICKET {
-		statisticsMap, err := CalculateCricketStatistics(visit.LegID)
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		for playerID, stats := range statisticsMap {
-			_, err = tx.Exec(`
-				INSERT INTO statistics_cricket
-					(leg_id, player_id, total_marks, rounds, score, first_nine_marks, mpr, first_nine_mpr, marks5, marks6, marks7, marks8, marks9)
-				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, visit.LegID, playerID, stats.TotalMarks, stats.Rounds, stats.Score, stats.FirstNineMarks,
-				stats.MPR, stats.FirstNineMPR, stats.Marks5, stats.Marks6, stats.Marks7, stats.Marks8, stats.Marks9)
-			if err != nil {
-				tx.Rollback()
-				return err
-			}
-			log.Printf("[%d] Inserting cricket statistics for player %d", visit.LegID, playerID)
-		}
-	} else if matchType == models.DARTSATX {
-		statisticsMap, err := CalculateDartsAtXStatistics(visit.LegID)
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		for playerID, stats := range statisticsMap {
-			_, err = tx.Exec(`
-				INSERT INTO statistics_darts_at_x
-					(leg_id, player_id, score, singles, doubles, triples, hit_rate, hits5, hits6, hits7, hits8, hits9)
-				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, visit.LegID, playerID, stats.Score, stats.Singles, stats.Doubles, stats.Triples, stats.HitRate,
-				stats.Hits5, stats.Hits6, stats.Hits7, stats.Hits8, stats.Hits9)
-			if err != nil {
-				tx.Rollback()
-				return err
-			}
-			log.Printf("[%d] Inserting Darts At %d statistics for player %d", visit.LegID, leg.StartingScore, playerID)
-		}
-	} else if matchType == models.AROUNDTHECLOCK {
-		statisticsMap, err := CalculateAroundTheClockStatistics(visit.LegID)
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		for playerID, stats := range statisticsMap {
-			_, err = tx.Exec(`
-			INSERT INTO statistics_around_the
-				(leg_id, player_id, darts_thrown, score, longest_streak, total_hit_rate, hit_rate_1, hit_rate_2, hit_rate_3, hit_rate_4, hit_rate_5, hit_rate_6, hit_rate_7, hit_rate_8,
-					hit_rate_9, hit_rate_10, hit_rate_11, hit_rate_12, hit_rate_13, hit_rate_14, hit_rate_15, hit_rate_16, hit_rate_17, hit_rate_18, hit_rate_19, hit_rate_20, hit_rate_bull)
-			VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`, visit.LegID, playerID, stats.DartsThrown, stats.Score, stats.LongestStreak, stats.TotalHitRate, stats.Hitrates[1],
-				stats.Hitrates[2], stats.Hitrates[3], stats.Hitrates[4], stats.Hitrates[5], stats.Hitrates[6], stats.Hitrates[7], stats.Hitrates[8], stats.Hitrates[9], stats.Hitrates[10],
-				stats.Hitrates[11], stats.Hitrates[12], stats.Hitrates[13], stats.Hitrates[14], stats.Hitrates[15], stats.Hitrates[16], stats.Hitrates[17], stats.Hitrates[18], stats.Hitrates[19],
-				stats.Hitrates[20], stats.Hitrates[25])
-			if err != nil {
-				tx.Rollback()
-				return err
-			}
-			log.Printf("[%d] Inserting Around the Clock statistics for player %d", visit.LegID, playerID)
-		}
-	} else if matchType == models.AROUNDTHEWORLD || matchType == models.SHANGHAI {
-		statisticsMap, err := CalculateAroundTheWorldStatistics(visit.LegID, matchType)
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		for playerID, stats := range statisticsMap {
-			_, err = tx.Exec(`
-				INSERT INTO statistics_around_the
-					(leg_id, player_id, darts_thrown, score, shanghai, mpr, total_hit_rate, hit_rate_1, hit_rate_2, hit_rate_3, hit_rate_4, hit_rate_5, hit_rate_6, hit_rate_7, hit_rate_8, hit_rate_9, hit_rate_10,
-						hit_rate_11, hit_rate_12, hit_rate_13, hit_rate_14, hit_rate_15, hit_rate_16, hit_rate_17, hit_rate_18, hit_rate_19, hit_rate_20, hit_rate_bull)
-				VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`, visit.LegID, playerID, stats.DartsThrown, stats.Score, stats.Shanghai, stats.MPR, stats.TotalHitRate, stats.Hitrates[1],
-				stats.Hitrates[2], stats.Hitrates[3], stats.Hitrates[4], stats.Hitrates[5], stats.Hitrates[6], stats.Hitrates[7], stats.Hitrates[8], stats.Hitrates[9], stats.Hitrates[10],
-				stats.Hitrates[11], stats.Hitrates[12], stats.Hitrates[13], stats.Hitrates[14], stats.Hitrates[15], stats.Hitrates[16], stats.Hitrates[17], stats.Hitrates[18], stats.Hitrates[19],
-				stats.Hitrates[20], stats.Hitrates[25])
-			if err != nil {
-				tx.Rollback()
-				return err
-			}
-			log.Printf("[%d] Inserting Around the World/Shanghai statistics for player %d", visit.LegID, playerID)
-		}
-	} else if matchType == models.TICTACTOE {
-		statisticsMap, err := CalculateTicTacToeStatistics(visit.LegID)
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		for playerID, stats := range statisticsMap {
-			_, err = tx.Exec(`
-				INSERT INTO statistics_tic_tac_toe (leg_id, player_id, darts_thrown, score, numbers_closed, highest_closed) VALUES (?,?,?,?,?,?)`, visit.LegID,
-				playerID, stats.DartsThrown, stats.Score, stats.NumbersClosed, stats.HighestClosed)
-			if err != nil {
-				tx.Rollback()
-				return err
-			}
-			log.Printf("[%d] Inserting Tic Tac Toe statistics for player %d", visit.LegID, playerID)
-		}
-	} else if matchType == models.BERMUDATRIANGLE {
-		statisticsMap, err := CalculateBermudaTriangleStatistics(visit.LegID)
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		for playerID, stats := range statisticsMap {
-			_, err = tx.Exec(`
-				INSERT INTO statistics_bermuda_triangle (leg_id, player_id, darts_thrown, score, mpr, total_marks, highest_score_reached, total_hit_rate, hit_rate_1, hit_rate_2, hit_rate_3,
-					hit_rate_4, hit_rate_5, hit_rate_6, hit_rate_7, hit_rate_8, hit_rate_9, hit_rate_10, hit_rate_11, hit_rate_12, hit_rate_13, hit_count) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
-				visit.LegID, playerID, stats.DartsThrown, stats.Score, stats.MPR, &stats.TotalMarks, stats.HighestScoreReached, stats.TotalHitRate, stats.Hitrates[0], stats.Hitrates[1], stats.Hitrates[2],
-				stats.Hitrates[3], stats.Hitrates[4], stats.Hitrates[5], stats.Hitrates[6], stats.Hitrates[7], stats.Hitrates[8], stats.Hitrates[9], stats.Hitrates[10], stats.Hitrates[11], stats.Hitrates[12],
-				stats.HitCount)
-			if err != nil {
-				tx.Rollback()
-				return err
-			}
-			log.Printf("[%d] Inserting Bermuda Triangle statistics for player %d", visit.LegID, playerID)
-		}
-	} else if matchType == models.FOURTWENTY {
-		statisticsMap, err := Calculate420Statistics(visit.LegID)
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		for playerID, stats := range statisticsMap {
-			_, err = tx.Exec(`
-				INSERT INTO statistics_420 (leg_id, player_id, score, total_hit_rate, hit_rate_1, hit_rate_2, hit_rate_3, hit_rate_4, hit_rate_5, hit_rate_6, hit_rate_7, hit_rate_8, hit_rate_9,
-					hit_rate_10, hit_rate_11, hit_rate_12, hit_rate_13, hit_rate_14, hit_rate_15, hit_rate_16, hit_rate_17, hit_rate_18, hit_rate_19, hit_rate_20, hit_rate_bull) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
-				visit.LegID, playerID, stats.Score, stats.TotalHitRate, stats.Hitrates[1], stats.Hitrates[2], stats.Hitrates[3], stats.Hitrates[4], stats.Hitrates[5], stats.Hitrates[6],
-				stats.Hitrates[7], stats.Hitrates[8], stats.Hitrates[9], stats.Hitrates[10], stats.Hitrates[11], stats.Hitrates[12], stats.Hitrates[13], stats.Hitrates[14], stats.Hitrates[15], stats.Hitrates[16],
-				stats.Hitrates[17], stats.Hitrates[18], stats.Hitrates[19], stats.Hitrates[20], stats.Hitrates[25])
-			if err != nil {
-				tx.Rollback()
-				return err
-			}
-			log.Printf("[%d] Inserting Four Twenty statistics for player %d", visit.LegID, playerID)
-		}
-	} else if matchType == models.KILLBULL {
-		statisticsMap, err := CalculateKillBullStatistics(visit.LegID)
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		for playerID, stats := range statisticsMap {
-			_, err = tx.Exec(`
-						INSERT INTO statistics_kill_bull (leg_id, player_id, darts_thrown, score, marks3, marks4, marks5, marks6, longest_streak, times_busted, total_hit_rate) VALUES (?,?,?,?,?,?,?,?,?,?,?)`,
-				visit.LegID, playerID, stats.DartsThrown, stats.Score, stats.Marks3, stats.Marks4, stats.Marks5, stats.Marks6, stats.LongestStreak, stats.TimesBusted, stats.TotalHitRate)
-			if err != nil {
-				tx.Rollback()
-				return err
-			}
-			log.Printf("[%d] Inserting Kill Bull statistics for player %d", visit.LegID, playerID)
-		}
-	} else if matchType == models.GOTCHA {
-		statisticsMap, err := CalculateGotchaStatistics(visit.LegID)
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		for playerID, stats := range statisticsMap {
-			_, err = tx.Exec(`
-				INSERT INTO statistics_gotcha (leg_id, player_id, darts_thrown, highest_score, times_reset, others_reset, score) VALUES (?,?,?,?,?,?,?)`,
-				visit.LegID, playerID, stats.DartsThrown, stats.HighestScore, stats.TimesReset, stats.OthersReset, stats.Score)
-			if err != nil {
-				tx.Rollback()
-				return err
-			}
-			log.Printf("[%d] Inserting Gotcha statistics for player %d", visit.LegID, playerID)
-		}
-	} else if matchType == models.JDCPRACTICE {
-		statisticsMap, err := CalculateJDCPracticeStatistics(visit.LegID)
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		for playerID, stats := range statisticsMap {
-			_, err = tx.Exec(`
-				INSERT INTO statistics_jdc_practice (leg_id, player_id, darts_thrown, score, mpr, shanghai_count, doubles_hitrate) VALUES (?,?,?,?,?,?,?)`,
-				visit.LegID, playerID, stats.DartsThrown, stats.Score, stats.MPR, stats.ShanghaiCount, stats.DoublesHitrate)
-			if err != nil {
-				tx.Rollback()
-				return err
-			}
-			log.Printf("[%d] Inserting JDC Practice statistics for player %d", visit.LegID, playerID)
-		}
-	} else if matchType == models.KNOCKOUT {
-		statisticsMap, err := CalculateKnockoutStatistics(visit.LegID)
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		for playerID, stats := range statisticsMap {
-			_, err = tx.Exec(`
-				INSERT INTO statistics_knockout (leg_id, player_id, darts_thrown, avg_score, lives_lost, lives_taken, final_position) VALUES (?,?,?,?,?,?,?)`,
-				visit.LegID, playerID, stats.DartsThrown, stats.AvgScore, stats.LivesLost, stats.LivesTaken, stats.FinalPosition)
-			if err != nil {
-				tx.Rollback()
-				return err
-			}
-			log.Printf("[%d] Inserting Knockout statistics for player %d", visit.LegID, playerID)
-		}
-	} else {
-		statisticsMap, err := CalculateX01Statistics(visit.LegID, visit.PlayerID, leg.StartingScore)
-		if err != nil {
-			tx.Rollback()
-			return err
-		}
-		for playerID, stats := range statisticsMap {
-			_, err = tx.Exec(`
-				INSERT INTO statistics_x01
-					(leg_id, player_id, ppd, ppd_score, first_nine_ppd, first_nine_ppd_score, checkout_percentage, checkout_attempts, darts_thrown, 60s_plus,
-					 100s_plus, 140s_plus, 180s, accuracy_20, accuracy_19, overall_accuracy)
-				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, visit.LegID, playerID, stats.PPD, stats.PPDScore, stats.FirstNinePPD, stats.FirstNinePPDScore,
-				stats.CheckoutPercentage, stats.CheckoutAttempts, stats.DartsThrown, stats.Score60sPlus, stats.Score100sPlus, stats.Score140sPlus,
-				stats.Score180s, stats.AccuracyStatistics.Accuracy20, stats.AccuracyStatistics.Accuracy19, stats.AccuracyStatistics.AccuracyOverall)
-			if err != nil {
-				tx.Rollback()
-				return err
-			}
-			log.Printf("[%d] Inserting x01 statistics for player %d", visit.LegID, playerID)
-		}
+	err = rules.InsertStatistics(tx, leg, visit)
+	if err != nil {
+		tx.Rollback()
+		return err
 	}
 
 	// Check if match is finished or not
@@ -423,11 +152,39 @@ func FinishLeg(visit models.Visit) error {
 		}
 	}
 
+	winsRequired := match.MatchMode.WinsRequired
+	nextLegPlayers := leg.Players
+	if match.MatchMode.HasSets() {
+		// Include the winner of this leg, since it is not yet committed
+		winners := make([]null.Int, len(match.Legs))
+		for i, l := range match.Legs {
+			winners[i] = l.WinnerPlayerID
+			if l.ID == leg.ID {
+				winners[i] = winnerID
+			}
+		}
+		match.SetSets(winners)
+		currentPlayerWins = match.SetsWon[int(winnerID.ValueOrZero())]
+		winsRequired = int(match.MatchMode.SetsRequired.Int64)
+
+		set := match.Sets[len(match.Sets)-1]
+		setNumber, legNumber := set.Set, set.Legs
+		if set.WinnerID.Valid {
+			log.Printf("Set %d of match %d won by player %d", set.Set, match.ID, set.WinnerID.Int64)
+			setNumber, legNumber = setNumber+1, 0
+		}
+		// NewLeg will shift the players once, so pass the order which will give the correct starting player
+		start := models.GetStartingPlayerIndex(setNumber, legNumber, len(match.Legs[0].Players))
+		nextLegPlayers = getPlayerOrder(match.Legs[0].Players, start-1)
+	}
+
 	isFinished := false
 	isTieBreak := false
-	if currentPlayerWins == match.MatchMode.WinsRequired {
+	matchWinnerID := null.IntFromPtr(nil)
+	if currentPlayerWins == winsRequired {
 		// Match finished, current player won
 		isFinished = true
+		matchWinnerID = winnerID
 		_, err = tx.Exec("UPDATE matches SET is_finished = 1, winner_id = ? WHERE id = ?", winnerID, match.ID)
 		if err != nil {
 			tx.Rollback()
@@ -451,7 +208,7 @@ func FinishLeg(visit models.Visit) error {
 			}
 		}
 		log.Printf("Match %d finished with player %d winning", match.ID, winnerID.ValueOrZero())
-	} else if match.MatchMode.LegsRequired.Valid && playedLegs == int(match.MatchMode.LegsRequired.Int64) {
+	} else if !match.MatchMode.HasSets() && match.MatchMode.LegsRequired.Valid && playedLegs == int(match.MatchMode.LegsRequired.Int64) {
 		// Match finished, draw
 		isFinished = true
 		_, err = tx.Exec("UPDATE matches SET is_finished = 1 WHERE id = ?", match.ID)
@@ -460,11 +217,23 @@ func FinishLeg(visit models.Visit) error {
 			return err
 		}
 		log.Printf("Match %d finished with a Draw", match.ID)
-	} else if playedLegs == (int(match.MatchMode.LegsRequired.Int64)-1) && match.MatchMode.TieBreakMatchTypeID.Valid {
+	} else if !match.MatchMode.HasSets() && playedLegs == (int(match.MatchMode.LegsRequired.Int64)-1) && match.MatchMode.TieBreakMatchTypeID.Valid {
 		isTieBreak = true
 	}
 	tx.Commit()
 
+	models.Events.Publish(newLegEvent(models.EVENTLEGFINISHED, leg.ID, match, models.LegFinishedEvent{WinnerID: winnerID, MatchTypeID: matchType, IsMatchFinished: isFinished}))
+	if (matchType == models.X01 || matchType == models.X01HANDICAP) && winnerID.Valid {
+		publishHighestCheckout(leg.ID, match, int(winnerID.Int64), visit.GetScore())
+	}
+	if isFinished {
+		models.Events.Publish(newLegEvent(models.EVENTMATCHFINISHED, leg.ID, match, models.MatchFinishedEvent{WinnerID: matchWinnerID}))
+		if match.TournamentID.Valid {
+			models.Events.Publish(newLegEvent(models.EVENTTOURNAMENTMATCHDECIDED, leg.ID, match,
+				models.TournamentMatchEvent{TournamentID: int(match.TournamentID.Int64), WinnerID: matchWinnerID}))
+		}
+	}
+
 	if isFinished {
 		// Update Elo for players if match is finished
 		err = UpdateEloForMatch(match.ID)
@@ -515,7 +284,7 @@ func FinishLeg(visit models.Visit) error {
 			matchType = new(int)
 			*matchType = int(match.MatchMode.TieBreakMatchTypeID.Int64)
 		}
-		_, err = NewLeg(match.ID, leg.StartingScore, leg.Players, matchType)
+		_, err = NewLeg(match.ID, leg.StartingScore, nextLegPlayers, matchType)
 		if err != nil {
 			return err
 		}
@@ -523,8 +292,25 @@ func FinishLeg(visit models.Visit) error {
 	return nil
 }
 
+// getPlayerOrder will return the given players rotated, so that the player at the given index is first
+func getPlayerOrder(players []int, start int) []int {
+	start = ((start % len(players)) + len(players)) % len(players)
+	order := make([]int, 0, len(players))
+	order = append(order, players[start:]...)
+	return append(order, players[:start]...)
+}
+
 // UndoLegFinish will undo a finalized leg
 func UndoLegFinish(legID int) error {
+	matchType, err := GetLegMatchType(legID)
+	if err != nil {
+		return err
+	}
+	rules, err := models.GetGameRules(*matchType)
+	if err != nil {
+		return err
+	}
+
 	tx, err := models.DB.Begin()
 	if err != nil {
 		return err
@@ -543,62 +329,7 @@ func UndoLegFinish(legID int) error {
 		return err
 	}
 	// Remove generated statistics for the leg
-	_, err = tx.Exec("DELETE FROM statistics_x01 WHERE leg_id = ?", legID)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	_, err = tx.Exec("DELETE FROM statistics_shootout WHERE leg_id = ?", legID)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	_, err = tx.Exec("DELETE FROM statistics_cricket WHERE leg_id = ?", legID)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	_, err = tx.Exec("DELETE FROM statistics_darts_at_x WHERE leg_id = ?", legID)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	_, err = tx.Exec("DELETE FROM statistics_around_the WHERE leg_id = ?", legID)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	_, err = tx.Exec("DELETE FROM statistics_tic_tac_toe WHERE leg_id = ?", legID)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	_, err = tx.Exec("DELETE FROM statistics_bermuda_triangle WHERE leg_id = ?", legID)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	_, err = tx.Exec("DELETE FROM statistics_420 WHERE leg_id = ?", legID)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	_, err = tx.Exec("DELETE FROM statistics_kill_bull WHERE leg_id = ?", legID)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	_, err = tx.Exec("DELETE FROM statistics_gotcha WHERE leg_id = ?", legID)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	_, err = tx.Exec("DELETE FROM statistics_jdc_practice WHERE leg_id = ?", legID)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	_, err = tx.Exec("DELETE FROM statistics_knockout WHERE leg_id = ?", legID)
+	err = rules.DeleteStatistics(tx, legID)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -630,6 +361,7 @@ func UndoLegFinish(legID int) error {
 
 	tx.Commit()
 	log.Printf("[%d] Undo finish of leg", legID)
+	publishLegEvent(models.EVENTLEGFINISHUNDONE, legID, nil)
 	return nil
 }
 
@@ -671,12 +403,13 @@ func GetLegsForMatch(matchID int) ([]*models.Leg, error) {
 		}
 		leg.Visits = visits
 
-		matchType := leg.LegType.ID
-		if matchType == models.TICTACTOE || matchType == models.KNOCKOUT {
-			leg.Parameters, err = GetLegParameters(leg.ID)
-			if err != nil {
-				return nil, err
-			}
+		rules, err := models.GetGameRules(leg.LegType.ID)
+		if err != nil {
+			return nil, err
+		}
+		leg.Parameters, err = rules.GetLegParameters(leg.ID)
+		if err != nil {
+			return nil, err
 		}
 		legs = append(legs, leg)
 	}
@@ -689,6 +422,10 @@ func GetLegsForMatch(matchID int) ([]*models.Leg, error) {
 
 // GetLegsOfType returns all legs with scores for the given match type
 func GetLegsOfType(matchType int, loadVisits bool) ([]*models.Leg, error) {
+	rules, err := models.GetGameRules(matchType)
+	if err != nil {
+		return nil, err
+	}
 	rows, err := models.DB.Query(`
 		SELECT
 			l.id, l.end_time, l.starting_score, l.is_finished,
@@ -722,11 +459,9 @@ func GetLegsOfType(matchType int, loadVisits bool) ([]*models.Leg, error) {
 			}
 			leg.Visits = visits
 		}
-		if matchType == models.TICTACTOE || matchType == models.KNOCKOUT {
-			leg.Parameters, err = GetLegParameters(leg.ID)
-			if err != nil {
-				return nil, err
-			}
+		leg.Parameters, err = rules.GetLegParameters(leg.ID)
+		if err != nil {
+			return nil, err
 		}
 		legs = append(legs, leg)
 	}
@@ -806,35 +541,24 @@ func GetLeg(id int) (*models.Leg, error) {
 	}
 
 	matchType := leg.LegType.ID
-	if matchType == models.TICTACTOE || matchType == models.KNOCKOUT {
-		leg.Parameters, err = GetLegParameters(id)
-		if err != nil {
-			return nil, err
-		}
+	rules, err := models.GetGameRules(matchType)
+	if err != nil {
+		return nil, err
+	}
+	leg.Parameters, err = rules.GetLegParameters(id)
+	if err != nil {
+		return nil, err
 	}
 
 	scores := make(map[int]*models.Player2Leg)
-	for i := 0; i < len(leg.Players); i++ {
+	for _, playerID := range leg.Players {
 		p2l := new(models.Player2Leg)
+		p2l.PlayerID = playerID
+		p2l.StartingScore = leg.StartingScore
 		p2l.Hits = make(map[int]*models.Hits)
-		if matchType == models.DARTSATX || matchType == models.AROUNDTHECLOCK || matchType == models.AROUNDTHEWORLD || matchType == models.SHANGHAI ||
-			matchType == models.TICTACTOE || matchType == models.BERMUDATRIANGLE || matchType == models.GOTCHA || matchType == models.JDCPRACTICE ||
-			matchType == models.SHOOTOUT {
-			p2l.CurrentScore = 0
-		} else if matchType == models.KNOCKOUT {
-			p2l.CurrentScore = 0
-			p2l.Lives = null.IntFrom(leg.Parameters.StartingLives.Int64)
-		} else if matchType == models.FOURTWENTY {
-			p2l.CurrentScore = 420
-		} else if matchType == models.X01HANDICAP {
-			// TODO
-		} else {
-			p2l.CurrentScore = leg.StartingScore
-			p2l.StartingScore = leg.StartingScore
-		}
-		p2l.DartsThrown = 0
-		scores[leg.Players[i]] = p2l
+		scores[playerID] = p2l
 	}
+	rules.InitializeScores(leg.Parameters, scores)
 
 	specialNums := make([]int, 0)
 	if leg.Parameters != nil && leg.Parameters.Numbers != nil {
@@ -844,93 +568,14 @@ func GetLeg(id int) (*models.Leg, error) {
 
 	dartsThrown := 0
 	visitCount := 0
-	round := 1
 	for i, visit := range visits {
-		if i > 0 && i%len(leg.Players) == 0 {
-			round++
-		}
 		if visitCount%len(leg.Players) == 0 {
 			dartsThrown += 3
 		}
 		visit.DartsThrown = dartsThrown
 		visitCount++
 
-		if !visit.IsBust {
-			score := visit.GetScore()
-			if matchType == models.DARTSATX {
-				score = 0
-				if visit.FirstDart.ValueRaw() == leg.StartingScore {
-					score += int(visit.FirstDart.Multiplier)
-				}
-				if visit.SecondDart.ValueRaw() == leg.StartingScore {
-					score += int(visit.SecondDart.Multiplier)
-				}
-				if visit.ThirdDart.ValueRaw() == leg.StartingScore {
-					score += int(visit.ThirdDart.Multiplier)
-				}
-			}
-			if matchType == models.DARTSATX || matchType == models.SHOOTOUT {
-				scores[visit.PlayerID].CurrentScore += score
-			} else if matchType == models.CRICKET {
-				score = visit.CalculateCricketScore(scores)
-				scores[visit.PlayerID].CurrentScore += score
-			} else if matchType == models.AROUNDTHECLOCK {
-				score = visit.CalculateAroundTheClockScore(scores[visit.PlayerID].CurrentScore)
-				scores[visit.PlayerID].CurrentScore += score
-			} else if matchType == models.AROUNDTHEWORLD || matchType == models.SHANGHAI {
-				score = visit.CalculateAroundTheWorldScore(round)
-				scores[visit.PlayerID].CurrentScore += score
-			} else if matchType == models.TICTACTOE {
-				score = 0
-
-				lastDartValid := visit.GetLastDart().IsDouble()
-				if leg.Parameters.OutshotType.ID == models.OUTSHOTANY {
-					lastDartValid = true
-				} else if leg.Parameters.OutshotType.ID == models.OUTSHOTMASTER {
-					lastDartValid = visit.GetLastDart().IsDouble() || visit.GetLastDart().IsTriple()
-				}
-
-				for _, num := range leg.Parameters.Numbers {
-					if num == visit.GetScore() && lastDartValid {
-						score = num
-						break
-					}
-				}
-				scores[visit.PlayerID].CurrentScore += score
-			} else if matchType == models.BERMUDATRIANGLE {
-				score = visit.CalculateBermudaTriangleScore(round - 1)
-				if score == 0 {
-					scores[visit.PlayerID].CurrentScore = scores[visit.PlayerID].CurrentScore / 2
-				} else {
-					scores[visit.PlayerID].CurrentScore += score
-				}
-			} else if matchType == models.FOURTWENTY {
-				score = visit.Calculate420Score(round - 1)
-				scores[visit.PlayerID].CurrentScore -= score
-			} else if matchType == models.KILLBULL {
-				score = visit.CalculateKillBullScore()
-				if score == 0 {
-					scores[visit.PlayerID].CurrentScore = scores[visit.PlayerID].StartingScore
-				} else {
-					scores[visit.PlayerID].CurrentScore -= score
-				}
-			} else if matchType == models.GOTCHA {
-				score = visit.CalculateGotchaScore(scores, leg.StartingScore)
-				scores[visit.PlayerID].CurrentScore += score
-			} else if matchType == models.JDCPRACTICE {
-				score = visit.CalculateJDCPracticeScore(round - 1)
-				scores[visit.PlayerID].CurrentScore += score
-			} else if matchType == models.KNOCKOUT {
-				player := scores[visit.PlayerID]
-				player.CurrentScore = visit.GetScore()
-				// Set correctly darts thrown for each player
-				player.DartsThrown += 3
-				visit.DartsThrown = player.DartsThrown
-			} else {
-				scores[visit.PlayerID].CurrentScore -= score
-			}
-			visit.Score = score
-		}
+		visit.Score = rules.CalculateScore(leg.Parameters, scores, visits[:i], visit)
 
 		visit.Scores = make(map[int]int)
 		visit.Scores[visit.PlayerID] = scores[visit.PlayerID].CurrentScore
@@ -953,12 +598,7 @@ func GetLeg(id int) (*models.Leg, error) {
 		// We also want to add hits for certain special numbers in some game types
 		for j, num := range specialNums {
 			// Check if we hit the exact number, ending with a double
-			lastDartValid := visit.GetLastDart().IsDouble()
-			if leg.Parameters.OutshotType.ID == models.OUTSHOTANY {
-				lastDartValid = true
-			} else if leg.Parameters.OutshotType.ID == models.OUTSHOTMASTER {
-				lastDartValid = visit.GetLastDart().IsDouble() || visit.GetLastDart().IsTriple()
-			}
+			lastDartValid := visit.GetLastDart().IsValidOutshot(leg.Parameters.OutshotType.ID)
 			if num == visit.GetScore() && lastDartValid {
 				leg.Parameters.Hits[num] = visit.PlayerID
 
@@ -980,7 +620,7 @@ func GetLeg(id int) (*models.Leg, error) {
 	leg.Visits = visits
 	leg.Hits, leg.DartsThrown = models.GetHitsMap(visits)
 	if matchType == models.X01 || matchType == models.X01HANDICAP {
-		leg.CheckoutStatistics, err = getCheckoutStatistics(leg.ID, leg.StartingScore)
+		leg.CheckoutStatistics, err = getCheckoutStatistics(leg.ID, leg.StartingScore, leg.GetOutshotTypeID(), leg.GetInshotTypeID())
 	}
 	if err != nil {
 		return nil, err
@@ -1087,6 +727,7 @@ func ChangePlayerOrder(legID int, orderMap map[string]int) error {
 	tx.Commit()
 
 	log.Printf("[%d] Changed player order to %v", legID, orderMap)
+	publishLegEvent(models.EVENTPLAYERORDERCHANGED, legID, orderMap)
 
 	return nil
 }
@@ -1105,6 +746,7 @@ func StartWarmup(legID int) error {
 	tx.Commit()
 
 	log.Printf("[%d] Started warmup", legID)
+	publishLegEvent(models.EVENTWARMUPSTARTED, legID, nil)
 	return nil
 }
 
@@ -1151,10 +793,12 @@ func DeleteLeg(legID int) error {
 func GetLegParameters(legID int) (*models.LegParameters, error) {
 	params := new(models.LegParameters)
 	n := make([]null.Int, 9)
-	var ost null.Int
+	var ost, ist null.Int
 	err := models.DB.QueryRow(`
-		SELECT outshot_type_id, number_1, number_2, number_3, number_4, number_5, number_6, number_7, number_8, number_9, starting_lives
-		FROM leg_parameters WHERE leg_id = ?`, legID).Scan(&ost, &n[0], &n[1], &n[2], &n[3], &n[4], &n[5], &n[6], &n[7], &n[8], &params.StartingLives)
+		SELECT outshot_type_id, inshot_type_id, number_1, number_2, number_3, number_4, number_5, number_6, number_7, number_8, number_9, starting_lives, holes, rounds,
+			game_definition_id, darts, target_multiplier, starting_score
+		FROM leg_parameters WHERE leg_id = ?`, legID).Scan(&ost, &ist, &n[0], &n[1], &n[2], &n[3], &n[4], &n[5], &n[6], &n[7], &n[8], &params.StartingLives, &params.Holes,
+		&params.Rounds, &params.GameDefinitionID, &params.Darts, &params.TargetMultiplier, &params.StartingScore)
 	if err != nil {
 		return nil, err
 	}
@@ -1165,6 +809,13 @@ func GetLegParameters(legID int) (*models.LegParameters, error) {
 		}
 		params.OutshotType = os
 	}
+	if ist.Valid {
+		is, err := GetOutshotType(int(ist.Int64))
+		if err != nil {
+			return nil, err
+		}
+		params.InshotType = is
+	}
 	if n[0].Valid {
 		numbers := make([]int, 9)
 		for i, num := range n {
@@ -1172,10 +823,137 @@ func GetLegParameters(legID int) (*models.LegParameters, error) {
 		}
 		params.Numbers = numbers
 	}
+	if params.GameDefinitionID.Valid {
+		def, err := GetGameDefinition(int(params.GameDefinitionID.Int64))
+		if err != nil {
+			return nil, err
+		}
+		params.GameDefinition = def
+	}
 	params.Hits = make(map[int]int)
+
+	rows, err := models.DB.Query("SELECT player_id, number FROM leg_parameters_killer WHERE leg_id = ?", legID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var playerID, number int
+		err := rows.Scan(&playerID, &number)
+		if err != nil {
+			return nil, err
+		}
+		if params.PlayerNumbers == nil {
+			params.PlayerNumbers = make(map[int]int)
+		}
+		params.PlayerNumbers[playerID] = number
+	}
+
+	targets, err := getTargets("SELECT leg_id, value, target_values, multipliers, score FROM game_target WHERE leg_id = ? ORDER BY round", legID)
+	if err != nil {
+		return nil, err
+	}
+	params.Targets = targets[legID]
 	return params, nil
 }
 
+// insertHalveItLegParameters will write the target of each round of the given Halve-It leg
+func insertHalveItLegParameters(tx *sql.Tx, legID int64, params *models.LegParameters) error {
+	_, err := tx.Exec("INSERT INTO leg_parameters (leg_id) VALUES (?)", legID)
+	if err != nil {
+		return err
+	}
+	return insertTargets(tx, null.IntFromPtr(nil), null.IntFrom(legID), params.GetHalveItTargets())
+}
+
+// insertKillerLegParameters will write the starting lives and a randomly assigned number for each player of the given Killer leg
+func insertKillerLegParameters(tx *sql.Tx, legID int64, params *models.LegParameters, players []int) error {
+	lives := null.IntFrom(3)
+	if params != nil && params.StartingLives.Valid {
+		lives = params.StartingLives
+	}
+	_, err := tx.Exec("INSERT INTO leg_parameters (leg_id, starting_lives) VALUES (?, ?)", legID, lives)
+	if err != nil {
+		return err
+	}
+	numbers := new(models.LegParameters)
+	err = numbers.GenerateKillerNumbers(players)
+	if err != nil {
+		return err
+	}
+	for _, playerID := range players {
+		_, err = tx.Exec("INSERT INTO leg_parameters_killer (leg_id, player_id, number) VALUES (?, ?, ?)", legID, playerID, numbers.PlayerNumbers[playerID])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertX01LegParameters will write the outshot and inshot type of the given X01 leg, if any are set
+func insertX01LegParameters(tx *sql.Tx, legID int64, params *models.LegParameters) error {
+	if params == nil || (params.OutshotType == nil && params.InshotType == nil) {
+		return nil
+	}
+	outshotTypeID := null.IntFromPtr(nil)
+	if params.OutshotType != nil {
+		outshotTypeID = null.IntFrom(int64(params.OutshotType.ID))
+	}
+	inshotTypeID := null.IntFromPtr(nil)
+	if params.InshotType != nil {
+		inshotTypeID = null.IntFrom(int64(params.InshotType.ID))
+	}
+	_, err := tx.Exec("INSERT INTO leg_parameters (leg_id, outshot_type_id, inshot_type_id) VALUES (?, ?, ?)", legID, outshotTypeID, inshotTypeID)
+	return err
+}
+
+// insertVariantLegParameters will write the variant settings (rounds, darts and target multiplier) of the given leg, if any are set
+func insertVariantLegParameters(tx *sql.Tx, legID int64, params *models.LegParameters) error {
+	if params == nil || (!params.Rounds.Valid && !params.Darts.Valid && !params.TargetMultiplier.Valid) {
+		return nil
+	}
+	_, err := tx.Exec("INSERT INTO leg_parameters (leg_id, rounds, darts, target_multiplier) VALUES (?, ?, ?, ?)",
+		legID, params.Rounds, params.Darts, params.TargetMultiplier)
+	return err
+}
+
+// insertKillBullLegParameters will write the starting score of the given Kill Bull leg, if set
+func insertKillBullLegParameters(tx *sql.Tx, legID int64, params *models.LegParameters) error {
+	if params == nil || !params.StartingScore.Valid {
+		return nil
+	}
+	_, err := tx.Exec("INSERT INTO leg_parameters (leg_id, starting_score) VALUES (?, ?)", legID, params.StartingScore)
+	return err
+}
+
+// insertCustomLegParameters will insert the game definition played in the given custom leg
+func insertCustomLegParameters(tx *sql.Tx, legID int64, params *models.LegParameters) error {
+	if params == nil || !params.GameDefinitionID.Valid {
+		return errors.New("game_definition_id is required for custom games")
+	}
+	_, err := tx.Exec("INSERT INTO leg_parameters (leg_id, game_definition_id) VALUES (?, ?)", legID, params.GameDefinitionID)
+	return err
+}
+
+// insertCheckout121LegParameters will insert the outshot type and number of rounds for the given Checkout 121 leg
+func insertCheckout121LegParameters(tx *sql.Tx, legID int64, params *models.LegParameters) error {
+	outshotTypeID := null.IntFromPtr(nil)
+	if params != nil && params.OutshotType != nil {
+		outshotTypeID = null.IntFrom(int64(params.OutshotType.ID))
+	}
+	_, err := tx.Exec("INSERT INTO leg_parameters (leg_id, outshot_type_id, rounds) VALUES (?, ?, ?)", legID, outshotTypeID, params.GetCheckout121Rounds())
+	return err
+}
+
+// getOptionalLegParameters will return leg parameters for the given leg, or nil if the leg has no parameters
+func getOptionalLegParameters(legID int) (*models.LegParameters, error) {
+	params, err := GetLegParameters(legID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return params, err
+}
+
 // GetLegMatchType returns the match type for a given leg
 func GetLegMatchType(legID int) (*int, error) {
 	var matchType int
@@ -1192,7 +970,7 @@ func GetLegMatchType(legID int) (*int, error) {
 }
 
 // getCheckoutStatistics will get all checkout attempts for the given leg
-func getCheckoutStatistics(legID int, startingScore int) (*models.CheckoutStatistics, error) {
+func getCheckoutStatistics(legID int, startingScore int, outshotType int, inshotType int) (*models.CheckoutStatistics, error) {
 	visits, err := GetLegVisits(legID)
 	if err != nil {
 		return nil, err
@@ -1214,23 +992,29 @@ func getCheckoutStatistics(legID int, startingScore int) (*models.CheckoutStatis
 
 	totalAttempts := 0
 	checkoutAttempts := make(map[int]int)
+	isIn := make(map[int]bool)
 	for _, visit := range visits {
 		player := playersMap[visit.PlayerID]
 
 		currentScore := player.CurrentScore
-		if visit.FirstDart.IsCheckoutAttempt(currentScore, 1) {
+		if inshotType != models.OUTSHOTANY && !isIn[visit.PlayerID] {
+			// Darts thrown before the player is in do not score
+			currentScore += visit.GetScoreBeforeIn(inshotType)
+			isIn[visit.PlayerID] = models.GetDartsToGetIn([]*models.Visit{visit}, visit.PlayerID, inshotType).Valid
+		}
+		if visit.FirstDart.IsCheckoutAttempt(currentScore, 1, outshotType) {
 			totalAttempts++
 			checkoutAttempts[currentScore]++
 		}
 		currentScore -= visit.FirstDart.GetScore()
 
-		if visit.SecondDart.IsCheckoutAttempt(currentScore, 2) {
+		if visit.SecondDart.IsCheckoutAttempt(currentScore, 2, outshotType) {
 			totalAttempts++
 			checkoutAttempts[currentScore]++
 		}
 		currentScore -= visit.SecondDart.GetScore()
 
-		if visit.ThirdDart.IsCheckoutAttempt(currentScore, 3) {
+		if visit.ThirdDart.IsCheckoutAttempt(currentScore, 3, outshotType) {
 			totalAttempts++
 			checkoutAttempts[currentScore]++
 		}
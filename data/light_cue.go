@@ -0,0 +1,113 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// lightCueClient is the client used to send commands to LED controllers, which are expected to be on the local network
+var lightCueClient = &http.Client{Timeout: 2 * time.Second}
+
+// StartLightCues will start turning game events at venues with LED lights into light cues, which are published as
+// events and sent to the LED controller of the venue
+func StartLightCues(config models.LEDConfig) {
+	filter := models.EventFilter{Types: []string{models.EVENTVISITADDED, models.EVENTONEEIGHTY, models.EVENTLEGSTARTED, models.EVENTLEGFINISHED,
+		models.EVENTLEGFINISHUNDONE, models.EVENTMATCHFINISHED, models.EVENTPLAYERORDERCHANGED}}
+	go consumeEvents(filter, func(event *models.Event) {
+		err := handleLightCues(config, event)
+		if err != nil {
+			log.Printf("Unable to handle light cues for event %d: %s", event.Sequence, err)
+		}
+	})
+}
+
+// handleLightCues will publish and send the light cues triggered by the given event
+func handleLightCues(config models.LEDConfig, event *models.Event) error {
+	if !event.VenueID.Valid {
+		return nil
+	}
+	venue, err := GetVenueConfiguration(int(event.VenueID.Int64))
+	if err != nil {
+		return err
+	}
+	if !venue.HasLEDLights {
+		return nil
+	}
+
+	cues := make([]*models.LightCue, 0)
+	for _, cue := range models.GetLightCues(event) {
+		lightCue := &models.LightCue{Cue: cue, VenueID: venue.VenueID, LegID: event.LegID, Command: config.GetCommand(cue, null.StringFromPtr(nil))}
+		switch data := event.Data.(type) {
+		case models.Visit:
+			lightCue.PlayerID = null.IntFrom(int64(data.PlayerID))
+		case models.LegFinishedEvent:
+			lightCue.PlayerID = data.WinnerID
+		case models.MatchFinishedEvent:
+			lightCue.PlayerID = data.WinnerID
+		}
+		cues = append(cues, lightCue)
+	}
+	if event.LegID.Valid && (event.Type == models.EVENTVISITADDED || event.Type == models.EVENTLEGSTARTED || event.Type == models.EVENTPLAYERORDERCHANGED ||
+		event.Type == models.EVENTLEGFINISHUNDONE) {
+		cue, err := getPlayerTurnCue(config, venue.VenueID, int(event.LegID.Int64))
+		if err != nil {
+			return err
+		}
+		if cue != nil {
+			cues = append(cues, cue)
+		}
+	}
+
+	for _, cue := range cues {
+		models.Events.Publish(models.Event{Type: models.EVENTLIGHTCUE, LegID: event.LegID, MatchID: event.MatchID,
+			VenueID: event.VenueID, OfficeID: event.OfficeID, Data: cue})
+		if venue.LEDControllerURL.Valid && venue.LEDControllerURL.String != "" {
+			err = sendLightCue(venue.LEDControllerURL.String, cue)
+			if err != nil {
+				log.Printf("[%d] Unable to send %s light cue to venue %d: %s", cue.LegID.Int64, cue.Cue, cue.VenueID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// getPlayerTurnCue will return the cue showing the colour of the current player of the given leg, or nil if the leg is finished
+func getPlayerTurnCue(config models.LEDConfig, venueID int, legID int) (*models.LightCue, error) {
+	leg, err := GetLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+	if leg.IsFinished {
+		return nil, nil
+	}
+	player, err := GetPlayer(leg.CurrentPlayerID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.LightCue{Cue: models.LIGHTCUEPLAYERTURN, VenueID: venueID, LegID: null.IntFrom(int64(legID)),
+		PlayerID: null.IntFrom(int64(player.ID)), Command: config.GetCommand(models.LIGHTCUEPLAYERTURN, player.Color)}, nil
+}
+
+// sendLightCue will post the given cue as JSON to the given LED controller
+func sendLightCue(url string, cue *models.LightCue) error {
+	payload, err := json.Marshal(cue)
+	if err != nil {
+		return err
+	}
+	resp, err := lightCueClient.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response %s", resp.Status)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package data
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
 
 	"github.com/guregu/null"
@@ -11,6 +12,19 @@ import (
 
 // NewMatch will insert a new match in the database
 func NewMatch(match models.Match) (*models.Match, error) {
+	rules, err := models.GetGameRules(match.MatchType.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", models.ErrInvalidInput, err)
+	}
+	err = rules.ValidateLegParameters(&models.Leg{StartingScore: match.Legs[0].StartingScore, Players: match.Players, Parameters: match.Legs[0].Parameters})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", models.ErrInvalidInput, err)
+	}
+	err = match.ValidateTeams()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", models.ErrInvalidInput, err)
+	}
+
 	tx, err := models.DB.Begin()
 	if err != nil {
 		return nil, err
@@ -37,22 +51,16 @@ func NewMatch(match models.Match) (*models.Match, error) {
 		tx.Rollback()
 		return nil, err
 	}
-	if match.MatchType.ID == models.TICTACTOE {
-		params := match.Legs[0].Parameters
-		params.GenerateTicTacToeNumbers(startingScore)
-		_, err = tx.Exec("INSERT INTO leg_parameters (leg_id, outshot_type_id, number_1, number_2, number_3, number_4, number_5, number_6, number_7, number_8, number_9) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
-			legID, params.OutshotType.ID, params.Numbers[0], params.Numbers[1], params.Numbers[2], params.Numbers[3], params.Numbers[4], params.Numbers[5], params.Numbers[6], params.Numbers[7], params.Numbers[8])
-		if err != nil {
-			tx.Rollback()
-			return nil, err
-		}
-	} else if match.MatchType.ID == models.KNOCKOUT {
-		params := match.Legs[0].Parameters
-		_, err = tx.Exec("INSERT INTO leg_parameters (leg_id, starting_lives) VALUES (?, ?)", legID, params.StartingLives)
-		if err != nil {
-			tx.Rollback()
-			return nil, err
-		}
+	err = rules.InsertLegParameters(tx, &models.Leg{ID: int(legID), StartingScore: startingScore, Players: match.Players, Parameters: match.Legs[0].Parameters})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = insertTeams(tx, match, matchID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
 	}
 
 	tx.Exec("UPDATE matches SET current_leg_id = ? WHERE id = ?", legID, matchID)
@@ -80,6 +88,7 @@ func NewMatch(match models.Match) (*models.Match, error) {
 	}
 	tx.Commit()
 	log.Printf("Started new match %d", matchID)
+	publishLegEvent(models.EVENTLEGSTARTED, int(legID), nil)
 	return GetMatch(int(matchID))
 }
 
@@ -89,7 +98,7 @@ func GetMatches() ([]*models.Match, error) {
 		SELECT
 			m.id, m.is_finished, m.is_abandoned, m.is_walkover, m.current_leg_id, m.winner_id, m.office_id, m.is_practice,
 			m.created_at, m.updated_at, m.owe_type_id, m.venue_id, mt.id, mt.name, mt.description, mm.id, mm.name, mm.short_name,
-			mm.wins_required, mm.legs_required, ot.id, ot.item, v.id, v.name, v.description, l.updated_at as 'last_throw',
+			mm.wins_required, mm.legs_required, mm.sets_required, ot.id, ot.item, v.id, v.name, v.description, l.updated_at as 'last_throw',
 			GROUP_CONCAT(DISTINCT p2l.player_id ORDER BY p2l.order) AS 'players'
 		FROM matches m
 			JOIN match_type mt ON mt.id = m.match_type_id
@@ -115,7 +124,7 @@ func GetMatches() ([]*models.Match, error) {
 		var players string
 		err := rows.Scan(&m.ID, &m.IsFinished, &m.IsAbandoned, &m.IsWalkover, &m.CurrentLegID, &m.WinnerID, &m.OfficeID, &m.IsPractice, &m.CreatedAt, &m.UpdatedAt,
 			&m.OweTypeID, &m.VenueID, &m.MatchType.ID, &m.MatchType.Name, &m.MatchType.Description,
-			&m.MatchMode.ID, &m.MatchMode.Name, &m.MatchMode.ShortName, &m.MatchMode.WinsRequired, &m.MatchMode.LegsRequired,
+			&m.MatchMode.ID, &m.MatchMode.Name, &m.MatchMode.ShortName, &m.MatchMode.WinsRequired, &m.MatchMode.LegsRequired, &m.MatchMode.SetsRequired,
 			&ot.ID, &ot.Item, &venue.ID, &venue.Name, &venue.Description, &m.LastThrow, &players)
 		if err != nil {
 			return nil, err
@@ -143,7 +152,7 @@ func GetActiveMatches() ([]*models.Match, error) {
 		SELECT
 			m.id, m.is_finished, m.is_abandoned, m.is_walkover, m.current_leg_id, m.winner_id, m.office_id, m.is_practice,
 			m.created_at, m.updated_at, m.owe_type_id, m.venue_id, mt.id, mt.name, mt.description, mm.id, mm.name, mm.short_name,
-			mm.wins_required, mm.legs_required, ot.id, ot.item, v.id, v.name, v.description, l.updated_at as 'last_throw',
+			mm.wins_required, mm.legs_required, mm.sets_required, ot.id, ot.item, v.id, v.name, v.description, l.updated_at as 'last_throw',
 			GROUP_CONCAT(DISTINCT p2l.player_id ORDER BY p2l.order) AS 'players'
 		FROM matches m
 			JOIN match_type mt ON mt.id = m.match_type_id
@@ -171,7 +180,7 @@ func GetActiveMatches() ([]*models.Match, error) {
 		var players string
 		err := rows.Scan(&m.ID, &m.IsFinished, &m.IsAbandoned, &m.IsWalkover, &m.CurrentLegID, &m.WinnerID, &m.OfficeID, &m.IsPractice,
 			&m.CreatedAt, &m.UpdatedAt, &m.OweTypeID, &m.VenueID, &m.MatchType.ID, &m.MatchType.Name, &m.MatchType.Description,
-			&m.MatchMode.ID, &m.MatchMode.Name, &m.MatchMode.ShortName, &m.MatchMode.WinsRequired, &m.MatchMode.LegsRequired,
+			&m.MatchMode.ID, &m.MatchMode.Name, &m.MatchMode.ShortName, &m.MatchMode.WinsRequired, &m.MatchMode.LegsRequired, &m.MatchMode.SetsRequired,
 			&ot.ID, &ot.Item, &venue.ID, &venue.Name, &venue.Description, &m.LastThrow, &players)
 		if err != nil {
 			return nil, err
@@ -199,9 +208,10 @@ func GetMatchesLimit(start int, limit int) ([]*models.Match, error) {
 		SELECT
 			m.id, m.is_finished, m.is_abandoned, m.is_walkover, m.current_leg_id, m.winner_id, m.office_id, m.is_practice,
 			m.created_at, m.updated_at, m.owe_type_id, m.venue_id, mt.id, mt.name, mt.description, mm.id, mm.name, mm.short_name,
-			mm.wins_required, mm.legs_required, ot.id, ot.item, v.id, v.name, v.description,
+			mm.wins_required, mm.legs_required, mm.sets_required, ot.id, ot.item, v.id, v.name, v.description,
 			l.updated_at as 'last_throw', GROUP_CONCAT(DISTINCT p2l.player_id ORDER BY p2l.order) AS 'players',
-			m.tournament_id, t.id, t.name, tg.id, tg.name, GROUP_CONCAT(legs.winner_id ORDER BY legs.id) AS 'legs_won'
+			m.tournament_id, t.id, t.name, tg.id, tg.name, GROUP_CONCAT(legs.winner_id ORDER BY legs.id) AS 'legs_won',
+			(SELECT GROUP_CONCAT(IFNULL(ml.winner_id, 0) ORDER BY ml.id) FROM leg ml WHERE ml.match_id = m.id) AS 'leg_winners'
 		FROM matches m
 			JOIN match_type mt ON mt.id = m.match_type_id
 			JOIN match_mode mm ON mm.id = m.match_mode_id
@@ -232,11 +242,12 @@ func GetMatchesLimit(start int, limit int) ([]*models.Match, error) {
 		tournament := new(models.MatchTournament)
 		var players string
 		var legsWon null.String
+		var legWinners null.String
 		err := rows.Scan(&m.ID, &m.IsFinished, &m.IsAbandoned, &m.IsWalkover, &m.CurrentLegID, &m.WinnerID, &m.OfficeID, &m.IsPractice,
 			&m.CreatedAt, &m.UpdatedAt, &m.OweTypeID, &m.VenueID, &m.MatchType.ID, &m.MatchType.Name, &m.MatchType.Description,
-			&m.MatchMode.ID, &m.MatchMode.Name, &m.MatchMode.ShortName, &m.MatchMode.WinsRequired, &m.MatchMode.LegsRequired,
+			&m.MatchMode.ID, &m.MatchMode.Name, &m.MatchMode.ShortName, &m.MatchMode.WinsRequired, &m.MatchMode.LegsRequired, &m.MatchMode.SetsRequired,
 			&ot.ID, &ot.Item, &venue.ID, &venue.Name, &venue.Description, &m.LastThrow, &players, &m.TournamentID, &tournament.TournamentID,
-			&tournament.TournamentName, &tournament.TournamentGroupID, &tournament.TournamentGroupName, &legsWon)
+			&tournament.TournamentName, &tournament.TournamentGroupID, &tournament.TournamentGroupName, &legsWon, &legWinners)
 		if err != nil {
 			return nil, err
 		}
@@ -253,6 +264,10 @@ func GetMatchesLimit(start int, limit int) ([]*models.Match, error) {
 		if legsWon.Valid {
 			m.LegsWon = util.StringToIntArray(legsWon.String)
 		}
+		if legWinners.Valid {
+			// Legs won only contains legs with a winner, so drawn legs are counted from the winner of all legs
+			m.SetSets(models.GetLegWinners(util.StringToIntArray(legWinners.String)))
+		}
 		matches = append(matches, m)
 	}
 	if err = rows.Err(); err != nil {
@@ -275,7 +290,7 @@ func GetMatch(id int) (*models.Match, error) {
         SELECT
 			m.id, m.is_finished, m.is_abandoned, m.is_walkover, m.current_leg_id, m.winner_id, m.office_id, m.is_practice, m.created_at, m.updated_at,
 			m.owe_type_id, m.venue_id, mt.id, mt.name, mt.description, mm.id, mm.name, mm.short_name, mm.wins_required,
-			mm.legs_required, mm.tiebreak_match_type_id, ot.id, ot.item, v.id, v.name, v.description,
+			mm.legs_required, mm.sets_required, mm.tiebreak_match_type_id, ot.id, ot.item, v.id, v.name, v.description,
 			MAX(l.updated_at) AS 'last_throw',
 			MIN(s.created_at) AS 'first_throw',
 			GROUP_CONCAT(DISTINCT p2l.player_id ORDER BY p2l.order) AS 'players',
@@ -293,7 +308,7 @@ func GetMatch(id int) (*models.Match, error) {
 			LEFT JOIN tournament_group tg ON tg.id = p2t.tournament_group_id
 		WHERE m.id = ?`, id).Scan(&m.ID, &m.IsFinished, &m.IsAbandoned, &m.IsWalkover, &m.CurrentLegID, &m.WinnerID, &m.OfficeID, &m.IsPractice,
 		&m.CreatedAt, &m.UpdatedAt, &m.OweTypeID, &m.VenueID, &m.MatchType.ID, &m.MatchType.Name, &m.MatchType.Description,
-		&m.MatchMode.ID, &m.MatchMode.Name, &m.MatchMode.ShortName, &m.MatchMode.WinsRequired, &m.MatchMode.LegsRequired, &m.MatchMode.TieBreakMatchTypeID,
+		&m.MatchMode.ID, &m.MatchMode.Name, &m.MatchMode.ShortName, &m.MatchMode.WinsRequired, &m.MatchMode.LegsRequired, &m.MatchMode.SetsRequired, &m.MatchMode.TieBreakMatchTypeID,
 		&ot.ID, &ot.Item, &venue.ID, &venue.Name, &venue.Description, &m.LastThrow, &m.FirstThrow, &players, &m.TournamentID, &tournament.TournamentID,
 		&tournament.TournamentName, &tournament.OfficeID, &tournament.TournamentGroupID, &tournament.TournamentGroupName)
 	if err != nil {
@@ -312,10 +327,19 @@ func GetMatch(id int) (*models.Match, error) {
 		m.Tournament = tournament
 	}
 	m.Players = util.StringToIntArray(players)
+	m.Teams, err = GetTeams(id)
+	if err != nil {
+		return nil, err
+	}
 	m.Legs, err = GetLegsForMatch(id)
 	if err != nil {
 		return nil, err
 	}
+	winners := make([]null.Int, len(m.Legs))
+	for i, leg := range m.Legs {
+		winners[i] = leg.WinnerPlayerID
+	}
+	m.SetSets(winners)
 	if m.IsFinished {
 		m.EndTime = m.Legs[len(m.Legs)-1].Endtime.String
 	}
@@ -412,7 +436,7 @@ func DeleteMatch(id int) (*models.Match, error) {
 
 // GetMatchModes will return all match modes
 func GetMatchModes() ([]*models.MatchMode, error) {
-	rows, err := models.DB.Query("SELECT id, wins_required, legs_required, tiebreak_match_type_id, `name`, short_name FROM match_mode ORDER BY wins_required")
+	rows, err := models.DB.Query("SELECT id, wins_required, legs_required, sets_required, tiebreak_match_type_id, `name`, short_name FROM match_mode ORDER BY sets_required, wins_required")
 	if err != nil {
 		return nil, err
 	}
@@ -421,7 +445,7 @@ func GetMatchModes() ([]*models.MatchMode, error) {
 	modes := make([]*models.MatchMode, 0)
 	for rows.Next() {
 		mm := new(models.MatchMode)
-		err := rows.Scan(&mm.ID, &mm.WinsRequired, &mm.LegsRequired, &mm.TieBreakMatchTypeID, &mm.Name, &mm.ShortName)
+		err := rows.Scan(&mm.ID, &mm.WinsRequired, &mm.LegsRequired, &mm.SetsRequired, &mm.TieBreakMatchTypeID, &mm.Name, &mm.ShortName)
 		if err != nil {
 			return nil, err
 		}
@@ -518,7 +542,7 @@ func GetHeadToHeadMatches(player1 int, player2 int) ([]*models.Match, error) {
 		SELECT
 			m.id, m.is_finished, m.is_abandoned, m.is_walkover, m.current_leg_id, m.winner_id, m.created_at, m.updated_at,
 			m.owe_type_id, mt.id, mt.name, mt.description,
-			mm.id, mm.name, mm.short_name, mm.wins_required, mm.legs_required
+			mm.id, mm.name, mm.short_name, mm.wins_required, mm.legs_required, mm.sets_required
 		FROM matches m
 			JOIN match_type mt ON mt.id = m.match_type_id
 			JOIN match_mode mm ON mm.id = m.match_mode_id
@@ -542,7 +566,7 @@ func GetHeadToHeadMatches(player1 int, player2 int) ([]*models.Match, error) {
 		m.MatchMode = new(models.MatchMode)
 		err := rows.Scan(&m.ID, &m.IsFinished, &m.IsAbandoned, &m.IsWalkover, &m.CurrentLegID, &m.WinnerID, &m.CreatedAt, &m.UpdatedAt,
 			&m.OweTypeID, &m.MatchType.ID, &m.MatchType.Name, &m.MatchType.Description,
-			&m.MatchMode.ID, &m.MatchMode.Name, &m.MatchMode.ShortName, &m.MatchMode.WinsRequired, &m.MatchMode.LegsRequired)
+			&m.MatchMode.ID, &m.MatchMode.Name, &m.MatchMode.ShortName, &m.MatchMode.WinsRequired, &m.MatchMode.LegsRequired, &m.MatchMode.SetsRequired)
 		if err != nil {
 			return nil, err
 		}
@@ -560,7 +584,7 @@ func GetPlayerLastMatches(playerID int, limit int) ([]*models.Match, error) {
 		SELECT
 			m.id, m.is_finished, m.is_abandoned, m.is_walkover, m.current_leg_id, m.winner_id, m.created_at, m.updated_at,
 			m.owe_type_id, mt.id, mt.name, mt.description,
-			mm.id, mm.name, mm.short_name, mm.wins_required, mm.legs_required
+			mm.id, mm.name, mm.short_name, mm.wins_required, mm.legs_required, mm.sets_required
 		FROM matches m
 			JOIN match_type mt ON mt.id = m.match_type_id
 			JOIN match_mode mm ON mm.id = m.match_mode_id
@@ -582,7 +606,7 @@ func GetPlayerLastMatches(playerID int, limit int) ([]*models.Match, error) {
 		m.MatchMode = new(models.MatchMode)
 		err := rows.Scan(&m.ID, &m.IsFinished, &m.IsAbandoned, &m.IsWalkover, &m.CurrentLegID, &m.WinnerID, &m.CreatedAt, &m.UpdatedAt, &m.OweTypeID,
 			&m.MatchType.ID, &m.MatchType.Name, &m.MatchType.Description,
-			&m.MatchMode.ID, &m.MatchMode.Name, &m.MatchMode.ShortName, &m.MatchMode.WinsRequired, &m.MatchMode.LegsRequired)
+			&m.MatchMode.ID, &m.MatchMode.Name, &m.MatchMode.ShortName, &m.MatchMode.WinsRequired, &m.MatchMode.LegsRequired, &m.MatchMode.SetsRequired)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,175 @@
+package data
+
+import (
+	"log"
+	"strings"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// notifier struct used for sending notifications about events, keeping the last tournament standings to detect changes
+type notifier struct {
+	config    models.SlackConfig
+	transport models.NotificationTransport
+	standings map[int]int
+}
+
+// StartNotifier will start sending notifications about matches, personal bests and tournament standings using the given transport
+func StartNotifier(config models.SlackConfig, transport models.NotificationTransport) {
+	n := &notifier{config: config, transport: transport, standings: make(map[int]int)}
+	standings, err := GetTournamentStandings()
+	if err != nil {
+		log.Printf("Unable to get tournament standings for notifications: %s", err)
+	}
+	for _, standing := range standings {
+		n.standings[standing.PlayerID] = standing.Rank
+	}
+	filter := models.EventFilter{Types: []string{models.EVENTMATCHFINISHED, models.EVENTHIGHESTCHECKOUT, models.EVENTELOUPDATED}}
+	go consumeEvents(filter, n.handleEvent)
+}
+
+// handleEvent will send the notifications enabled for the given event
+func (n *notifier) handleEvent(event *models.Event) {
+	var err error
+	switch event.Type {
+	case models.EVENTMATCHFINISHED:
+		if data, ok := event.Data.(models.MatchFinishedEvent); ok && n.config.IsNotificationEnabled(models.NOTIFYMATCHRESULT) {
+			err = n.notifyMatchResult(int(event.MatchID.Int64), data.WinnerID)
+		}
+		if err == nil && n.config.IsNotificationEnabled(models.NOTIFYMATCHNEXT) {
+			err = n.notifyNextMatch(int(event.MatchID.Int64))
+		}
+	case models.EVENTHIGHESTCHECKOUT:
+		if data, ok := event.Data.(models.HighestCheckoutEvent); ok && n.config.IsNotificationEnabled(models.NOTIFYPERSONALBEST) {
+			err = n.notifyPersonalBest(event.OfficeID, data)
+		}
+	case models.EVENTELOUPDATED:
+		if n.config.IsNotificationEnabled(models.NOTIFYSTANDINGS) {
+			err = n.notifyStandings(int(event.MatchID.Int64))
+		}
+	}
+	if err != nil {
+		log.Printf("Unable to send notification for event %d: %s", event.Sequence, err)
+	}
+}
+
+// notifyMatchResult will post the result of the given match, with the three dart average of each player in X01 matches
+func (n *notifier) notifyMatchResult(matchID int, winnerID null.Int) error {
+	match, err := GetMatch(matchID)
+	if err != nil {
+		return err
+	}
+	if match.IsPractice {
+		return nil
+	}
+	players, err := getNotificationPlayers(match.Players)
+	if err != nil {
+		return err
+	}
+	legsWon, err := GetWinsPerPlayer(matchID)
+	if err != nil {
+		return err
+	}
+	averages := make(map[int]float32)
+	if match.MatchType.ID == models.X01 || match.MatchType.ID == models.X01HANDICAP {
+		stats, err := GetX01StatisticsForMatch(matchID)
+		if err != nil {
+			return err
+		}
+		for _, s := range stats {
+			averages[s.PlayerID] = s.ThreeDartAvg
+		}
+	}
+	return n.send(match.OfficeID, models.GetMatchResultMessage(players, winnerID, legsWon, averages))
+}
+
+// notifyNextMatch will tell the players of the next official match in the tournament of the given match that they are up next
+func (n *notifier) notifyNextMatch(matchID int) error {
+	match, err := GetMatch(matchID)
+	if err != nil {
+		return err
+	}
+	if !match.TournamentID.Valid {
+		return nil
+	}
+	next, err := GetNextTournamentMatch(matchID)
+	if err != nil {
+		return err
+	}
+	if next == nil || next.IsFinished || len(next.Players) != 2 {
+		return nil
+	}
+	players, err := getNotificationPlayers(next.Players)
+	if err != nil {
+		return err
+	}
+	venue := null.StringFromPtr(nil)
+	if next.Venue != nil {
+		venue = next.Venue.Name
+	}
+	lines := []string{
+		models.GetMatchNextMessage(players[0], players[1], venue),
+		models.GetMatchNextMessage(players[1], players[0], venue),
+	}
+	return n.send(next.OfficeID, strings.Join(lines, "\n"))
+}
+
+// notifyPersonalBest will post the new highest checkout of a player
+func (n *notifier) notifyPersonalBest(officeID null.Int, checkout models.HighestCheckoutEvent) error {
+	player, err := GetPlayer(checkout.PlayerID)
+	if err != nil {
+		return err
+	}
+	return n.send(officeID, models.GetPersonalBestMessage(player, checkout.Checkout, checkout.PreviousCheckout))
+}
+
+// notifyStandings will post the players changing rank in the tournament standings after the given tournament match
+func (n *notifier) notifyStandings(matchID int) error {
+	match, err := GetMatch(matchID)
+	if err != nil {
+		return err
+	}
+	if !match.TournamentID.Valid {
+		return nil
+	}
+	standings, err := GetTournamentStandings()
+	if err != nil {
+		return err
+	}
+	changes := make([]*models.StandingChange, 0)
+	for _, standing := range standings {
+		previous, ok := n.standings[standing.PlayerID]
+		n.standings[standing.PlayerID] = standing.Rank
+		if !ok || previous == standing.Rank {
+			continue
+		}
+		player, err := GetPlayer(standing.PlayerID)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, &models.StandingChange{Player: player, Rank: standing.Rank, PreviousRank: previous})
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return n.send(match.OfficeID, models.GetStandingsMessage(changes))
+}
+
+// send will send the given text to the channel of the given office
+func (n *notifier) send(officeID null.Int, text string) error {
+	return n.transport.Send(models.NotificationMessage{Channel: n.config.GetChannel(officeID), Username: n.config.Username, Text: text})
+}
+
+// getNotificationPlayers will return the given players, in the same order
+func getNotificationPlayers(ids []int) ([]*models.Player, error) {
+	players := make([]*models.Player, len(ids))
+	for i, id := range ids {
+		player, err := GetPlayer(id)
+		if err != nil {
+			return nil, err
+		}
+		players[i] = player
+	}
+	return players, nil
+}
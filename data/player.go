@@ -275,10 +275,10 @@ func GetPlayersScore(legID int) (map[int]*models.Player2Leg, error) {
 			FROM player2leg p2l
 				LEFT JOIN player p on p.id = p2l.player_id
 				LEFT JOIN leg l ON l.id = p2l.leg_id
-				LEFT JOIN score s ON s.leg_id = p2l.leg_id AND s.player_id = p2l.player_id
+				LEFT JOIN score s ON s.leg_id = p2l.leg_id AND s.player_id = p2l.player_id AND s.is_bust = 0
 				LEFT JOIN matches m on m.id = l.match_id
 				LEFT JOIN bot2player2leg b ON b.player2leg_id = p2l.id
-			WHERE p2l.leg_id = ? AND (s.is_bust IS NULL OR is_bust = 0)
+			WHERE p2l.leg_id = ?
 			GROUP BY p2l.player_id
 			ORDER BY p2l.order ASC`, legID)
 	if err != nil {
@@ -309,221 +309,20 @@ func GetPlayersScore(legID int) (map[int]*models.Player2Leg, error) {
 	if err != nil {
 		return nil, err
 	}
-	matchType := *mt
-	// Get score for other game types
-	if matchType == models.SHOOTOUT {
-		visits, err := GetLegVisits(legID)
-		if err != nil {
-			return nil, err
-		}
-		for _, player := range scores {
-			player.CurrentScore = 0
-			player.DartsThrown = 0
-		}
-		for _, visit := range visits {
-			player := scores[visit.PlayerID]
-			player.CurrentScore += visit.GetScore()
-			player.DartsThrown += 3
-		}
-	} else if matchType == models.CRICKET {
-		visits, err := GetLegVisits(legID)
-		if err != nil {
-			return nil, err
-		}
-		cricketScores := make(map[int]*models.Player2Leg)
-		for id := range scores {
-			p2l := new(models.Player2Leg)
-			p2l.Hits = make(map[int]*models.Hits)
-			cricketScores[id] = p2l
-		}
-
-		for _, visit := range visits {
-			visit.CalculateCricketScore(cricketScores)
-		}
-		for _, player := range scores {
-			player.CurrentScore = cricketScores[player.PlayerID].CurrentScore
-		}
-
-		return scores, nil
-	} else if matchType == models.DARTSATX {
-		rows, err := models.DB.Query(`
-			SELECT
-				player_id,
-				SUM(case when first_dart = l.starting_score then first_dart_multiplier else 0 end) +
-				SUM(case when second_dart = l.starting_score then second_dart_multiplier else 0 end) +
-				SUM(case when third_dart = l.starting_score then third_dart_multiplier else 0 end) as 'current_score'
-			FROM score s
-			JOIN leg l on l.id = s.leg_id
-			WHERE leg_id = ?
-			GROUP BY player_id`, legID)
-		if err != nil {
-			return nil, err
-		}
-		defer rows.Close()
-
-		for rows.Next() {
-			var playerID int
-			var score int
-			err := rows.Scan(&playerID, &score)
-			if err != nil {
-				return nil, err
-			}
-			scores[playerID].CurrentScore = score
-		}
-		if err = rows.Err(); err != nil {
-			return nil, err
-		}
-	} else if matchType == models.AROUNDTHECLOCK {
-		visits, err := GetLegVisits(legID)
-		if err != nil {
-			return nil, err
-		}
-		for _, player := range scores {
-			player.CurrentScore = 0
-		}
-
-		for _, visit := range visits {
-			score := visit.CalculateAroundTheClockScore(scores[visit.PlayerID].CurrentScore)
-			scores[visit.PlayerID].CurrentScore += score
-		}
-	} else if matchType == models.AROUNDTHEWORLD || matchType == models.SHANGHAI {
-		visits, err := GetLegVisits(legID)
-		if err != nil {
-			return nil, err
-		}
-		for _, player := range scores {
-			player.CurrentScore = 0
-		}
-
-		round := 1
-		for i, visit := range visits {
-			if i > 0 && i%len(players) == 0 {
-				round++
-			}
-			score := visit.CalculateAroundTheWorldScore(round)
-			scores[visit.PlayerID].CurrentScore += score
-		}
-	} else if matchType == models.TICTACTOE {
-		for _, player := range scores {
-			player.CurrentScore = 0
-		}
-	} else if matchType == models.BERMUDATRIANGLE {
-		visits, err := GetLegVisits(legID)
-		if err != nil {
-			return nil, err
-		}
-		for _, player := range scores {
-			player.CurrentScore = 0
-		}
-
-		round := 1
-		for i, visit := range visits {
-			if i > 0 && i%len(players) == 0 {
-				round++
-			}
-			score := visit.CalculateBermudaTriangleScore(round - 1)
-			if score == 0 {
-				scores[visit.PlayerID].CurrentScore = scores[visit.PlayerID].CurrentScore / 2
-			} else {
-				scores[visit.PlayerID].CurrentScore += score
-			}
-		}
-	} else if matchType == models.FOURTWENTY {
-		visits, err := GetLegVisits(legID)
-		if err != nil {
-			return nil, err
-		}
-		for _, player := range scores {
-			player.CurrentScore = 420
-		}
-
-		round := 1
-		for i, visit := range visits {
-			if i > 0 && i%len(players) == 0 {
-				round++
-			}
-			score := visit.Calculate420Score(round - 1)
-			scores[visit.PlayerID].CurrentScore -= score
-		}
-	} else if matchType == models.KILLBULL {
-		visits, err := GetLegVisits(legID)
-		if err != nil {
-			return nil, err
-		}
-		for _, player := range scores {
-			player.CurrentScore = player.StartingScore
-		}
-
-		for _, visit := range visits {
-			score := visit.CalculateKillBullScore()
-			if score == 0 {
-				scores[visit.PlayerID].CurrentScore = scores[visit.PlayerID].StartingScore
-			} else {
-				scores[visit.PlayerID].CurrentScore -= score
-			}
-		}
-	} else if matchType == models.GOTCHA {
-		visits, err := GetLegVisits(legID)
-		if err != nil {
-			return nil, err
-		}
-
-		targetScore := 0
-		for _, player := range scores {
-			player.CurrentScore = 0
-			targetScore = player.StartingScore
-		}
-
-		for _, visit := range visits {
-			score := visit.CalculateGotchaScore(scores, targetScore)
-			scores[visit.PlayerID].CurrentScore += score
-		}
-	} else if matchType == models.JDCPRACTICE {
-		visits, err := GetLegVisits(legID)
-		if err != nil {
-			return nil, err
-		}
-		for _, player := range scores {
-			player.CurrentScore = 0
-		}
-
-		round := 1
-		for i, visit := range visits {
-			if i > 0 && i%len(players) == 0 {
-				round++
-			}
-			scores[visit.PlayerID].CurrentScore += visit.CalculateJDCPracticeScore(round - 1)
-		}
-	} else if matchType == models.KNOCKOUT {
-		visits, err := GetLegVisits(legID)
-		if err != nil {
-			return nil, err
-		}
-		params, err := GetLegParameters(legID)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, player := range scores {
-			player.CurrentScore = 0
-			player.Lives = params.StartingLives
-		}
-
-		for i, visit := range visits {
-			player := scores[visit.PlayerID]
-			player.CurrentScore = visit.GetScore()
+	rules, err := models.GetGameRules(*mt)
+	if err != nil {
+		return nil, err
+	}
 
-			idx := i - 1
-			if idx < 0 {
-				continue
-			}
-			prev := visits[idx]
-			if prev.GetScore() > visit.GetScore() {
-				player.Lives = null.IntFrom(player.Lives.Int64 - 1)
-			}
-			scores[prev.PlayerID].CurrentScore = 0
-		}
+	visits, err := GetLegVisits(legID)
+	if err != nil {
+		return nil, err
+	}
+	params, err := rules.GetLegParameters(legID)
+	if err != nil {
+		return nil, err
 	}
+	calculatePlayersScore(scores, rules, params, visits)
 	return scores, nil
 }
 
@@ -691,7 +490,7 @@ func GetPlayerCheckouts(playerID int) ([]*models.CheckoutStatistics, error) {
 
 	checkouts := make([]*models.CheckoutStatistics, 0)
 	for i := 170; i >= 2; i-- {
-		if i == 169 || i == 168 || i == 166 || i == 165 || i == 163 || i == 162 || i == 159 {
+		if models.IsBogeyNumber(i, models.OUTSHOTDOUBLE) {
 			// Skip values which cannot be checkouts
 			continue
 		}
@@ -973,6 +772,7 @@ func UpdateEloForMatch(matchID int) error {
 	if err != nil {
 		return err
 	}
+	publishMatchEvent(models.EVENTELOUPDATED, matchID, []*models.PlayerElo{p1, p2})
 	return nil
 }
 
@@ -0,0 +1,117 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// replayLeg will run the given visits through the rules of the leg, in order, updating bust, invalidated darts and the thrower of each visit.
+// It returns the state of the leg after the last visit. An error is returned if the visits are not a valid history
+func replayLeg(leg *models.Leg, visits []*models.Visit) (*models.LegState, error) {
+	rules, err := models.GetGameRules(leg.LegType.ID)
+	if err != nil {
+		return nil, err
+	}
+	base, err := GetPlayersScore(leg.ID)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := GetTeamsForLeg(leg.ID)
+	if err != nil {
+		return nil, err
+	}
+	match := models.Match{Teams: teams}
+
+	replay := *leg
+	if leg.Parameters != nil {
+		// Hits are claimed again while replaying, so start from a clean board
+		params := *leg.Parameters
+		params.Hits = make(map[int]int)
+		replay.Parameters = &params
+	}
+
+	currentPlayerID := leg.Players[0]
+	isFinished := false
+	for i, visit := range visits {
+		if isFinished {
+			return nil, fmt.Errorf("visit %d would be thrown after the leg was finished", visit.ID)
+		}
+		if visit.PlayerID != currentPlayerID {
+			return nil, fmt.Errorf("visit %d was thrown by player %d, but it would be player %d's turn", visit.ID, visit.PlayerID, currentPlayerID)
+		}
+		// Members of a team throw in rotation, so visits after a deleted visit are thrown by the next member
+		visit.ThrowerID = null.Int{}
+		err = match.SetThrower(visit, visits[:i])
+		if err != nil {
+			return nil, err
+		}
+
+		players := copyPlayers(base)
+		replay.Visits = visits[:i]
+		calculatePlayersScore(players, rules, replay.Parameters, replay.Visits)
+
+		rules.HandleVisit(&replay, players, visit)
+		isFinished = rules.IsLegFinished(&replay, players, visit)
+		currentPlayerID = getNextPlayerID(players, rules, *visit)
+	}
+
+	players := copyPlayers(base)
+	calculatePlayersScore(players, rules, replay.Parameters, visits)
+	for _, player := range players {
+		player.IsCurrentPlayer = player.PlayerID == currentPlayerID
+	}
+	state := &models.LegState{
+		LegID:           leg.ID,
+		Visit:           len(visits),
+		Round:           models.GetRound(visits, currentPlayerID),
+		CurrentPlayerID: currentPlayerID,
+		IsFinished:      isFinished,
+		Players:         players,
+	}
+	if replay.Parameters != nil && len(replay.Parameters.Hits) > 0 {
+		state.Hits = replay.Parameters.Hits
+	}
+	if len(visits) > 0 {
+		state.LastVisit = visits[len(visits)-1]
+	}
+	return state, nil
+}
+
+// GetLegState will return the state of the given leg after the given number of visits, by replaying the visits of the leg
+func GetLegState(legID int, visit int) (*models.LegState, error) {
+	leg, err := GetLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+	if visit < 0 || visit > len(leg.Visits) {
+		return nil, fmt.Errorf("leg %d has %d visits", legID, len(leg.Visits))
+	}
+	return replayLeg(leg, leg.Visits[:visit])
+}
+
+// copyPlayers will return a copy of the given players, to allow rules to modify them without changing the originals
+func copyPlayers(players map[int]*models.Player2Leg) map[int]*models.Player2Leg {
+	copies := make(map[int]*models.Player2Leg)
+	for id, player := range players {
+		p2l := *player
+		copies[id] = &p2l
+	}
+	return copies
+}
+
+// getReplayKeys will return the replay key of each of the given visits, by visit ID
+func getReplayKeys(visits []*models.Visit) map[int]string {
+	keys := make(map[int]string)
+	for _, visit := range visits {
+		keys[visit.ID] = getReplayKey(visit)
+	}
+	return keys
+}
+
+// getReplayKey will return a string representing the darts, bust state and thrower of the given visit, to detect visits changed by a replay
+func getReplayKey(visit *models.Visit) string {
+	return fmt.Sprintf("%s %s %s %t %d", visit.FirstDart.GetString(), visit.SecondDart.GetString(), visit.ThirdDart.GetString(), visit.IsBust,
+		visit.GetThrowerID())
+}
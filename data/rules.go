@@ -0,0 +1,178 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+func init() {
+	models.RegisterGameRules(models.X01, newX01Rules(models.X01))
+	models.RegisterGameRules(models.SHOOTOUT, newShootoutRules())
+	models.RegisterGameRules(models.X01HANDICAP, newX01Rules(models.X01HANDICAP))
+	models.RegisterGameRules(models.CRICKET, newCricketRules())
+	models.RegisterGameRules(models.DARTSATX, newDartsAtXRules())
+	models.RegisterGameRules(models.AROUNDTHEWORLD, newAroundTheWorldRules(models.AROUNDTHEWORLD))
+	models.RegisterGameRules(models.SHANGHAI, newAroundTheWorldRules(models.SHANGHAI))
+	models.RegisterGameRules(models.AROUNDTHECLOCK, newAroundTheClockRules())
+	models.RegisterGameRules(models.TICTACTOE, newTicTacToeRules())
+	models.RegisterGameRules(models.BERMUDATRIANGLE, newBermudaTriangleRules())
+	models.RegisterGameRules(models.FOURTWENTY, newFourTwentyRules())
+	models.RegisterGameRules(models.KILLBULL, newKillBullRules())
+	models.RegisterGameRules(models.GOTCHA, newGotchaRules())
+	models.RegisterGameRules(models.JDCPRACTICE, newJDCPracticeRules())
+	models.RegisterGameRules(models.KNOCKOUT, newKnockoutRules())
+	models.RegisterGameRules(models.KILLER, newKillerRules())
+	models.RegisterGameRules(models.GOLF, newGolfRules())
+	models.RegisterGameRules(models.HALVEIT, newHalveItRules())
+	models.RegisterGameRules(models.BASEBALL, newBaseballRules())
+	models.RegisterGameRules(models.BOBS27, newBobs27Rules())
+	models.RegisterGameRules(models.COUNTUP, newCountUpRules())
+	models.RegisterGameRules(models.CHECKOUT121, newCheckout121Rules())
+	models.RegisterGameRules(models.CUSTOM, newCustomRules())
+}
+
+// baseRules contains the default rules for match types without leg parameters, where all players start at zero and are never out
+type baseRules struct{}
+
+// IsPlayerOut will return false, since players are never out
+func (r baseRules) IsPlayerOut(player *models.Player2Leg, visit models.Visit) bool {
+	return false
+}
+
+// InitializeScores will set the score of all players to zero
+func (r baseRules) InitializeScores(params *models.LegParameters, players map[int]*models.Player2Leg) {
+	for _, player := range players {
+		player.CurrentScore = 0
+	}
+}
+
+// ValidateLegParameters will return nil, since there are no leg parameters to validate
+func (r baseRules) ValidateLegParameters(leg *models.Leg) error {
+	return nil
+}
+
+// InsertLegParameters will not write anything, since the match type has no leg parameters
+func (r baseRules) InsertLegParameters(tx *sql.Tx, leg *models.Leg) error {
+	return nil
+}
+
+// GetLegParameters will return nil, since the match type has no leg parameters
+func (r baseRules) GetLegParameters(legID int) (*models.LegParameters, error) {
+	return nil, nil
+}
+
+// gameStatistics contains the tables statistics of a match type are written to, and the functions used to read them
+type gameStatistics struct {
+	tables    []string
+	global    func(from string, to string) (interface{}, error)
+	forLeg    func(legID int) (interface{}, error)
+	forMatch  func(matchID int) (interface{}, error)
+	forPlayer func(playerID int) (interface{}, error)
+	history   func(playerID int, limit int) (interface{}, error)
+}
+
+// DeleteStatistics will delete the statistics of the given leg from all tables of the match type
+func (s gameStatistics) DeleteStatistics(tx *sql.Tx, legID int) error {
+	for _, table := range s.tables {
+		_, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE leg_id = ?", table), legID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetStatistics will return statistics for all players between the given dates
+func (s gameStatistics) GetStatistics(from string, to string) (interface{}, error) {
+	return s.global(from, to)
+}
+
+// GetStatisticsForLeg will return statistics for all players in the given leg
+func (s gameStatistics) GetStatisticsForLeg(legID int) (interface{}, error) {
+	return s.forLeg(legID)
+}
+
+// GetStatisticsForMatch will return statistics for all players in the given match
+func (s gameStatistics) GetStatisticsForMatch(matchID int) (interface{}, error) {
+	return s.forMatch(matchID)
+}
+
+// GetStatisticsForPlayer will return statistics for the given player
+func (s gameStatistics) GetStatisticsForPlayer(playerID int) (interface{}, error) {
+	return s.forPlayer(playerID)
+}
+
+// GetHistoryForPlayer will return the last legs played by the given player
+func (s gameStatistics) GetHistoryForPlayer(playerID int, limit int) (interface{}, error) {
+	return s.history(playerID, limit)
+}
+
+// getVisitRound will return the round (starting at 1) of a visit thrown after the given visits
+func getVisitRound(visits []*models.Visit, players map[int]*models.Player2Leg) int {
+	return len(visits)/len(players) + 1
+}
+
+// calculatePlayersScore will set the current score of each player after the given visits have been thrown
+func calculatePlayersScore(scores map[int]*models.Player2Leg, rules models.GameRules, params *models.LegParameters, visits []*models.Visit) {
+	rules.InitializeScores(params, scores)
+	for i, visit := range visits {
+		rules.CalculateScore(params, scores, visits[:i], visit)
+	}
+}
+
+// getHighScoreWinner will return the player with the highest score, or null if two players share the highest score
+func getHighScoreWinner(players map[int]*models.Player2Leg) null.Int {
+	winnerID := null.IntFromPtr(nil)
+	highScore := 0
+	isDraw := false
+	for playerID, player := range players {
+		if player.CurrentScore == highScore {
+			isDraw = true
+		}
+		if player.CurrentScore > highScore {
+			highScore = player.CurrentScore
+			winnerID = null.IntFrom(int64(playerID))
+			isDraw = false
+		}
+	}
+	if isDraw {
+		winnerID = null.IntFromPtr(nil)
+	}
+	return winnerID
+}
+
+// getLowScoreWinner will return the player with the lowest score below the given upper limit
+func getLowScoreWinner(players map[int]*models.Player2Leg, limit int) null.Int {
+	winnerID := null.IntFromPtr(nil)
+	lowestScore := limit
+	for playerID, player := range players {
+		if player.CurrentScore < lowestScore {
+			lowestScore = player.CurrentScore
+			winnerID = null.IntFrom(int64(playerID))
+		}
+	}
+	return winnerID
+}
+
+// getRound will return the current round (starting at 1) of the given leg
+func getRound(leg *models.Leg) int {
+	return len(leg.Visits)/len(leg.Players) + 1
+}
+
+// isRoundCompleteWithUniqueLead will check if the given visit completes a round, at least the given number of rounds have been
+// played, and a single player is leading after adding the given score for the visit
+func isRoundCompleteWithUniqueLead(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit, rounds int, score int) bool {
+	visits := len(leg.Visits) + 1
+	if visits%len(leg.Players) != 0 || visits/len(leg.Players) < rounds {
+		return false
+	}
+	scores := make(map[int]*models.Player2Leg)
+	for id, player := range players {
+		scores[id] = &models.Player2Leg{PlayerID: player.PlayerID, CurrentScore: player.CurrentScore}
+	}
+	scores[visit.PlayerID].CurrentScore += score
+	return getHighScoreWinner(scores).Valid
+}
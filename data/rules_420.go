@@ -0,0 +1,76 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// fourTwentyRules contains the rules for 420
+type fourTwentyRules struct {
+	baseRules
+	gameStatistics
+}
+
+// newFourTwentyRules will return the rules for 420
+func newFourTwentyRules() *fourTwentyRules {
+	return &fourTwentyRules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_420"},
+		global:    func(from string, to string) (interface{}, error) { return Get420Statistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return Get420StatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return Get420StatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return Get420StatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return Get420HistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit does nothing, since it is not possible to bust in 420
+func (r *fourTwentyRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+}
+
+// IsLegFinished will check if all players have thrown all 21 rounds
+func (r *fourTwentyRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	return ((len(leg.Visits)+1)*3)%(63*len(leg.Players)) == 0
+}
+
+// GetWinner will return the player with the lowest score
+func (r *fourTwentyRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	return getLowScoreWinner(players, 421)
+}
+
+// InsertStatistics will write 420 statistics for all players in the leg
+func (r *fourTwentyRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := Calculate420Statistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_420 (leg_id, player_id, score, total_hit_rate, hit_rate_1, hit_rate_2, hit_rate_3, hit_rate_4, hit_rate_5, hit_rate_6, hit_rate_7, hit_rate_8, hit_rate_9,
+				hit_rate_10, hit_rate_11, hit_rate_12, hit_rate_13, hit_rate_14, hit_rate_15, hit_rate_16, hit_rate_17, hit_rate_18, hit_rate_19, hit_rate_20, hit_rate_bull) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+			visit.LegID, playerID, stats.Score, stats.TotalHitRate, stats.Hitrates[1], stats.Hitrates[2], stats.Hitrates[3], stats.Hitrates[4], stats.Hitrates[5], stats.Hitrates[6],
+			stats.Hitrates[7], stats.Hitrates[8], stats.Hitrates[9], stats.Hitrates[10], stats.Hitrates[11], stats.Hitrates[12], stats.Hitrates[13], stats.Hitrates[14], stats.Hitrates[15], stats.Hitrates[16],
+			stats.Hitrates[17], stats.Hitrates[18], stats.Hitrates[19], stats.Hitrates[20], stats.Hitrates[25])
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting Four Twenty statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// InitializeScores will set the score of each player to 420
+func (r *fourTwentyRules) InitializeScores(params *models.LegParameters, players map[int]*models.Player2Leg) {
+	for _, player := range players {
+		player.CurrentScore = 420
+	}
+}
+
+// CalculateScore will subtract the score of darts hitting the target of the round
+func (r *fourTwentyRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	score := visit.Calculate420Score(getVisitRound(visits, players) - 1)
+	players[visit.PlayerID].CurrentScore -= score
+	return score
+}
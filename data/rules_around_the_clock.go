@@ -0,0 +1,98 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// aroundTheClockRules contains the rules for Around the Clock
+type aroundTheClockRules struct {
+	baseRules
+	gameStatistics
+}
+
+// newAroundTheClockRules will return the rules for Around the Clock
+func newAroundTheClockRules() *aroundTheClockRules {
+	return &aroundTheClockRules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_around_the"},
+		global:    func(from string, to string) (interface{}, error) { return GetAroundTheClockStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetAroundTheClockStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetAroundTheClockStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetAroundTheClockStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetAroundTheClockHistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit will invalidate darts thrown after the bull was hit
+func (r *aroundTheClockRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+	currentScore := players[visit.PlayerID].CurrentScore
+	if currentScore+visit.CalculateAroundTheClockScore(currentScore, leg.Parameters.GetTargetMultiplier()) == 21 {
+		if visit.FirstDart.IsBull() {
+			visit.SecondDart.Value = null.IntFromPtr(nil)
+			visit.ThirdDart.Value = null.IntFromPtr(nil)
+		} else if visit.SecondDart.IsBull() {
+			visit.ThirdDart.Value = null.IntFromPtr(nil)
+		}
+	}
+}
+
+// IsLegFinished will check if the player has hit all numbers, finishing on bull
+func (r *aroundTheClockRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	player := players[visit.PlayerID]
+	player.CurrentScore += visit.CalculateAroundTheClockScore(player.CurrentScore, leg.Parameters.GetTargetMultiplier())
+	return player.CurrentScore == 21
+}
+
+// GetWinner will return the player finishing the leg
+func (r *aroundTheClockRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	return null.IntFrom(int64(visit.PlayerID))
+}
+
+// InsertStatistics will write Around the Clock statistics for all players in the leg
+func (r *aroundTheClockRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateAroundTheClockStatistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_around_the
+				(leg_id, player_id, darts_thrown, score, longest_streak, total_hit_rate, hit_rate_1, hit_rate_2, hit_rate_3, hit_rate_4, hit_rate_5, hit_rate_6, hit_rate_7, hit_rate_8,
+					hit_rate_9, hit_rate_10, hit_rate_11, hit_rate_12, hit_rate_13, hit_rate_14, hit_rate_15, hit_rate_16, hit_rate_17, hit_rate_18, hit_rate_19, hit_rate_20, hit_rate_bull)
+			VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`, visit.LegID, playerID, stats.DartsThrown, stats.Score, stats.LongestStreak, stats.TotalHitRate, stats.Hitrates[1],
+			stats.Hitrates[2], stats.Hitrates[3], stats.Hitrates[4], stats.Hitrates[5], stats.Hitrates[6], stats.Hitrates[7], stats.Hitrates[8], stats.Hitrates[9], stats.Hitrates[10],
+			stats.Hitrates[11], stats.Hitrates[12], stats.Hitrates[13], stats.Hitrates[14], stats.Hitrates[15], stats.Hitrates[16], stats.Hitrates[17], stats.Hitrates[18], stats.Hitrates[19],
+			stats.Hitrates[20], stats.Hitrates[25])
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting Around the Clock statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// CalculateScore will add the number of targets hit, in order, with the multiplier of the leg
+func (r *aroundTheClockRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	player := players[visit.PlayerID]
+	score := visit.CalculateAroundTheClockScore(player.CurrentScore, params.GetTargetMultiplier())
+	player.CurrentScore += score
+	return score
+}
+
+// ValidateLegParameters will check that the target multiplier of the leg, if set, is a single, double or triple
+func (r *aroundTheClockRules) ValidateLegParameters(leg *models.Leg) error {
+	return leg.Parameters.ValidateAroundTheClock()
+}
+
+// InsertLegParameters will write the variant settings of the leg, if any are set
+func (r *aroundTheClockRules) InsertLegParameters(tx *sql.Tx, leg *models.Leg) error {
+	return insertVariantLegParameters(tx, int64(leg.ID), leg.Parameters)
+}
+
+// GetLegParameters will return the variant settings of the leg, or nil if the defaults are used
+func (r *aroundTheClockRules) GetLegParameters(legID int) (*models.LegParameters, error) {
+	return getOptionalLegParameters(legID)
+}
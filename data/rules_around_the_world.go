@@ -0,0 +1,112 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// aroundTheWorldRules contains the rules for Around the World and Shanghai
+type aroundTheWorldRules struct {
+	baseRules
+	gameStatistics
+	matchType int
+}
+
+// newAroundTheWorldRules will return the rules for the given Around the World match type
+func newAroundTheWorldRules(matchType int) *aroundTheWorldRules {
+	if matchType == models.SHANGHAI {
+		return &aroundTheWorldRules{matchType: matchType, gameStatistics: gameStatistics{
+			tables:    []string{"statistics_around_the"},
+			global:    func(from string, to string) (interface{}, error) { return GetShanghaiStatistics(from, to) },
+			forLeg:    func(id int) (interface{}, error) { return GetShanghaiStatisticsForLeg(id) },
+			forMatch:  func(id int) (interface{}, error) { return GetShanghaiStatisticsForMatch(id) },
+			forPlayer: func(id int) (interface{}, error) { return GetShanghaiStatisticsForPlayer(id) },
+			history:   func(id int, limit int) (interface{}, error) { return GetShanghaiHistoryForPlayer(id, limit) },
+		}}
+	}
+	return &aroundTheWorldRules{matchType: matchType, gameStatistics: gameStatistics{
+		tables:    []string{"statistics_around_the"},
+		global:    func(from string, to string) (interface{}, error) { return GetAroundTheWorldStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetAroundTheWorldStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetAroundTheWorldStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetAroundTheWorldStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetAroundTheWorldHistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit does nothing, since it is not possible to bust in Around the World or Shanghai
+func (r *aroundTheWorldRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+}
+
+// IsLegFinished will check if all rounds have been thrown, or if a Shanghai was hit on the current number
+func (r *aroundTheWorldRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	if r.matchType == models.SHANGHAI {
+		return (len(leg.Visits)+1)%(leg.Parameters.GetShanghaiRounds()*len(leg.Players)) == 0 || (visit.IsShanghai() && visit.FirstDart.ValueRaw() == getRound(leg))
+	}
+	return (len(leg.Visits)+1)%(21*len(leg.Players)) == 0
+}
+
+// GetWinner will return the player hitting a Shanghai, or the player with the highest score
+func (r *aroundTheWorldRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	if r.matchType == models.SHANGHAI && visit.IsShanghai() {
+		return null.IntFrom(int64(visit.PlayerID))
+	}
+	return getHighScoreWinner(players)
+}
+
+// InsertStatistics will write Around the World/Shanghai statistics for all players in the leg
+func (r *aroundTheWorldRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateAroundTheWorldStatistics(visit.LegID, r.matchType)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_around_the
+				(leg_id, player_id, darts_thrown, score, shanghai, mpr, total_hit_rate, hit_rate_1, hit_rate_2, hit_rate_3, hit_rate_4, hit_rate_5, hit_rate_6, hit_rate_7, hit_rate_8, hit_rate_9, hit_rate_10,
+					hit_rate_11, hit_rate_12, hit_rate_13, hit_rate_14, hit_rate_15, hit_rate_16, hit_rate_17, hit_rate_18, hit_rate_19, hit_rate_20, hit_rate_bull)
+			VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`, visit.LegID, playerID, stats.DartsThrown, stats.Score, stats.Shanghai, stats.MPR, stats.TotalHitRate, stats.Hitrates[1],
+			stats.Hitrates[2], stats.Hitrates[3], stats.Hitrates[4], stats.Hitrates[5], stats.Hitrates[6], stats.Hitrates[7], stats.Hitrates[8], stats.Hitrates[9], stats.Hitrates[10],
+			stats.Hitrates[11], stats.Hitrates[12], stats.Hitrates[13], stats.Hitrates[14], stats.Hitrates[15], stats.Hitrates[16], stats.Hitrates[17], stats.Hitrates[18], stats.Hitrates[19],
+			stats.Hitrates[20], stats.Hitrates[25])
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting Around the World/Shanghai statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// CalculateScore will add the score of darts hitting the number of the current round
+func (r *aroundTheWorldRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	score := visit.CalculateAroundTheWorldScore(getVisitRound(visits, players))
+	players[visit.PlayerID].CurrentScore += score
+	return score
+}
+
+// ValidateLegParameters will check that the number of rounds of Shanghai legs, if set, is between 1 and 20
+func (r *aroundTheWorldRules) ValidateLegParameters(leg *models.Leg) error {
+	if r.matchType != models.SHANGHAI {
+		return nil
+	}
+	return leg.Parameters.ValidateShanghai()
+}
+
+// InsertLegParameters will write the number of rounds of Shanghai legs, if set
+func (r *aroundTheWorldRules) InsertLegParameters(tx *sql.Tx, leg *models.Leg) error {
+	if r.matchType != models.SHANGHAI {
+		return nil
+	}
+	return insertVariantLegParameters(tx, int64(leg.ID), leg.Parameters)
+}
+
+// GetLegParameters will return the number of rounds of Shanghai legs, or nil if the defaults are used
+func (r *aroundTheWorldRules) GetLegParameters(legID int) (*models.LegParameters, error) {
+	if r.matchType != models.SHANGHAI {
+		return nil, nil
+	}
+	return getOptionalLegParameters(legID)
+}
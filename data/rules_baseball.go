@@ -0,0 +1,69 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// baseballRules contains the rules for Baseball
+type baseballRules struct {
+	baseRules
+	gameStatistics
+}
+
+// newBaseballRules will return the rules for Baseball
+func newBaseballRules() *baseballRules {
+	return &baseballRules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_baseball"},
+		global:    func(from string, to string) (interface{}, error) { return GetBaseballStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetBaseballStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetBaseballStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetBaseballStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetBaseballHistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit does nothing, since it is not possible to bust in Baseball
+func (r *baseballRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+}
+
+// IsLegFinished will check if all players have thrown all nine innings, going to extra innings until one player is leading
+func (r *baseballRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	return isRoundCompleteWithUniqueLead(leg, players, visit, models.BaseballInnings, visit.CalculateBaseballScore(getRound(leg)))
+}
+
+// GetWinner will return the player with the most runs
+func (r *baseballRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	return getHighScoreWinner(players)
+}
+
+// InsertStatistics will write Baseball statistics for all players in the leg
+func (r *baseballRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateBaseballStatistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		i := stats.Innings
+		_, err = tx.Exec(`
+			INSERT INTO statistics_baseball (leg_id, player_id, darts_thrown, score, innings_played, total_hit_rate, inning_1, inning_2, inning_3, inning_4,
+				inning_5, inning_6, inning_7, inning_8, inning_9, extra_inning_runs) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+			visit.LegID, playerID, stats.DartsThrown, stats.Score, stats.InningsPlayed, stats.TotalHitRate, i[1], i[2], i[3], i[4],
+			i[5], i[6], i[7], i[8], i[9], stats.ExtraInningRuns)
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting Baseball statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// CalculateScore will add the runs scored in the current inning
+func (r *baseballRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	score := visit.CalculateBaseballScore(getVisitRound(visits, players))
+	players[visit.PlayerID].CurrentScore += score
+	return score
+}
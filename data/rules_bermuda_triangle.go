@@ -0,0 +1,74 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// bermudaTriangleRules contains the rules for Bermuda Triangle
+type bermudaTriangleRules struct {
+	baseRules
+	gameStatistics
+}
+
+// newBermudaTriangleRules will return the rules for Bermuda Triangle
+func newBermudaTriangleRules() *bermudaTriangleRules {
+	return &bermudaTriangleRules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_bermuda_triangle"},
+		global:    func(from string, to string) (interface{}, error) { return GetBermudaTriangleStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetBermudaTriangleStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetBermudaTriangleStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetBermudaTriangleStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetBermudaTriangleHistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit does nothing, since it is not possible to bust in Bermuda Triangle
+func (r *bermudaTriangleRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+}
+
+// IsLegFinished will check if all players have thrown all 13 rounds
+func (r *bermudaTriangleRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	return ((len(leg.Visits)+1)*3)%(39*len(leg.Players)) == 0
+}
+
+// GetWinner will return the player with the highest score
+func (r *bermudaTriangleRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	return getHighScoreWinner(players)
+}
+
+// InsertStatistics will write Bermuda Triangle statistics for all players in the leg
+func (r *bermudaTriangleRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateBermudaTriangleStatistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_bermuda_triangle (leg_id, player_id, darts_thrown, score, mpr, total_marks, highest_score_reached, total_hit_rate, hit_rate_1, hit_rate_2, hit_rate_3,
+				hit_rate_4, hit_rate_5, hit_rate_6, hit_rate_7, hit_rate_8, hit_rate_9, hit_rate_10, hit_rate_11, hit_rate_12, hit_rate_13, hit_count) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+			visit.LegID, playerID, stats.DartsThrown, stats.Score, stats.MPR, &stats.TotalMarks, stats.HighestScoreReached, stats.TotalHitRate, stats.Hitrates[0], stats.Hitrates[1], stats.Hitrates[2],
+			stats.Hitrates[3], stats.Hitrates[4], stats.Hitrates[5], stats.Hitrates[6], stats.Hitrates[7], stats.Hitrates[8], stats.Hitrates[9], stats.Hitrates[10], stats.Hitrates[11], stats.Hitrates[12],
+			stats.HitCount)
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting Bermuda Triangle statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// CalculateScore will add the score of darts hitting the target of the round, or halve the score of the player if the target was missed
+func (r *bermudaTriangleRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	player := players[visit.PlayerID]
+	score := visit.CalculateBermudaTriangleScore(getVisitRound(visits, players) - 1)
+	if score == 0 {
+		player.CurrentScore = player.CurrentScore / 2
+	} else {
+		player.CurrentScore += score
+	}
+	return score
+}
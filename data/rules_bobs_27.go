@@ -0,0 +1,126 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// bobs27Rules contains the rules for Bob's 27
+type bobs27Rules struct {
+	baseRules
+	gameStatistics
+}
+
+// newBobs27Rules will return the rules for Bob's 27
+func newBobs27Rules() *bobs27Rules {
+	return &bobs27Rules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_bobs_27"},
+		global:    func(from string, to string) (interface{}, error) { return GetBobs27Statistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetBobs27StatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetBobs27StatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetBobs27StatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetBobs27HistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit does nothing, since it is not possible to bust in Bob's 27
+func (r *bobs27Rules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+}
+
+// IsLegFinished will check if all players have either reached zero, or thrown at all doubles
+func (r *bobs27Rules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	rounds := make(map[int]int)
+	for _, v := range leg.Visits {
+		rounds[v.PlayerID]++
+	}
+	if rounds[visit.PlayerID] >= len(models.TargetsBobs27) {
+		return true
+	}
+	score := players[visit.PlayerID].CurrentScore + visit.CalculateBobs27Score(rounds[visit.PlayerID])
+	rounds[visit.PlayerID]++
+
+	for playerID, player := range players {
+		current := player.CurrentScore
+		if playerID == visit.PlayerID {
+			current = score
+		}
+		if current > 0 && rounds[playerID] < len(models.TargetsBobs27) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetWinner will return the player lasting the most rounds, with the highest score, or null if two players are equal
+func (r *bobs27Rules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	winnerID := null.IntFromPtr(nil)
+	var best *models.Player2Leg
+	for playerID, player := range players {
+		if best == nil || player.DartsThrown > best.DartsThrown ||
+			(player.DartsThrown == best.DartsThrown && player.CurrentScore > best.CurrentScore) {
+			best = player
+			winnerID = null.IntFrom(int64(playerID))
+		} else if player.DartsThrown == best.DartsThrown && player.CurrentScore == best.CurrentScore {
+			winnerID = null.IntFromPtr(nil)
+		}
+	}
+	return winnerID
+}
+
+// InsertStatistics will write Bob's 27 statistics for all players in the leg
+func (r *bobs27Rules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateBobs27Statistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		h := make([]null.Float, 22)
+		for i, target := range models.TargetsBobs27 {
+			if hitrate, ok := stats.Hitrates[target]; ok {
+				h[i+1] = null.FloatFrom(hitrate)
+			}
+		}
+		_, err = tx.Exec(`
+			INSERT INTO statistics_bobs_27 (leg_id, player_id, darts_thrown, score, highest_score_reached, rounds_played, doubles_hit, total_hit_rate,
+				hit_rate_1, hit_rate_2, hit_rate_3, hit_rate_4, hit_rate_5, hit_rate_6, hit_rate_7, hit_rate_8, hit_rate_9, hit_rate_10, hit_rate_11,
+				hit_rate_12, hit_rate_13, hit_rate_14, hit_rate_15, hit_rate_16, hit_rate_17, hit_rate_18, hit_rate_19, hit_rate_20, hit_rate_bull)
+			VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+			visit.LegID, playerID, stats.DartsThrown, stats.Score, stats.HighestScoreReached, stats.RoundsPlayed, stats.DoublesHit, stats.TotalHitRate,
+			h[1], h[2], h[3], h[4], h[5], h[6], h[7], h[8], h[9], h[10], h[11], h[12], h[13], h[14], h[15], h[16], h[17], h[18], h[19], h[20], h[21])
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting Bob's 27 statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// IsPlayerOut will check if the given player has reached zero
+func (r *bobs27Rules) IsPlayerOut(player *models.Player2Leg, visit models.Visit) bool {
+	return player.CurrentScore <= 0 && player.PlayerID != visit.PlayerID
+}
+
+// InitializeScores will set the score of each player to the starting score of Bob's 27
+func (r *bobs27Rules) InitializeScores(params *models.LegParameters, players map[int]*models.Player2Leg) {
+	for _, player := range players {
+		player.CurrentScore = models.Bobs27StartingScore
+		player.DartsThrown = 0
+	}
+}
+
+// CalculateScore will add the score of the double of the round of the player, ignoring visits after the last double
+func (r *bobs27Rules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	// Players can be knocked out, so the round of each player is given by their own visits
+	player := players[visit.PlayerID]
+	if player.DartsThrown/3 >= len(models.TargetsBobs27) {
+		return 0
+	}
+	score := visit.CalculateBobs27Score(player.DartsThrown / 3)
+	player.CurrentScore += score
+	player.DartsThrown += 3
+	visit.DartsThrown = player.DartsThrown
+	return score
+}
@@ -0,0 +1,107 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// checkout121Rules contains the rules for Checkout 121
+type checkout121Rules struct {
+	baseRules
+	gameStatistics
+}
+
+// newCheckout121Rules will return the rules for Checkout 121
+func newCheckout121Rules() *checkout121Rules {
+	return &checkout121Rules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_checkout_121"},
+		global:    func(from string, to string) (interface{}, error) { return GetCheckout121Statistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetCheckout121StatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetCheckout121StatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetCheckout121StatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetCheckout121HistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit will check if the visit is a bust for the remaining score of the current target, according to the outshot type of the leg
+func (r *checkout121Rules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+	visit.SetIsBust(players[visit.PlayerID].CurrentScore, leg.GetOutshotTypeID())
+}
+
+// IsLegFinished will check if all players have played the configured number of rounds
+func (r *checkout121Rules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	return len(leg.Visits)+1 >= leg.Parameters.GetCheckout121Rounds()*len(leg.Players)
+}
+
+// GetWinner will return the player reaching the highest target, with the most checkouts, or null if two players are equal
+func (r *checkout121Rules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	winnerID := null.IntFromPtr(nil)
+	var best *models.Checkout121
+	for playerID, player := range players {
+		ladder := player.Checkout121
+		if best == nil || ladder.HighestTarget > best.HighestTarget ||
+			(ladder.HighestTarget == best.HighestTarget && ladder.Checkouts > best.Checkouts) {
+			best = ladder
+			winnerID = null.IntFrom(int64(playerID))
+		} else if ladder.HighestTarget == best.HighestTarget && ladder.Checkouts == best.Checkouts {
+			winnerID = null.IntFromPtr(nil)
+		}
+	}
+	return winnerID
+}
+
+// InsertStatistics will write Checkout 121 statistics for all players in the leg
+func (r *checkout121Rules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateCheckout121Statistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_checkout_121 (leg_id, player_id, darts_thrown, highest_target, targets_attempted, checkouts, success_rate,
+				darts_per_checkout, checkout_attempts, checkout_percentage)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, visit.LegID, playerID, stats.DartsThrown, stats.HighestTarget, stats.TargetsAttempted,
+			stats.Checkouts, stats.SuccessRate, stats.DartsPerCheckout, stats.CheckoutAttempts, stats.CheckoutPercentage)
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting Checkout 121 statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// InitializeScores will start each player at the first target of the ladder
+func (r *checkout121Rules) InitializeScores(params *models.LegParameters, players map[int]*models.Player2Leg) {
+	for _, player := range players {
+		player.Checkout121 = models.NewCheckout121()
+		player.CurrentScore = player.Checkout121.Remaining
+	}
+}
+
+// CalculateScore will add the visit to the ladder of the player. Busts still count towards the darts used on the current target
+func (r *checkout121Rules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	outshotType := models.OUTSHOTDOUBLE
+	if params != nil && params.OutshotType != nil {
+		outshotType = params.OutshotType.ID
+	}
+	player := players[visit.PlayerID]
+	player.Checkout121.AddVisit(visit, outshotType)
+	player.CurrentScore = player.Checkout121.Remaining
+	if visit.IsBust {
+		return 0
+	}
+	return visit.GetScore()
+}
+
+// InsertLegParameters will write the outshot type and number of rounds of the leg
+func (r *checkout121Rules) InsertLegParameters(tx *sql.Tx, leg *models.Leg) error {
+	return insertCheckout121LegParameters(tx, int64(leg.ID), leg.Parameters)
+}
+
+// GetLegParameters will return the outshot type and number of rounds of the leg
+func (r *checkout121Rules) GetLegParameters(legID int) (*models.LegParameters, error) {
+	return GetLegParameters(legID)
+}
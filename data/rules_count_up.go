@@ -0,0 +1,73 @@
+package data
+
+import (
+	"database/sql"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// countUpRules contains the rules for Count-Up
+type countUpRules struct {
+	baseRules
+	gameStatistics
+}
+
+// newCountUpRules will return the rules for Count-Up, which shares statistics with Shootout
+func newCountUpRules() *countUpRules {
+	return &countUpRules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_shootout"},
+		global:    func(from string, to string) (interface{}, error) { return GetShootoutStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetShootoutStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetShootoutStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetShootoutStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetCountUpHistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit does nothing, since it is not possible to bust in Count-Up
+func (r *countUpRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+}
+
+// IsLegFinished will check if all players have played the configured number of rounds
+func (r *countUpRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	return len(leg.Visits)+1 >= leg.Parameters.GetCountUpRounds()*len(leg.Players)
+}
+
+// GetWinner will return the player with the highest score
+func (r *countUpRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	return getHighScoreWinner(players)
+}
+
+// InsertStatistics will write Shootout statistics for all players in the leg, so Count-Up shares leaderboards with Shootout
+func (r *countUpRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	return insertShootoutStatistics(tx, visit.LegID)
+}
+
+// InitializeScores will set the score and darts thrown of each player to zero
+func (r *countUpRules) InitializeScores(params *models.LegParameters, players map[int]*models.Player2Leg) {
+	for _, player := range players {
+		player.CurrentScore = 0
+		player.DartsThrown = 0
+	}
+}
+
+// CalculateScore will add the score of the visit
+func (r *countUpRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	player := players[visit.PlayerID]
+	score := visit.GetScore()
+	player.CurrentScore += score
+	player.DartsThrown += 3
+	return score
+}
+
+// InsertLegParameters will write the number of rounds of the leg
+func (r *countUpRules) InsertLegParameters(tx *sql.Tx, leg *models.Leg) error {
+	_, err := tx.Exec("INSERT INTO leg_parameters (leg_id, rounds) VALUES (?, ?)", leg.ID, leg.Parameters.GetCountUpRounds())
+	return err
+}
+
+// GetLegParameters will return the number of rounds of the leg
+func (r *countUpRules) GetLegParameters(legID int) (*models.LegParameters, error) {
+	return GetLegParameters(legID)
+}
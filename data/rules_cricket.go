@@ -0,0 +1,116 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+	"math"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// cricketRules contains the rules for Cricket
+type cricketRules struct {
+	baseRules
+	gameStatistics
+}
+
+// newCricketRules will return the rules for Cricket
+func newCricketRules() *cricketRules {
+	return &cricketRules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_cricket"},
+		global:    func(from string, to string) (interface{}, error) { return GetCricketStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetCricketStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetCricketStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetCricketStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetCricketHistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit does nothing, since darts not thrown can only be known once the leg is finished, see IsLegFinished
+func (r *cricketRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+}
+
+// IsLegFinished will check if the current player has closed all numbers and has the lowest score.
+// If so, darts not thrown after the last number was closed are invalidated
+func (r *cricketRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	scores := make(map[int]*models.Player2Leg)
+	for _, playerID := range leg.Players {
+		p2l := new(models.Player2Leg)
+		p2l.Hits = make(map[int]*models.Hits)
+		scores[playerID] = p2l
+	}
+
+	// Replay all visits, including the incoming one, on a copy to not modify the given visits
+	visits := make([]*models.Visit, 0, len(leg.Visits)+1)
+	visits = append(visits, leg.Visits...)
+	for _, v := range append(visits, visit) {
+		cv := *v
+		cv.CalculateCricketScore(scores)
+	}
+
+	// Did current player close all numbers?
+	player := scores[visit.PlayerID]
+	for _, dart := range models.CRICKETDARTS {
+		if player.Hits[dart] == nil || player.Hits[dart].Total < 3 {
+			return false
+		}
+	}
+
+	// What is the lowest score?
+	lowestScore := math.MaxInt32
+	for _, p := range scores {
+		if p.CurrentScore < lowestScore {
+			lowestScore = p.CurrentScore
+		}
+	}
+	// If current player closed all numbers and has the lowest score, it's finished
+	if player.CurrentScore != lowestScore {
+		return false
+	}
+	if visit.ThirdDart.IsCricketMiss() {
+		visit.ThirdDart.Value = null.IntFromPtr(nil)
+	}
+	if visit.SecondDart.IsCricketMiss() {
+		visit.SecondDart.Value = null.IntFromPtr(nil)
+	}
+	return true
+}
+
+// GetWinner will return the player finishing the leg
+func (r *cricketRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	return null.IntFrom(int64(visit.PlayerID))
+}
+
+// InsertStatistics will write Cricket statistics for all players in the leg
+func (r *cricketRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateCricketStatistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_cricket
+				(leg_id, player_id, total_marks, rounds, score, first_nine_marks, mpr, first_nine_mpr, marks5, marks6, marks7, marks8, marks9)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, visit.LegID, playerID, stats.TotalMarks, stats.Rounds, stats.Score, stats.FirstNineMarks,
+			stats.MPR, stats.FirstNineMPR, stats.Marks5, stats.Marks6, stats.Marks7, stats.Marks8, stats.Marks9)
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting cricket statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// InitializeScores will set the score of each player to zero, with no numbers hit
+func (r *cricketRules) InitializeScores(params *models.LegParameters, players map[int]*models.Player2Leg) {
+	for _, player := range players {
+		player.CurrentScore = 0
+		player.Hits = make(map[int]*models.Hits)
+	}
+}
+
+// CalculateScore will add the marks of the visit, and give points to the players who have not closed the numbers scored on
+func (r *cricketRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	return visit.CalculateCricketScore(players)
+}
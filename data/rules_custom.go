@@ -0,0 +1,68 @@
+package data
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/kcapp/api/models"
+)
+
+// customRules contains the rules for custom games played from a game definition
+type customRules struct {
+	definitionRules
+}
+
+// newCustomRules will return the rules for custom games played from a game definition
+func newCustomRules() *customRules {
+	return &customRules{definitionRules{definition: func(params *models.LegParameters) *models.GameDefinition { return params.GameDefinition }, gameStatistics: gameStatistics{
+		tables:    []string{"statistics_custom", "statistics_custom_round"},
+		global:    func(from string, to string) (interface{}, error) { return GetCustomStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetCustomStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetCustomStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetCustomStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetCustomHistoryForPlayer(id, limit) },
+	}}}
+}
+
+// InsertStatistics will write custom game statistics, including the hit rate of each round, for all players in the leg
+func (r *customRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateCustomStatistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_custom (leg_id, player_id, game_definition_id, darts_thrown, score, hit_count, total_hit_rate)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`, visit.LegID, playerID, stats.GameDefinitionID, stats.DartsThrown, stats.Score, stats.HitCount, stats.TotalHitRate)
+		if err != nil {
+			return err
+		}
+		for round, hitrate := range stats.Hitrates {
+			_, err = tx.Exec("INSERT INTO statistics_custom_round (leg_id, player_id, round, hit_rate) VALUES (?, ?, ?, ?)", visit.LegID, playerID, round, hitrate)
+			if err != nil {
+				return err
+			}
+		}
+		log.Printf("[%d] Inserting custom game statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// ValidateLegParameters will check that the game definition played in the leg exists
+func (r *customRules) ValidateLegParameters(leg *models.Leg) error {
+	if leg.Parameters == nil || !leg.Parameters.GameDefinitionID.Valid {
+		return errors.New("game_definition_id is required for custom games")
+	}
+	_, err := GetGameDefinition(int(leg.Parameters.GameDefinitionID.Int64))
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("game definition %d does not exist", leg.Parameters.GameDefinitionID.Int64)
+	}
+	return err
+}
+
+// InsertLegParameters will write the game definition played in the leg
+func (r *customRules) InsertLegParameters(tx *sql.Tx, leg *models.Leg) error {
+	return insertCustomLegParameters(tx, int64(leg.ID), leg.Parameters)
+}
@@ -0,0 +1,89 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// dartsAtXRules contains the rules for Darts at X
+type dartsAtXRules struct {
+	baseRules
+	gameStatistics
+}
+
+// newDartsAtXRules will return the rules for Darts at X
+func newDartsAtXRules() *dartsAtXRules {
+	return &dartsAtXRules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_darts_at_x"},
+		global:    func(from string, to string) (interface{}, error) { return GetDartsAtXStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetDartsAtXStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetDartsAtXStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetDartsAtXStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetDartsAtXHistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit does nothing, since it is not possible to bust in Darts at X
+func (r *dartsAtXRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+}
+
+// IsLegFinished will check if all players have thrown the configured number of darts
+func (r *dartsAtXRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	return ((len(leg.Visits)+1)*3)%(leg.Parameters.GetDartsAtXDarts()*len(leg.Players)) == 0
+}
+
+// GetWinner will return the player with the highest score
+func (r *dartsAtXRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	return getHighScoreWinner(players)
+}
+
+// InsertStatistics will write Darts at X statistics for all players in the leg
+func (r *dartsAtXRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateDartsAtXStatistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_darts_at_x
+				(leg_id, player_id, score, singles, doubles, triples, hit_rate, hits5, hits6, hits7, hits8, hits9)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, visit.LegID, playerID, stats.Score, stats.Singles, stats.Doubles, stats.Triples, stats.HitRate,
+			stats.Hits5, stats.Hits6, stats.Hits7, stats.Hits8, stats.Hits9)
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting Darts At %d statistics for player %d", visit.LegID, leg.StartingScore, playerID)
+	}
+	return nil
+}
+
+// CalculateScore will add the multiplier of each dart hitting the target
+func (r *dartsAtXRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	player := players[visit.PlayerID]
+	score := 0
+	for _, dart := range []*models.Dart{visit.FirstDart, visit.SecondDart, visit.ThirdDart} {
+		if dart.ValueRaw() == player.StartingScore {
+			score += int(dart.Multiplier)
+		}
+	}
+	player.CurrentScore += score
+	return score
+}
+
+// ValidateLegParameters will check that the number of darts of the leg, if set, is above 0
+func (r *dartsAtXRules) ValidateLegParameters(leg *models.Leg) error {
+	return leg.Parameters.ValidateDartsAtX()
+}
+
+// InsertLegParameters will write the variant settings of the leg, if any are set
+func (r *dartsAtXRules) InsertLegParameters(tx *sql.Tx, leg *models.Leg) error {
+	return insertVariantLegParameters(tx, int64(leg.ID), leg.Parameters)
+}
+
+// GetLegParameters will return the variant settings of the leg, or nil if the defaults are used
+func (r *dartsAtXRules) GetLegParameters(legID int) (*models.LegParameters, error) {
+	return getOptionalLegParameters(legID)
+}
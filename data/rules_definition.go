@@ -0,0 +1,47 @@
+package data
+
+import (
+	"math"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// definitionRules contains the rules for round-based games played from a game definition, where each round has a target
+// and the score is calculated by the scoring rule of the definition
+type definitionRules struct {
+	baseRules
+	gameStatistics
+	definition func(params *models.LegParameters) *models.GameDefinition
+}
+
+// HandleVisit does nothing, since it is not possible to bust in games played from a game definition
+func (r *definitionRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+}
+
+// IsLegFinished will check if all players have thrown at all targets of the game definition
+func (r *definitionRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	return len(leg.Visits)+1 >= len(r.definition(leg.Parameters).Targets)*len(leg.Players)
+}
+
+// GetWinner will return the player with the highest or lowest score, according to the win condition of the game definition
+func (r *definitionRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	if r.definition(leg.Parameters).WinCondition == models.WINLOW {
+		return getLowScoreWinner(players, math.MaxInt32)
+	}
+	return getHighScoreWinner(players)
+}
+
+// CalculateScore will update the score of the player using the scoring rule of the game definition.
+// Visits after the last target are ignored
+func (r *definitionRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	player := players[visit.PlayerID]
+	current := player.CurrentScore
+	player.CurrentScore = r.definition(params).CalculateScore(visit, getVisitRound(visits, players)-1, current)
+	return player.CurrentScore - current
+}
+
+// GetLegParameters will return the targets or the game definition of the leg
+func (r *definitionRules) GetLegParameters(legID int) (*models.LegParameters, error) {
+	return GetLegParameters(legID)
+}
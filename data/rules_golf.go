@@ -0,0 +1,92 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// golfRules contains the rules for Golf
+type golfRules struct {
+	baseRules
+	gameStatistics
+}
+
+// newGolfRules will return the rules for Golf
+func newGolfRules() *golfRules {
+	return &golfRules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_golf"},
+		global:    func(from string, to string) (interface{}, error) { return GetGolfStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetGolfStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetGolfStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetGolfStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetGolfHistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit does nothing, since it is not possible to bust in Golf
+func (r *golfRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+}
+
+// IsLegFinished will check if all players have played all holes
+func (r *golfRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	return len(leg.Visits)+1 >= leg.Parameters.GetGolfHoles()*len(leg.Players)
+}
+
+// GetWinner will return the player with the fewest strokes
+func (r *golfRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	return getLowScoreWinner(players, 5*leg.Parameters.GetGolfHoles()+1)
+}
+
+// InsertStatistics will write Golf statistics for all players in the leg
+func (r *golfRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateGolfStatistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		h := make([]null.Int, 19)
+		for hole, strokes := range stats.HoleScores {
+			h[hole] = null.IntFrom(int64(strokes))
+		}
+		_, err = tx.Exec(`
+			INSERT INTO statistics_golf (leg_id, player_id, holes, score, hole_1, hole_2, hole_3, hole_4, hole_5, hole_6, hole_7, hole_8, hole_9,
+				hole_10, hole_11, hole_12, hole_13, hole_14, hole_15, hole_16, hole_17, hole_18) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+			visit.LegID, playerID, stats.Holes, stats.Score, h[1], h[2], h[3], h[4], h[5], h[6], h[7], h[8], h[9],
+			h[10], h[11], h[12], h[13], h[14], h[15], h[16], h[17], h[18])
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting Golf statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// CalculateScore will add the strokes used on the current hole, ignoring visits after the last hole
+func (r *golfRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	hole := getVisitRound(visits, players) - 1
+	if hole >= params.GetGolfHoles() {
+		return 0
+	}
+	score := visit.CalculateGolfScore(hole)
+	players[visit.PlayerID].CurrentScore += score
+	return score
+}
+
+// ValidateLegParameters will check that the number of holes of the leg, if set, is 9 or 18
+func (r *golfRules) ValidateLegParameters(leg *models.Leg) error {
+	return leg.Parameters.ValidateGolf()
+}
+
+// InsertLegParameters will write the number of holes of the leg
+func (r *golfRules) InsertLegParameters(tx *sql.Tx, leg *models.Leg) error {
+	_, err := tx.Exec("INSERT INTO leg_parameters (leg_id, holes) VALUES (?, ?)", leg.ID, leg.Parameters.GetGolfHoles())
+	return err
+}
+
+// GetLegParameters will return the number of holes of the leg
+func (r *golfRules) GetLegParameters(legID int) (*models.LegParameters, error) {
+	return GetLegParameters(legID)
+}
@@ -0,0 +1,72 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// gotchaRules contains the rules for Gotcha
+type gotchaRules struct {
+	baseRules
+	gameStatistics
+}
+
+// newGotchaRules will return the rules for Gotcha
+func newGotchaRules() *gotchaRules {
+	return &gotchaRules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_gotcha"},
+		global:    func(from string, to string) (interface{}, error) { return GetGotchaStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetGotchaStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetGotchaStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetGotchaStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetGotchaHistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit will check if the visit puts the score above the target score
+func (r *gotchaRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+	visit.SetIsBustAbove(players[visit.PlayerID].CurrentScore, leg.StartingScore)
+}
+
+// IsLegFinished will check if the player has reached the target score
+func (r *gotchaRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	score := players[visit.PlayerID].CurrentScore + visit.CalculateGotchaScore(players, leg.StartingScore)
+	return score == leg.StartingScore
+}
+
+// GetWinner will return the player finishing the leg
+func (r *gotchaRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	return null.IntFrom(int64(visit.PlayerID))
+}
+
+// InsertStatistics will write Gotcha statistics for all players in the leg
+func (r *gotchaRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateGotchaStatistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_gotcha (leg_id, player_id, darts_thrown, highest_score, times_reset, others_reset, score) VALUES (?,?,?,?,?,?,?)`,
+			visit.LegID, playerID, stats.DartsThrown, stats.HighestScore, stats.TimesReset, stats.OthersReset, stats.Score)
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting Gotcha statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// CalculateScore will add the score of the visit unless it is a bust, resetting the score of any player who was matched
+func (r *gotchaRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	if visit.IsBust {
+		return 0
+	}
+	player := players[visit.PlayerID]
+	score := visit.CalculateGotchaScore(players, player.StartingScore)
+	player.CurrentScore += score
+	return score
+}
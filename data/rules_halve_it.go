@@ -0,0 +1,60 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/kcapp/api/models"
+)
+
+// halveItRules contains the rules for Halve-It
+type halveItRules struct {
+	definitionRules
+}
+
+// newHalveItRules will return the rules for Halve-It, played as a game definition with the targets of the leg
+func newHalveItRules() *halveItRules {
+	return &halveItRules{definitionRules{definition: (*models.LegParameters).GetHalveItDefinition, gameStatistics: gameStatistics{
+		tables:    []string{"statistics_halve_it", "statistics_halve_it_round"},
+		global:    func(from string, to string) (interface{}, error) { return GetHalveItStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetHalveItStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetHalveItStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetHalveItStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetHalveItHistoryForPlayer(id, limit) },
+	}}}
+}
+
+// InsertStatistics will write Halve-It statistics, including the hit rate of each round, for all players in the leg
+func (r *halveItRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateHalveItStatistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_halve_it (leg_id, player_id, darts_thrown, score, mpr, total_marks, highest_score_reached, times_halved, total_hit_rate, hit_count)
+			VALUES (?,?,?,?,?,?,?,?,?,?)`, visit.LegID, playerID, stats.DartsThrown, stats.Score, stats.MPR, stats.TotalMarks, stats.HighestScoreReached,
+			stats.TimesHalved, stats.TotalHitRate, stats.HitCount)
+		if err != nil {
+			return err
+		}
+		for round, hitrate := range stats.Hitrates {
+			_, err = tx.Exec("INSERT INTO statistics_halve_it_round (leg_id, player_id, round, hit_rate) VALUES (?, ?, ?, ?)", visit.LegID, playerID, round, hitrate)
+			if err != nil {
+				return err
+			}
+		}
+		log.Printf("[%d] Inserting Halve-It statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// ValidateLegParameters will check that all targets given for the leg can be hit
+func (r *halveItRules) ValidateLegParameters(leg *models.Leg) error {
+	return leg.Parameters.ValidateHalveItTargets()
+}
+
+// InsertLegParameters will write the target of each round of the leg
+func (r *halveItRules) InsertLegParameters(tx *sql.Tx, leg *models.Leg) error {
+	return insertHalveItLegParameters(tx, int64(leg.ID), leg.Parameters)
+}
@@ -0,0 +1,66 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// jdcPracticeRules contains the rules for JDC Practice Routine
+type jdcPracticeRules struct {
+	baseRules
+	gameStatistics
+}
+
+// newJDCPracticeRules will return the rules for JDC Practice
+func newJDCPracticeRules() *jdcPracticeRules {
+	return &jdcPracticeRules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_jdc_practice"},
+		global:    func(from string, to string) (interface{}, error) { return GetJDCPracticeStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetJDCPracticeStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetJDCPracticeStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetJDCPracticeStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetJDCPracticeHistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit does nothing, since it is not possible to bust in JDC Practice
+func (r *jdcPracticeRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+}
+
+// IsLegFinished will check if all players have thrown all 19 rounds
+func (r *jdcPracticeRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	return (len(leg.Visits)+1)%(19*len(leg.Players)) == 0
+}
+
+// GetWinner will return the player with the highest score
+func (r *jdcPracticeRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	return getHighScoreWinner(players)
+}
+
+// InsertStatistics will write JDC Practice statistics for all players in the leg
+func (r *jdcPracticeRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateJDCPracticeStatistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_jdc_practice (leg_id, player_id, darts_thrown, score, mpr, shanghai_count, doubles_hitrate) VALUES (?,?,?,?,?,?,?)`,
+			visit.LegID, playerID, stats.DartsThrown, stats.Score, stats.MPR, stats.ShanghaiCount, stats.DoublesHitrate)
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting JDC Practice statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// CalculateScore will add the score of darts hitting the targets of the round
+func (r *jdcPracticeRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	score := visit.CalculateJDCPracticeScore(getVisitRound(visits, players) - 1)
+	players[visit.PlayerID].CurrentScore += score
+	return score
+}
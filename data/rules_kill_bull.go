@@ -0,0 +1,106 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// killBullRules contains the rules for Kill Bull
+type killBullRules struct {
+	baseRules
+	gameStatistics
+}
+
+// newKillBullRules will return the rules for Kill Bull
+func newKillBullRules() *killBullRules {
+	return &killBullRules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_kill_bull"},
+		global:    func(from string, to string) (interface{}, error) { return GetKillBullStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetKillBullStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetKillBullStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetKillBullStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetKillBullHistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit will invalidate darts thrown after the score reached zero
+func (r *killBullRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+	if r.IsLegFinished(leg, players, visit) {
+		if !visit.ThirdDart.IsBull() {
+			visit.ThirdDart.Value = null.IntFromPtr(nil)
+			if !visit.SecondDart.IsBull() {
+				visit.SecondDart.Value = null.IntFromPtr(nil)
+			}
+		}
+	}
+}
+
+// IsLegFinished will check if the player has reached zero. A visit missing the bull puts the player back to the starting score
+// of the leg, so it can only finish the leg if the starting score is zero or less
+func (r *killBullRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	score := visit.CalculateKillBullScore()
+	if score == 0 {
+		return leg.Parameters.GetKillBullStartingScore(leg.StartingScore) <= 0
+	}
+	return players[visit.PlayerID].CurrentScore-score <= 0
+}
+
+// GetWinner will return the player finishing the leg
+func (r *killBullRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	return null.IntFrom(int64(visit.PlayerID))
+}
+
+// InsertStatistics will write Kill Bull statistics for all players in the leg
+func (r *killBullRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateKillBullStatistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_kill_bull (leg_id, player_id, darts_thrown, score, marks3, marks4, marks5, marks6, longest_streak, times_busted, total_hit_rate) VALUES (?,?,?,?,?,?,?,?,?,?,?)`,
+			visit.LegID, playerID, stats.DartsThrown, stats.Score, stats.Marks3, stats.Marks4, stats.Marks5, stats.Marks6, stats.LongestStreak, stats.TimesBusted, stats.TotalHitRate)
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting Kill Bull statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// InitializeScores will set the score of each player to the starting score of the leg parameters, or of the leg if not set
+func (r *killBullRules) InitializeScores(params *models.LegParameters, players map[int]*models.Player2Leg) {
+	for _, player := range players {
+		player.CurrentScore = params.GetKillBullStartingScore(player.StartingScore)
+	}
+}
+
+// CalculateScore will subtract the score of bulls hit, or reset the score of the player to the starting score if the bull was missed
+func (r *killBullRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	player := players[visit.PlayerID]
+	score := visit.CalculateKillBullScore()
+	if score == 0 {
+		player.CurrentScore = params.GetKillBullStartingScore(player.StartingScore)
+	} else {
+		player.CurrentScore -= score
+	}
+	return score
+}
+
+// ValidateLegParameters will check that the starting score of the leg, if set, is above 0
+func (r *killBullRules) ValidateLegParameters(leg *models.Leg) error {
+	return leg.Parameters.ValidateKillBull()
+}
+
+// InsertLegParameters will write the starting score of the leg, if set
+func (r *killBullRules) InsertLegParameters(tx *sql.Tx, leg *models.Leg) error {
+	return insertKillBullLegParameters(tx, int64(leg.ID), leg.Parameters)
+}
+
+// GetLegParameters will return the starting score of the leg, or nil if the starting score of the leg is used
+func (r *killBullRules) GetLegParameters(legID int) (*models.LegParameters, error) {
+	return getOptionalLegParameters(legID)
+}
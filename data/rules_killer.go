@@ -0,0 +1,112 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// killerRules contains the rules for Killer
+type killerRules struct {
+	baseRules
+	gameStatistics
+}
+
+// newKillerRules will return the rules for Killer
+func newKillerRules() *killerRules {
+	return &killerRules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_killer"},
+		global:    func(from string, to string) (interface{}, error) { return GetKillerStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetKillerStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetKillerStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetKillerStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetKillerHistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit will make the player a killer, or take lives from other players, based on the doubles hit
+func (r *killerRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+	visit.CalculateKillerScore(players, leg.Parameters)
+}
+
+// IsLegFinished will check if only one player has lives left
+func (r *killerRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	playersAlive := 0
+	for _, player := range players {
+		if player.Lives.Int64 > 0 {
+			playersAlive++
+		}
+	}
+	return playersAlive < 2
+}
+
+// GetWinner will return the last player with lives left
+func (r *killerRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	winnerID := null.IntFrom(int64(visit.PlayerID))
+	for _, player := range players {
+		if player.Lives.Int64 > 0 {
+			winnerID = null.IntFrom(int64(player.PlayerID))
+		}
+	}
+	return winnerID
+}
+
+// InsertStatistics will write Killer statistics for all players in the leg
+func (r *killerRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateKillerStatistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_killer (leg_id, player_id, darts_thrown, darts_to_become_killer, lives_lost, lives_taken, final_position) VALUES (?,?,?,?,?,?,?)`,
+			visit.LegID, playerID, stats.DartsThrown, stats.DartsToBecomeKiller, stats.LivesLost, stats.LivesTaken, stats.FinalPosition)
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting Killer statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// IsPlayerOut will check if the given player has no lives left
+func (r *killerRules) IsPlayerOut(player *models.Player2Leg, visit models.Visit) bool {
+	return player.Lives.Int64 < 1 && player.PlayerID != visit.PlayerID
+}
+
+// InitializeScores will give each player the starting lives of the leg, with no killers
+func (r *killerRules) InitializeScores(params *models.LegParameters, players map[int]*models.Player2Leg) {
+	for _, player := range players {
+		player.CurrentScore = 0
+		player.Lives = params.StartingLives
+	}
+	params.Killers = make(map[int]bool)
+}
+
+// CalculateScore will add the lives gained by the player, and take lives from players whose number was hit by a killer
+func (r *killerRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	score := visit.CalculateKillerScore(players, params)
+	players[visit.PlayerID].CurrentScore += score
+	return score
+}
+
+// ValidateLegParameters will check that each player can be assigned a unique number
+func (r *killerRules) ValidateLegParameters(leg *models.Leg) error {
+	if len(leg.Players) > models.KillerMaxPlayers {
+		return fmt.Errorf("killer supports at most %d players", models.KillerMaxPlayers)
+	}
+	return nil
+}
+
+// InsertLegParameters will write the starting lives, and a randomly assigned number for each player of the leg
+func (r *killerRules) InsertLegParameters(tx *sql.Tx, leg *models.Leg) error {
+	return insertKillerLegParameters(tx, int64(leg.ID), leg.Parameters, leg.Players)
+}
+
+// GetLegParameters will return the starting lives and the number of each player of the leg
+func (r *killerRules) GetLegParameters(legID int) (*models.LegParameters, error) {
+	return GetLegParameters(legID)
+}
@@ -0,0 +1,125 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// knockoutRules contains the rules for Knockout
+type knockoutRules struct {
+	baseRules
+	gameStatistics
+}
+
+// newKnockoutRules will return the rules for Knockout
+func newKnockoutRules() *knockoutRules {
+	return &knockoutRules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_knockout"},
+		global:    func(from string, to string) (interface{}, error) { return GetKnockoutStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetKnockoutStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetKnockoutStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetKnockoutStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetKnockoutHistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit does nothing, since it is not possible to bust in Knockout
+func (r *knockoutRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+}
+
+// IsLegFinished will take a life from the player if they scored less than the previous player, and check if only one player is left
+func (r *knockoutRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	idx := len(leg.Visits) - 1
+	if idx < 0 {
+		return false
+	}
+	if leg.Visits[idx].GetScore() > visit.GetScore() {
+		players[visit.PlayerID].Lives = null.IntFrom(players[visit.PlayerID].Lives.Int64 - 1)
+	}
+	playersAlive := 0
+	for _, player := range players {
+		if player.Lives.Int64 > 0 {
+			playersAlive++
+		}
+	}
+	return playersAlive < 2
+}
+
+// GetWinner will return the last player with lives left
+func (r *knockoutRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	winnerID := null.IntFrom(int64(visit.PlayerID))
+	for _, player := range players {
+		if player.Lives.Int64 > 0 {
+			winnerID = null.IntFrom(int64(player.PlayerID))
+		}
+	}
+	return winnerID
+}
+
+// InsertStatistics will write Knockout statistics for all players in the leg
+func (r *knockoutRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateKnockoutStatistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_knockout (leg_id, player_id, darts_thrown, avg_score, lives_lost, lives_taken, final_position) VALUES (?,?,?,?,?,?,?)`,
+			visit.LegID, playerID, stats.DartsThrown, stats.AvgScore, stats.LivesLost, stats.LivesTaken, stats.FinalPosition)
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting Knockout statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// IsPlayerOut will check if the given player has no lives left
+func (r *knockoutRules) IsPlayerOut(player *models.Player2Leg, visit models.Visit) bool {
+	return player.Lives.Int64 < 1 && player.PlayerID != visit.PlayerID
+}
+
+// InitializeScores will give each player the starting lives of the leg, with no darts thrown
+func (r *knockoutRules) InitializeScores(params *models.LegParameters, players map[int]*models.Player2Leg) {
+	for _, player := range players {
+		player.CurrentScore = 0
+		player.DartsThrown = 0
+		player.Lives = params.StartingLives
+	}
+}
+
+// CalculateScore will set the score of the player to the score of the visit, and take a life if it was lower than the previous visit
+func (r *knockoutRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	player := players[visit.PlayerID]
+	player.CurrentScore = visit.GetScore()
+	if len(visits) > 0 {
+		previous := visits[len(visits)-1]
+		if previous.GetScore() > visit.GetScore() {
+			player.Lives = null.IntFrom(player.Lives.Int64 - 1)
+		}
+		players[previous.PlayerID].CurrentScore = 0
+	}
+	// Players can be knocked out, so darts thrown is counted for each player
+	player.DartsThrown += 3
+	visit.DartsThrown = player.DartsThrown
+	return visit.GetScore()
+}
+
+// ValidateLegParameters will check that only the starting lives are set for the leg
+func (r *knockoutRules) ValidateLegParameters(leg *models.Leg) error {
+	return leg.Parameters.ValidateKnockout()
+}
+
+// InsertLegParameters will write the starting lives of the leg
+func (r *knockoutRules) InsertLegParameters(tx *sql.Tx, leg *models.Leg) error {
+	_, err := tx.Exec("INSERT INTO leg_parameters (leg_id, starting_lives) VALUES (?, ?)", leg.ID, leg.Parameters.StartingLives)
+	return err
+}
+
+// GetLegParameters will return the starting lives of the leg
+func (r *knockoutRules) GetLegParameters(legID int) (*models.LegParameters, error) {
+	return GetLegParameters(legID)
+}
@@ -0,0 +1,97 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// shootoutRules contains the rules for 9 Dart Shootout
+type shootoutRules struct {
+	baseRules
+	gameStatistics
+}
+
+// newShootoutRules will return the rules for 9 Dart Shootout
+func newShootoutRules() *shootoutRules {
+	return &shootoutRules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_shootout"},
+		global:    func(from string, to string) (interface{}, error) { return GetShootoutStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetShootoutStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetShootoutStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetShootoutStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetShootoutHistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit does nothing, since it is not possible to bust in Shootout
+func (r *shootoutRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+}
+
+// IsLegFinished will check if all players have thrown 9 darts, and handle draws in legs with two players
+func (r *shootoutRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	isFinished := ((len(leg.Visits) + 1) * 3) >= (9 * len(leg.Players))
+	if isFinished {
+		// Handle draw in legs with two players
+		players[visit.PlayerID].CurrentScore += visit.GetScore()
+		players[visit.PlayerID].DartsThrown += 3
+
+		if len(players) == 2 {
+			scores := make([]*models.Player2Leg, 0, len(players))
+			for _, player := range players {
+				scores = append(scores, player)
+			}
+			// If both players have thrown the same amount of darts, and have different scores, game is finished
+			isFinished = scores[0].DartsThrown == scores[1].DartsThrown && scores[0].CurrentScore != scores[1].CurrentScore
+		}
+	}
+	return isFinished
+}
+
+// GetWinner will return the player with the highest score
+func (r *shootoutRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	return getHighScoreWinner(players)
+}
+
+// InsertStatistics will write Shootout statistics for all players in the leg
+func (r *shootoutRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	return insertShootoutStatistics(tx, visit.LegID)
+}
+
+// insertShootoutStatistics will write Shootout statistics for all players in the given leg, shared by Shootout and Count-Up
+func insertShootoutStatistics(tx *sql.Tx, legID int) error {
+	statisticsMap, err := CalculateShootoutStatistics(legID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_shootout(leg_id, player_id, score, ppd, 60s_plus, 100s_plus, 140s_plus, 180s)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, legID, playerID, stats.Score, stats.PPD, stats.Score60sPlus,
+			stats.Score100sPlus, stats.Score140sPlus, stats.Score180s)
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting shootout statistics for player %d", legID, playerID)
+	}
+	return nil
+}
+
+// InitializeScores will set the score and darts thrown of each player to zero
+func (r *shootoutRules) InitializeScores(params *models.LegParameters, players map[int]*models.Player2Leg) {
+	for _, player := range players {
+		player.CurrentScore = 0
+		player.DartsThrown = 0
+	}
+}
+
+// CalculateScore will add the score of the visit
+func (r *shootoutRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	player := players[visit.PlayerID]
+	score := visit.GetScore()
+	player.CurrentScore += score
+	player.DartsThrown += 3
+	return score
+}
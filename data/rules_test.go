@@ -0,0 +1,49 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCalculatePlayersScoreDoubleIn will check that scoring the same players twice, as a replay does, gives the same double in score
+func TestCalculatePlayersScoreDoubleIn(t *testing.T) {
+	params := &models.LegParameters{InshotType: &models.OutshotType{ID: models.OUTSHOTDOUBLE}}
+	visits := []*models.Visit{
+		{PlayerID: 1, FirstDart: models.NewDart(null.IntFrom(20), models.SINGLE), SecondDart: models.NewDart(null.IntFrom(20), models.SINGLE),
+			ThirdDart: models.NewDart(null.IntFrom(20), models.SINGLE)},
+		{PlayerID: 1, FirstDart: models.NewDart(null.IntFrom(20), models.DOUBLE), SecondDart: models.NewDart(null.IntFrom(20), models.SINGLE),
+			ThirdDart: models.NewDart(null.IntFrom(20), models.SINGLE)},
+	}
+	players := map[int]*models.Player2Leg{1: {PlayerID: 1, StartingScore: 501}}
+	rules := newX01Rules(models.X01)
+
+	calculatePlayersScore(players, rules, params, visits)
+	assert.Equal(t, players[1].CurrentScore, 421, "score should be 421")
+	calculatePlayersScore(players, rules, params, visits)
+	assert.Equal(t, players[1].CurrentScore, 421, "score should still be 421")
+	assert.Equal(t, players[1].DartsToGetIn, null.IntFrom(4), "should need 4 darts to get in")
+}
+
+// TestCalculatePlayersScoreKnockout will check that scoring the same players twice, as a replay does, counts the darts thrown once
+func TestCalculatePlayersScoreKnockout(t *testing.T) {
+	params := &models.LegParameters{StartingLives: null.IntFrom(3)}
+	visits := []*models.Visit{
+		{PlayerID: 1, FirstDart: models.NewDart(null.IntFrom(20), models.SINGLE), SecondDart: models.NewDart(null.IntFrom(20), models.SINGLE),
+			ThirdDart: models.NewDart(null.IntFrom(20), models.SINGLE)},
+		{PlayerID: 2, FirstDart: models.NewDart(null.IntFrom(1), models.SINGLE), SecondDart: models.NewDart(null.IntFrom(1), models.SINGLE),
+			ThirdDart: models.NewDart(null.IntFrom(1), models.SINGLE)},
+	}
+	players := map[int]*models.Player2Leg{1: {PlayerID: 1}, 2: {PlayerID: 2}}
+	rules := newKnockoutRules()
+
+	calculatePlayersScore(players, rules, params, visits)
+	replay := copyPlayers(players)
+	calculatePlayersScore(replay, rules, params, visits)
+	assert.Equal(t, replay[1].DartsThrown, 3, "player 1 should have thrown 3 darts")
+	assert.Equal(t, replay[2].DartsThrown, 3, "player 2 should have thrown 3 darts")
+	assert.Equal(t, visits[1].DartsThrown, 3, "visit should count 3 darts thrown by the player")
+	assert.Equal(t, replay[2].Lives, null.IntFrom(2), "player 2 should have lost a life")
+}
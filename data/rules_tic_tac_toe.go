@@ -0,0 +1,107 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// ticTacToeRules contains the rules for Tic-Tac-Toe
+type ticTacToeRules struct {
+	baseRules
+	gameStatistics
+}
+
+// newTicTacToeRules will return the rules for Tic-Tac-Toe
+func newTicTacToeRules() *ticTacToeRules {
+	return &ticTacToeRules{gameStatistics: gameStatistics{
+		tables:    []string{"statistics_tic_tac_toe"},
+		global:    func(from string, to string) (interface{}, error) { return GetTicTacToeStatistics(from, to) },
+		forLeg:    func(id int) (interface{}, error) { return GetTicTacToeStatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetTicTacToeStatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetTicTacToeStatisticsForPlayer(id) },
+		history:   func(id int, limit int) (interface{}, error) { return GetTicTacToeHistoryForPlayer(id, limit) },
+	}}
+}
+
+// HandleVisit will claim the number hit by the visit, and invalidate darts thrown after it was hit
+func (r *ticTacToeRules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+	params := leg.Parameters
+
+	lastDartValid := visit.GetLastDart().IsValidOutshot(params.OutshotType.ID)
+	for _, num := range params.Numbers {
+		// Check if we hit the exact number, ending with a double
+		if num == visit.GetScore() && lastDartValid {
+			if visit.ThirdDart.IsMiss() {
+				visit.ThirdDart.Value = null.IntFromPtr(nil)
+				if visit.SecondDart.IsMiss() {
+					visit.SecondDart.Value = null.IntFromPtr(nil)
+				}
+			}
+			params.Hits[num] = visit.PlayerID
+			break
+		}
+	}
+}
+
+// IsLegFinished will check if current player has 3 in a row horizontally, diagonally or vertically, or if the board is a draw
+func (r *ticTacToeRules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	params := leg.Parameters
+	return params.IsTicTacToeWinner(visit.PlayerID) || params.IsTicTacToeDraw() || len(params.Hits) == 9
+}
+
+// GetWinner will return the current player if they got three in a row, otherwise the leg is a draw
+func (r *ticTacToeRules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	if !leg.Parameters.IsTicTacToeWinner(visit.PlayerID) {
+		return null.IntFromPtr(nil)
+	}
+	return null.IntFrom(int64(visit.PlayerID))
+}
+
+// InsertStatistics will write Tic-Tac-Toe statistics for all players in the leg
+func (r *ticTacToeRules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateTicTacToeStatistics(visit.LegID)
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_tic_tac_toe (leg_id, player_id, darts_thrown, score, numbers_closed, highest_closed) VALUES (?,?,?,?,?,?)`, visit.LegID,
+			playerID, stats.DartsThrown, stats.Score, stats.NumbersClosed, stats.HighestClosed)
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting Tic Tac Toe statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// CalculateScore will add the number hit by the visit, if it ended with a valid outshot
+func (r *ticTacToeRules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	score := 0
+	lastDartValid := visit.GetLastDart().IsValidOutshot(params.OutshotType.ID)
+	for _, num := range params.Numbers {
+		if num == visit.GetScore() && lastDartValid {
+			score = num
+			break
+		}
+	}
+	players[visit.PlayerID].CurrentScore += score
+	return score
+}
+
+// InsertLegParameters will generate and write the numbers of the board, and the outshot type of the leg
+func (r *ticTacToeRules) InsertLegParameters(tx *sql.Tx, leg *models.Leg) error {
+	params := leg.Parameters
+	params.GenerateTicTacToeNumbers(leg.StartingScore)
+	_, err := tx.Exec("INSERT INTO leg_parameters (leg_id, outshot_type_id, number_1, number_2, number_3, number_4, number_5, number_6, number_7, number_8, number_9) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		leg.ID, params.OutshotType.ID, params.Numbers[0], params.Numbers[1], params.Numbers[2], params.Numbers[3], params.Numbers[4], params.Numbers[5], params.Numbers[6], params.Numbers[7], params.Numbers[8])
+	return err
+}
+
+// GetLegParameters will return the numbers of the board, and the outshot type of the leg
+func (r *ticTacToeRules) GetLegParameters(legID int) (*models.LegParameters, error) {
+	return GetLegParameters(legID)
+}
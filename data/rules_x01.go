@@ -0,0 +1,117 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// x01Rules contains the rules for X01 and X01 Handicap
+type x01Rules struct {
+	baseRules
+	gameStatistics
+	matchType int
+}
+
+// newX01Rules will return the rules for the given X01 match type
+func newX01Rules(matchType int) *x01Rules {
+	return &x01Rules{matchType: matchType, gameStatistics: gameStatistics{
+		tables: []string{"statistics_x01"},
+		global: func(from string, to string) (interface{}, error) {
+			return GetX01Statistics(from, to, matchType, 301, 501)
+		},
+		forLeg:    func(id int) (interface{}, error) { return GetX01StatisticsForLeg(id) },
+		forMatch:  func(id int) (interface{}, error) { return GetX01StatisticsForMatch(id) },
+		forPlayer: func(id int) (interface{}, error) { return GetX01StatisticsForPlayer(id, matchType) },
+		history:   func(id int, limit int) (interface{}, error) { return GetX01HistoryForPlayer(id, limit, matchType) },
+	}}
+}
+
+// HandleVisit will check if the visit is a bust, according to the outshot type of the leg. Darts thrown before the player is in are kept as thrown
+func (r *x01Rules) HandleVisit(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) {
+	inshotType := leg.GetInshotTypeID()
+	if inshotType != models.OUTSHOTANY && !models.GetDartsToGetIn(leg.Visits, visit.PlayerID, inshotType).Valid {
+		visit.SetIsBustBeforeIn(players[visit.PlayerID].CurrentScore, leg.GetOutshotTypeID(), inshotType)
+	} else {
+		visit.SetIsBust(players[visit.PlayerID].CurrentScore, leg.GetOutshotTypeID())
+	}
+}
+
+// IsLegFinished will check if the visit is a checkout
+func (r *x01Rules) IsLegFinished(leg *models.Leg, players map[int]*models.Player2Leg, visit *models.Visit) bool {
+	currentScore := players[visit.PlayerID].CurrentScore
+	inshotType := leg.GetInshotTypeID()
+	if inshotType != models.OUTSHOTANY && !models.GetDartsToGetIn(leg.Visits, visit.PlayerID, inshotType).Valid {
+		// Darts thrown before the player is in do not score
+		currentScore += visit.GetScoreBeforeIn(inshotType)
+	}
+	return !visit.IsBust && visit.IsCheckout(currentScore, leg.GetOutshotTypeID())
+}
+
+// GetWinner will return the player checking out
+func (r *x01Rules) GetWinner(leg *models.Leg, players map[int]*models.Player2Leg, visit models.Visit) null.Int {
+	return null.IntFrom(int64(visit.PlayerID))
+}
+
+// InsertStatistics will write X01 statistics for all players in the leg
+func (r *x01Rules) InsertStatistics(tx *sql.Tx, leg *models.Leg, visit models.Visit) error {
+	statisticsMap, err := CalculateX01Statistics(visit.LegID, visit.PlayerID, leg.StartingScore, leg.GetOutshotTypeID(), leg.GetInshotTypeID())
+	if err != nil {
+		return err
+	}
+	for playerID, stats := range statisticsMap {
+		_, err = tx.Exec(`
+			INSERT INTO statistics_x01
+				(leg_id, player_id, ppd, ppd_score, first_nine_ppd, first_nine_ppd_score, checkout_percentage, checkout_attempts, darts_thrown, 60s_plus,
+				 100s_plus, 140s_plus, 180s, accuracy_20, accuracy_19, overall_accuracy, darts_to_get_in, double_in_percentage)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, visit.LegID, playerID, stats.PPD, stats.PPDScore, stats.FirstNinePPD, stats.FirstNinePPDScore,
+			stats.CheckoutPercentage, stats.CheckoutAttempts, stats.DartsThrown, stats.Score60sPlus, stats.Score100sPlus, stats.Score140sPlus,
+			stats.Score180s, stats.AccuracyStatistics.Accuracy20, stats.AccuracyStatistics.Accuracy19, stats.AccuracyStatistics.AccuracyOverall,
+			stats.DartsToGetIn, stats.DoubleInPercentage)
+		if err != nil {
+			return err
+		}
+		log.Printf("[%d] Inserting x01 statistics for player %d", visit.LegID, playerID)
+	}
+	return nil
+}
+
+// InitializeScores will set the score of each player to the starting score, including the handicap of the player for X01 Handicap,
+// and reset the darts used to get in
+func (r *x01Rules) InitializeScores(params *models.LegParameters, players map[int]*models.Player2Leg) {
+	for _, player := range players {
+		player.CurrentScore = player.StartingScore
+		player.DartsToGetIn = null.Int{}
+		if r.matchType == models.X01HANDICAP && player.Handicap.Valid {
+			player.CurrentScore += int(player.Handicap.Int64)
+		}
+	}
+}
+
+// CalculateScore will subtract the score of the visit unless it is a bust, and keep track of the darts used by the player to get in.
+// Darts thrown before the player is in do not score
+func (r *x01Rules) CalculateScore(params *models.LegParameters, players map[int]*models.Player2Leg, visits []*models.Visit, visit *models.Visit) int {
+	player := players[visit.PlayerID]
+	score := visit.GetScore()
+	if params != nil && params.InshotType != nil && params.InshotType.ID != models.OUTSHOTANY && !player.DartsToGetIn.Valid {
+		score -= visit.GetScoreBeforeIn(params.InshotType.ID)
+		player.DartsToGetIn = models.GetDartsToGetIn(append(visits[:len(visits):len(visits)], visit), visit.PlayerID, params.InshotType.ID)
+	}
+	if visit.IsBust {
+		return 0
+	}
+	player.CurrentScore -= score
+	return score
+}
+
+// InsertLegParameters will write the outshot and inshot type of the leg, if any are set
+func (r *x01Rules) InsertLegParameters(tx *sql.Tx, leg *models.Leg) error {
+	return insertX01LegParameters(tx, int64(leg.ID), leg.Parameters)
+}
+
+// GetLegParameters will return the outshot and inshot type of the leg, or nil if the defaults are used
+func (r *x01Rules) GetLegParameters(legID int) (*models.LegParameters, error) {
+	return getOptionalLegParameters(legID)
+}
@@ -3,11 +3,9 @@ package data
 import (
 	"errors"
 	"log"
-	"math"
 	"sort"
 	"sync"
 
-	"github.com/guregu/null"
 	"github.com/kcapp/api/models"
 )
 
@@ -34,6 +32,10 @@ func AddVisit(visit models.Visit) (*models.Visit, error) {
 	if err != nil {
 		return nil, err
 	}
+	err = match.SetThrower(&visit, leg.Visits)
+	if err != nil {
+		return nil, err
+	}
 
 	players, err := GetPlayersScore(visit.LegID)
 	if err != nil {
@@ -45,149 +47,15 @@ func AddVisit(visit models.Visit) (*models.Visit, error) {
 		matchType = leg.LegType.ID
 	}
 
-	isFinished := false
-	// Invalidate extra darts not thrown, and check if leg is finished
-	if matchType == models.X01 || matchType == models.X01HANDICAP {
-		visit.SetIsBust(players[visit.PlayerID].CurrentScore)
-		isFinished = !visit.IsBust && visit.IsCheckout(players[visit.PlayerID].CurrentScore)
-	} else if matchType == models.SHOOTOUT {
-		isFinished = ((len(leg.Visits) + 1) * 3) >= (9 * len(leg.Players))
-		if isFinished {
-			// Handle draw in legs with two players
-			players[visit.PlayerID].CurrentScore += visit.GetScore()
-			players[visit.PlayerID].DartsThrown += 3
-
-			if len(players) == 2 {
-				scores := make([]*models.Player2Leg, 0, len(players))
-				for _, player := range players {
-					scores = append(scores, player)
-				}
-				// If both players have thrown the same amount of darts, and have different scores, game is finished
-				isFinished = scores[0].DartsThrown == scores[1].DartsThrown && scores[0].CurrentScore != scores[1].CurrentScore
-			}
-		}
-	} else if matchType == models.CRICKET {
-		isFinished, err = isCricketLegFinished(visit)
-		if err != nil {
-			return nil, err
-		}
-		if isFinished {
-			if visit.ThirdDart.IsCricketMiss() {
-				visit.ThirdDart.Value = null.IntFromPtr(nil)
-			}
-			if visit.SecondDart.IsCricketMiss() {
-				visit.SecondDart.Value = null.IntFromPtr(nil)
-			}
-		}
-	} else if matchType == models.DARTSATX {
-		isFinished = ((len(leg.Visits)+1)*3)%(99*len(leg.Players)) == 0
-	} else if matchType == models.AROUNDTHEWORLD {
-		isFinished = (len(leg.Visits)+1)%(21*len(leg.Players)) == 0
-	} else if matchType == models.AROUNDTHECLOCK {
-		players[visit.PlayerID].CurrentScore += visit.CalculateAroundTheClockScore(players[visit.PlayerID].CurrentScore)
-		if players[visit.PlayerID].CurrentScore == 21 {
-			if visit.FirstDart.IsBull() {
-				visit.SecondDart.Value = null.IntFromPtr(nil)
-				visit.ThirdDart.Value = null.IntFromPtr(nil)
-			} else if visit.SecondDart.IsBull() {
-				visit.ThirdDart.Value = null.IntFromPtr(nil)
-			}
-		}
-		isFinished = players[visit.PlayerID].CurrentScore == 21 && (visit.FirstDart.IsBull() || visit.SecondDart.IsBull() || visit.ThirdDart.IsBull())
-	} else if matchType == models.SHANGHAI {
-		round := int(math.Floor(float64(len(leg.Visits))/float64(len(leg.Players))) + 1)
-		isFinished = (len(leg.Visits)+1)%(20*len(leg.Players)) == 0 || (visit.IsShanghai() && visit.FirstDart.ValueRaw() == round)
-	} else if matchType == models.TICTACTOE {
-		numbers := leg.Parameters.Numbers
-		hits := leg.Parameters.Hits
-
-		lastDartValid := visit.GetLastDart().IsDouble()
-		if leg.Parameters.OutshotType.ID == models.OUTSHOTANY {
-			lastDartValid = true
-		} else if leg.Parameters.OutshotType.ID == models.OUTSHOTMASTER {
-			lastDartValid = visit.GetLastDart().IsDouble() || visit.GetLastDart().IsTriple()
-		}
-		for _, num := range numbers {
-			// Check if we hit the exact number, ending with a double
-			if num == visit.GetScore() && lastDartValid {
-				if visit.ThirdDart.IsMiss() {
-					visit.ThirdDart.Value = null.IntFromPtr(nil)
-					if visit.SecondDart.IsMiss() {
-						visit.SecondDart.Value = null.IntFromPtr(nil)
-					}
-				}
-				hits[num] = visit.PlayerID
-				break
-			}
-		}
-		// Check if current player has 3 in a row horizontally, diagonally or vertically
-		if leg.Parameters.IsTicTacToeWinner(visit.PlayerID) {
-			isFinished = true
-		} else if leg.Parameters.IsTicTacToeDraw() || len(hits) == 9 {
-			isFinished = true
-		}
-	} else if matchType == models.BERMUDATRIANGLE {
-		isFinished = ((len(leg.Visits)+1)*3)%(39*len(leg.Players)) == 0
-	} else if matchType == models.FOURTWENTY {
-		isFinished = ((len(leg.Visits)+1)*3)%(63*len(leg.Players)) == 0
-	} else if matchType == models.KILLBULL {
-		score := players[visit.PlayerID].CurrentScore - visit.CalculateKillBullScore()
-		if score <= 0 {
-			if !visit.ThirdDart.IsBull() {
-				visit.ThirdDart.Value = null.IntFromPtr(nil)
-				if !visit.SecondDart.IsBull() {
-					visit.SecondDart.Value = null.IntFromPtr(nil)
-				}
-			}
-			isFinished = true
-		}
-	} else if matchType == models.GOTCHA {
-		visit.SetIsBustAbove(players[visit.PlayerID].CurrentScore, leg.StartingScore)
-		score := players[visit.PlayerID].CurrentScore + visit.CalculateGotchaScore(players, leg.StartingScore)
-		if score == leg.StartingScore {
-			isFinished = true
-		}
-	} else if matchType == models.JDCPRACTICE {
-		isFinished = (len(leg.Visits)+1)%(19*len(leg.Players)) == 0
-	} else if matchType == models.KNOCKOUT {
-		idx := len(leg.Visits) - 1
-		if idx >= 0 {
-			if leg.Visits[idx].Score > visit.GetScore() {
-				players[visit.PlayerID].Lives = null.IntFrom(players[visit.PlayerID].Lives.Int64 - 1)
-			}
-			playersAlive := 0
-			for _, player := range players {
-				if player.Lives.Int64 > 0 {
-					playersAlive++
-				}
-			}
-			isFinished = playersAlive < 2
-		}
-	}
-
-	// Determine who will be the next player
-	order := make(map[int]int)
-	for _, player := range players {
-		if !player.IsOut(matchType, visit) {
-			order[player.Order] = player.PlayerID
-		}
-	}
-
-	// Set new player order on remaining players, from 1 to n
-	for i, key := range getKeys(order) {
-		players[order[key]].Order = i + 1
+	rules, err := models.GetGameRules(matchType)
+	if err != nil {
+		return nil, err
 	}
+	// Invalidate extra darts not thrown, and check if leg is finished
+	rules.HandleVisit(leg, players, &visit)
+	isFinished := rules.IsLegFinished(leg, players, &visit)
 
-	newOrder := make(map[int]int)
-	currentPlayerOrder := 1
-	for _, playerID := range order {
-		player := players[playerID]
-		if playerID == visit.PlayerID {
-			currentPlayerOrder = player.Order
-		}
-		newOrder[player.Order] = player.PlayerID
-	}
-	nextPlayerID := newOrder[(currentPlayerOrder%len(newOrder))+1]
+	nextPlayerID := getNextPlayerID(players, rules, visit)
 
 	tx, err := models.DB.Begin()
 	if err != nil {
@@ -195,13 +63,13 @@ func AddVisit(visit models.Visit) (*models.Visit, error) {
 	}
 	_, err = tx.Exec(`
 		INSERT INTO score(
-			leg_id, player_id,
+			leg_id, player_id, thrower_id,
 			first_dart, first_dart_multiplier,
 			second_dart, second_dart_multiplier,
 			third_dart, third_dart_multiplier,
 			is_bust, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`,
-		visit.LegID, visit.PlayerID,
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW())`,
+		visit.LegID, visit.PlayerID, visit.ThrowerID,
 		visit.FirstDart.Value, visit.FirstDart.Multiplier,
 		visit.SecondDart.Value, visit.SecondDart.Multiplier,
 		visit.ThirdDart.Value, visit.ThirdDart.Multiplier,
@@ -220,6 +88,10 @@ func AddVisit(visit models.Visit) (*models.Visit, error) {
 	log.Printf("[%d] Added score for player %d, (%d-%d, %d-%d, %d-%d, %t)", visit.LegID, visit.PlayerID, visit.FirstDart.Value.Int64,
 		visit.FirstDart.Multiplier, visit.SecondDart.Value.Int64, visit.SecondDart.Multiplier, visit.ThirdDart.Value.Int64, visit.ThirdDart.Multiplier,
 		visit.IsBust)
+	models.Events.Publish(newLegEvent(models.EVENTVISITADDED, visit.LegID, match, visit))
+	if visit.GetScore() == 180 {
+		models.Events.Publish(newLegEvent(models.EVENTONEEIGHTY, visit.LegID, match, visit))
+	}
 
 	if isFinished {
 		err = FinishLeg(visit)
@@ -231,60 +103,138 @@ func AddVisit(visit models.Visit) (*models.Visit, error) {
 	return &visit, nil
 }
 
-// ModifyVisit modify the scores of a visit
+// ModifyVisit modify the scores of a visit, and replay the leg to make sure all visits are still valid
 func ModifyVisit(visit models.Visit) error {
-	// FIXME: We need to check if this is a checkout/bust
-	stmt, err := models.DB.Prepare(`
-		UPDATE score SET
-    		first_dart = ?,
-    		first_dart_multiplier = ?,
-    		second_dart = ?,
-    		second_dart_multiplier = ?,
-    		third_dart = ?,
-		    third_dart_multiplier = ?,
-			updated_at = NOW()
-		WHERE id = ?`)
+	existing, err := GetVisit(visit.ID)
 	if err != nil {
 		return err
 	}
-	defer stmt.Close()
+	event := getLegEvent(models.EVENTVISITMODIFIED, existing.LegID, visit)
 
-	_, err = stmt.Exec(visit.FirstDart.Value, visit.FirstDart.Multiplier, visit.SecondDart.Value, visit.SecondDart.Multiplier,
-		visit.ThirdDart.Value, visit.ThirdDart.Multiplier, visit.ID)
+	addVisitLock.Lock()
+	defer addVisitLock.Unlock()
+
+	leg, err := GetLeg(existing.LegID)
 	if err != nil {
 		return err
 	}
-	log.Printf("[%d] Modified score %d, throws: (%d-%d, %d-%d, %d-%d)", visit.LegID, visit.ID, visit.FirstDart.Value.Int64,
+	visits, err := GetLegVisits(leg.ID)
+	if err != nil {
+		return err
+	}
+	keys := getReplayKeys(visits)
+	for _, v := range visits {
+		if v.ID == visit.ID {
+			v.FirstDart = visit.FirstDart
+			v.SecondDart = visit.SecondDart
+			v.ThirdDart = visit.ThirdDart
+		}
+	}
+
+	err = saveReplayedLeg(leg, visits, keys, 0)
+	if err != nil {
+		return err
+	}
+	log.Printf("[%d] Modified score %d, throws: (%d-%d, %d-%d, %d-%d)", leg.ID, visit.ID, visit.FirstDart.Value.Int64,
 		visit.FirstDart.Multiplier, visit.SecondDart.Value.Int64, visit.SecondDart.Multiplier, visit.ThirdDart.Value.Int64, visit.ThirdDart.Multiplier)
+	models.Events.Publish(event)
 
 	return nil
 }
 
-// DeleteVisit will delete the visit for the given ID
+// DeleteVisit will delete the visit for the given ID, and replay the leg to make sure all remaining visits are still valid
 func DeleteVisit(id int) error {
 	visit, err := GetVisit(id)
 	if err != nil {
 		return err
 	}
-	tx, err := models.DB.Begin()
+	event := getLegEvent(models.EVENTVISITDELETED, visit.LegID, visit)
+
+	addVisitLock.Lock()
+	defer addVisitLock.Unlock()
+
+	leg, err := GetLeg(visit.LegID)
 	if err != nil {
 		return err
 	}
-	// Delete the visit
-	_, err = tx.Exec("DELETE FROM score WHERE id = ?", id)
+	visits, err := GetLegVisits(leg.ID)
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
-	// Set current player to the player of the last visit
-	_, err = tx.Exec("UPDATE leg SET current_player_id = ? WHERE id = ?", visit.PlayerID, visit.LegID)
+	keys := getReplayKeys(visits)
+	remaining := make([]*models.Visit, 0, len(visits))
+	for _, v := range visits {
+		if v.ID != id {
+			remaining = append(remaining, v)
+		}
+	}
+
+	err = saveReplayedLeg(leg, remaining, keys, id)
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
+	log.Printf("[%d] Deleted visit %d", visit.LegID, visit.ID)
+	models.Events.Publish(event)
+	return nil
+}
+
+// saveReplayedLeg will replay the given visits, and write every visit changed by the replay, including the thrower of team visits,
+// the deleted visit (if any) and the next player.
+// If the replayed visits now finish the leg, the leg is finished
+func saveReplayedLeg(leg *models.Leg, visits []*models.Visit, keys map[int]string, deletedVisitID int) error {
+	state, err := replayLeg(leg, visits)
+	if err != nil {
+		return err
+	}
+	if leg.IsFinished && !state.IsFinished {
+		return errors.New("leg is finished, but the visits would no longer finish it")
+	}
+
+	tx, err := models.DB.Begin()
+	if err != nil {
+		return err
+	}
+	if deletedVisitID > 0 {
+		_, err = tx.Exec("DELETE FROM score WHERE id = ?", deletedVisitID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	for _, visit := range visits {
+		if getReplayKey(visit) == keys[visit.ID] {
+			continue
+		}
+		_, err = tx.Exec(`
+			UPDATE score SET
+				first_dart = ?,
+				first_dart_multiplier = ?,
+				second_dart = ?,
+				second_dart_multiplier = ?,
+				third_dart = ?,
+				third_dart_multiplier = ?,
+				is_bust = ?,
+				thrower_id = ?,
+				updated_at = NOW()
+			WHERE id = ?`, visit.FirstDart.Value, visit.FirstDart.Multiplier, visit.SecondDart.Value, visit.SecondDart.Multiplier,
+			visit.ThirdDart.Value, visit.ThirdDart.Multiplier, visit.IsBust, visit.ThrowerID, visit.ID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if !leg.IsFinished {
+		_, err = tx.Exec("UPDATE leg SET current_player_id = ?, updated_at = NOW() WHERE id = ?", state.CurrentPlayerID, leg.ID)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
 	tx.Commit()
 
-	log.Printf("[%d] Deleted visit %d", visit.LegID, visit.ID)
+	if !leg.IsFinished && state.IsFinished {
+		return FinishLeg(*visits[len(visits)-1])
+	}
 	return nil
 }
 
@@ -349,7 +299,7 @@ func GetPlayerVisits(id int) ([]*models.Visit, error) {
 func GetLegVisits(id int) ([]*models.Visit, error) {
 	rows, err := models.DB.Query(`
 		SELECT
-			id, leg_id, player_id,
+			id, leg_id, player_id, thrower_id,
 			first_dart, first_dart_multiplier,
 			second_dart, second_dart_multiplier,
 			third_dart, third_dart_multiplier,
@@ -369,7 +319,7 @@ func GetLegVisits(id int) ([]*models.Visit, error) {
 		v.FirstDart = new(models.Dart)
 		v.SecondDart = new(models.Dart)
 		v.ThirdDart = new(models.Dart)
-		err := rows.Scan(&v.ID, &v.LegID, &v.PlayerID,
+		err := rows.Scan(&v.ID, &v.LegID, &v.PlayerID, &v.ThrowerID,
 			&v.FirstDart.Value, &v.FirstDart.Multiplier,
 			&v.SecondDart.Value, &v.SecondDart.Multiplier,
 			&v.ThirdDart.Value, &v.ThirdDart.Multiplier,
@@ -394,7 +344,7 @@ func GetVisit(id int) (*models.Visit, error) {
 	v.ThirdDart = new(models.Dart)
 	err := models.DB.QueryRow(`
 		SELECT
-			id, leg_id, player_id,
+			id, leg_id, player_id, thrower_id,
 			first_dart, first_dart_multiplier,
 			second_dart, second_dart_multiplier,
 			third_dart, third_dart_multiplier,
@@ -402,7 +352,7 @@ func GetVisit(id int) (*models.Visit, error) {
 			created_at,
 			updated_at
 		FROM score s
-		WHERE s.id = ?`, id).Scan(&v.ID, &v.LegID, &v.PlayerID,
+		WHERE s.id = ?`, id).Scan(&v.ID, &v.LegID, &v.PlayerID, &v.ThrowerID,
 		&v.FirstDart.Value, &v.FirstDart.Multiplier,
 		&v.SecondDart.Value, &v.SecondDart.Multiplier,
 		&v.ThirdDart.Value, &v.ThirdDart.Multiplier,
@@ -603,45 +553,30 @@ func GetDartStatistics(dart int) (map[int]*models.Hits, error) {
 	return m, nil
 }
 
-func isCricketLegFinished(visit models.Visit) (bool, error) {
-	players, err := GetLegPlayers(visit.LegID)
-	if err != nil {
-		return false, err
-	}
-	allPlayers := make(map[int]*models.Player2Leg)
-	for _, player := range players {
-		allPlayers[player.PlayerID] = player
-	}
-
-	// Add score for incoming visit
-	visit.CalculateCricketScore(allPlayers)
+// getNextPlayerID will return the player to throw after the given visit, skipping players who are out
+func getNextPlayerID(players map[int]*models.Player2Leg, rules models.GameRules, visit models.Visit) int {
+	order := make(map[int]int)
 	for _, player := range players {
-		player.CurrentScore = allPlayers[player.PlayerID].CurrentScore
-	}
-
-	// Did current player close all numbers?
-	player := allPlayers[visit.PlayerID]
-	closed := true
-	for _, dart := range models.CRICKETDARTS {
-		if player.Hits[dart] == nil || player.Hits[dart].Total < 3 {
-			closed = false
-			break
+		if !rules.IsPlayerOut(player, visit) {
+			order[player.Order] = player.PlayerID
 		}
 	}
 
-	// What is the lowest score?
-	lowestScore := math.MaxInt32
-	for _, player := range players {
-		if player.CurrentScore < lowestScore {
-			lowestScore = player.CurrentScore
-		}
+	// Set new player order on remaining players, from 1 to n
+	for i, key := range getKeys(order) {
+		players[order[key]].Order = i + 1
 	}
 
-	// If current player closed all numbers and has the lowest score, it's finished
-	if closed && player.CurrentScore == lowestScore {
-		return true, nil
+	newOrder := make(map[int]int)
+	currentPlayerOrder := 1
+	for _, playerID := range order {
+		player := players[playerID]
+		if playerID == visit.PlayerID {
+			currentPlayerOrder = player.Order
+		}
+		newOrder[player.Order] = player.PlayerID
 	}
-	return false, nil
+	return newOrder[(currentPlayerOrder%len(newOrder))+1]
 }
 
 // getKeys will return all keys as a sorted slice for the given map
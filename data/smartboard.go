@@ -0,0 +1,54 @@
+package data
+
+import (
+	"errors"
+	"log"
+
+	"github.com/kcapp/api/models"
+)
+
+// GetVenueConfigurationBySmartboard will return the configuration of the venue with the given smartboard
+func GetVenueConfigurationBySmartboard(uuid string) (*models.VenueConfig, error) {
+	config := new(models.VenueConfig)
+	err := models.DB.QueryRow(`
+		SELECT venue_id, has_dual_monitor, has_led_lights, has_smartboard, smartboard_uuid, smartboard_button_number, led_controller_url
+		FROM venue_configuration WHERE smartboard_uuid = ? AND has_smartboard = 1`, uuid).
+		Scan(&config.VenueID, &config.HasDualMonitor, &config.HasLEDLights, &config.HasSmartboard, &config.SmartboardUUID, &config.SmartboardButtonNumber,
+			&config.LEDControllerURL)
+	if err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// HandleSmartboardEvent will apply the given event from the smartboard with the given uuid to the active leg of its venue,
+// returning the pending visit of the current player, or nil if the event did not change the leg
+func HandleSmartboardEvent(uuid string, event models.SmartboardEvent) (*models.PendingVisit, error) {
+	config, err := GetVenueConfigurationBySmartboard(uuid)
+	if err != nil {
+		return nil, err
+	}
+	action := event.GetAction(config)
+	if action == models.SMARTBOARDACTIONIGNORE {
+		return nil, nil
+	}
+
+	matches, err := SpectateVenue(config.VenueID)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 || !matches[0].CurrentLegID.Valid {
+		return nil, errors.New("no active leg at venue")
+	}
+	legID := int(matches[0].CurrentLegID.Int64)
+	log.Printf("[%d] Smartboard %s at venue %d sent %s (%s)", legID, uuid, config.VenueID, event.Type, action)
+
+	switch action {
+	case models.SMARTBOARDACTIONDART:
+		return AddDart(legID, event.GetDart())
+	case models.SMARTBOARDACTIONUNDO:
+		return UndoDart(legID)
+	}
+	// On takeout without darts thrown, the visit was already written when the last dart was thrown
+	return NextVisit(legID, event.Type != models.SMARTBOARDTAKEOUT)
+}
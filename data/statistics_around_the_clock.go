@@ -374,13 +374,14 @@ func CalculateAroundTheClockStatistics(legID int) (map[int]*models.StatisticsAro
 		}
 	}
 
+	multiplier := leg.Parameters.GetTargetMultiplier()
 	for _, visit := range leg.Visits {
 		stats := statisticsMap[visit.PlayerID]
 		currentScore := stats.Score
-		currentScore = isHit(stats, currentScore, visit.FirstDart)
-		currentScore = isHit(stats, currentScore, visit.SecondDart)
-		currentScore = isHit(stats, currentScore, visit.ThirdDart)
-		score := visit.CalculateAroundTheClockScore(stats.Score)
+		currentScore = isHit(stats, currentScore, visit.FirstDart, multiplier)
+		currentScore = isHit(stats, currentScore, visit.SecondDart, multiplier)
+		currentScore = isHit(stats, currentScore, visit.ThirdDart, multiplier)
+		score := visit.CalculateAroundTheClockScore(stats.Score, multiplier)
 		stats.Score += score
 		stats.DartsThrown = visit.DartsThrown
 	}
@@ -409,9 +410,9 @@ func CalculateAroundTheClockStatistics(legID int) (map[int]*models.StatisticsAro
 	return statisticsMap, nil
 }
 
-func isHit(stats *models.StatisticsAroundThe, currentScore int, dart *models.Dart) int {
+func isHit(stats *models.StatisticsAroundThe, currentScore int, dart *models.Dart, multiplier int64) int {
 	target := currentScore + 1
-	if (dart.ValueRaw() == target && dart.IsSingle()) || (target == 21 && dart.IsBull()) {
+	if dart.IsAroundTheClockHit(target, multiplier) {
 		stats.Hitrates[target] = 1 / (1 + stats.Hitrates[target])
 		stats.CurrentStreak++
 	} else {
@@ -361,30 +361,31 @@ func GetShanghaiStatistics(from string, to string) ([]*models.StatisticsAroundTh
 			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
 			SUM(s.mpr) / COUNT(DISTINCT l.id) as 'mpr',
 			SUM(s.total_hit_rate) / COUNT(l.id) as 'total_hit_rate',
-			IFNULL(SUM(s.hit_rate_1) / SUM(IF(shanghai < 1, 0, 1)), 0) as 'hit_rate_1',
-			IFNULL(SUM(s.hit_rate_2) / SUM(IF(shanghai < 2, 0, 1)), 0) as 'hit_rate_2',
-			IFNULL(SUM(s.hit_rate_3) / SUM(IF(shanghai < 3, 0, 1)), 0) as 'hit_rate_3',
-			IFNULL(SUM(s.hit_rate_4) / SUM(IF(shanghai < 4, 0, 1)), 0) as 'hit_rate_4',
-			IFNULL(SUM(s.hit_rate_5) / SUM(IF(shanghai < 5, 0, 1)), 0) as 'hit_rate_5',
-			IFNULL(SUM(s.hit_rate_6) / SUM(IF(shanghai < 6, 0, 1)), 0) as 'hit_rate_6',
-			IFNULL(SUM(s.hit_rate_7) / SUM(IF(shanghai < 7, 0, 1)), 0) as 'hit_rate_7',
-			IFNULL(SUM(s.hit_rate_8) / SUM(IF(shanghai < 8, 0, 1)), 0) as 'hit_rate_8',
-			IFNULL(SUM(s.hit_rate_9) / SUM(IF(shanghai < 9, 0, 1)), 0) as 'hit_rate_9',
-			IFNULL(SUM(s.hit_rate_10) / SUM(IF(shanghai < 10, 0, 1)), 0) as 'hit_rate_10',
-			IFNULL(SUM(s.hit_rate_11) / SUM(IF(shanghai < 11, 0, 1)), 0) as 'hit_rate_11',
-			IFNULL(SUM(s.hit_rate_12) / SUM(IF(shanghai < 12, 0, 1)), 0) as 'hit_rate_12',
-			IFNULL(SUM(s.hit_rate_13) / SUM(IF(shanghai < 13, 0, 1)), 0) as 'hit_rate_13',
-			IFNULL(SUM(s.hit_rate_14) / SUM(IF(shanghai < 14, 0, 1)), 0) as 'hit_rate_14',
-			IFNULL(SUM(s.hit_rate_15) / SUM(IF(shanghai < 15, 0, 1)), 0) as 'hit_rate_15',
-			IFNULL(SUM(s.hit_rate_16) / SUM(IF(shanghai < 16, 0, 1)), 0) as 'hit_rate_16',
-			IFNULL(SUM(s.hit_rate_17) / SUM(IF(shanghai < 17, 0, 1)), 0) as 'hit_rate_17',
-			IFNULL(SUM(s.hit_rate_18) / SUM(IF(shanghai < 18, 0, 1)), 0) as 'hit_rate_18',
-			IFNULL(SUM(s.hit_rate_19) / SUM(IF(shanghai < 19, 0, 1)), 0) as 'hit_rate_19',
-			IFNULL(SUM(s.hit_rate_20) / SUM(IF(shanghai < 20, 0, 1)), 0) as 'hit_rate_20'
+			IFNULL(SUM(s.hit_rate_1) / SUM(IF(shanghai < 1 OR IF(lp.rounds > 0, lp.rounds, 20) < 1, 0, 1)), 0) as 'hit_rate_1',
+			IFNULL(SUM(s.hit_rate_2) / SUM(IF(shanghai < 2 OR IF(lp.rounds > 0, lp.rounds, 20) < 2, 0, 1)), 0) as 'hit_rate_2',
+			IFNULL(SUM(s.hit_rate_3) / SUM(IF(shanghai < 3 OR IF(lp.rounds > 0, lp.rounds, 20) < 3, 0, 1)), 0) as 'hit_rate_3',
+			IFNULL(SUM(s.hit_rate_4) / SUM(IF(shanghai < 4 OR IF(lp.rounds > 0, lp.rounds, 20) < 4, 0, 1)), 0) as 'hit_rate_4',
+			IFNULL(SUM(s.hit_rate_5) / SUM(IF(shanghai < 5 OR IF(lp.rounds > 0, lp.rounds, 20) < 5, 0, 1)), 0) as 'hit_rate_5',
+			IFNULL(SUM(s.hit_rate_6) / SUM(IF(shanghai < 6 OR IF(lp.rounds > 0, lp.rounds, 20) < 6, 0, 1)), 0) as 'hit_rate_6',
+			IFNULL(SUM(s.hit_rate_7) / SUM(IF(shanghai < 7 OR IF(lp.rounds > 0, lp.rounds, 20) < 7, 0, 1)), 0) as 'hit_rate_7',
+			IFNULL(SUM(s.hit_rate_8) / SUM(IF(shanghai < 8 OR IF(lp.rounds > 0, lp.rounds, 20) < 8, 0, 1)), 0) as 'hit_rate_8',
+			IFNULL(SUM(s.hit_rate_9) / SUM(IF(shanghai < 9 OR IF(lp.rounds > 0, lp.rounds, 20) < 9, 0, 1)), 0) as 'hit_rate_9',
+			IFNULL(SUM(s.hit_rate_10) / SUM(IF(shanghai < 10 OR IF(lp.rounds > 0, lp.rounds, 20) < 10, 0, 1)), 0) as 'hit_rate_10',
+			IFNULL(SUM(s.hit_rate_11) / SUM(IF(shanghai < 11 OR IF(lp.rounds > 0, lp.rounds, 20) < 11, 0, 1)), 0) as 'hit_rate_11',
+			IFNULL(SUM(s.hit_rate_12) / SUM(IF(shanghai < 12 OR IF(lp.rounds > 0, lp.rounds, 20) < 12, 0, 1)), 0) as 'hit_rate_12',
+			IFNULL(SUM(s.hit_rate_13) / SUM(IF(shanghai < 13 OR IF(lp.rounds > 0, lp.rounds, 20) < 13, 0, 1)), 0) as 'hit_rate_13',
+			IFNULL(SUM(s.hit_rate_14) / SUM(IF(shanghai < 14 OR IF(lp.rounds > 0, lp.rounds, 20) < 14, 0, 1)), 0) as 'hit_rate_14',
+			IFNULL(SUM(s.hit_rate_15) / SUM(IF(shanghai < 15 OR IF(lp.rounds > 0, lp.rounds, 20) < 15, 0, 1)), 0) as 'hit_rate_15',
+			IFNULL(SUM(s.hit_rate_16) / SUM(IF(shanghai < 16 OR IF(lp.rounds > 0, lp.rounds, 20) < 16, 0, 1)), 0) as 'hit_rate_16',
+			IFNULL(SUM(s.hit_rate_17) / SUM(IF(shanghai < 17 OR IF(lp.rounds > 0, lp.rounds, 20) < 17, 0, 1)), 0) as 'hit_rate_17',
+			IFNULL(SUM(s.hit_rate_18) / SUM(IF(shanghai < 18 OR IF(lp.rounds > 0, lp.rounds, 20) < 18, 0, 1)), 0) as 'hit_rate_18',
+			IFNULL(SUM(s.hit_rate_19) / SUM(IF(shanghai < 19 OR IF(lp.rounds > 0, lp.rounds, 20) < 19, 0, 1)), 0) as 'hit_rate_19',
+			IFNULL(SUM(s.hit_rate_20) / SUM(IF(shanghai < 20 OR IF(lp.rounds > 0, lp.rounds, 20) < 20, 0, 1)), 0) as 'hit_rate_20'
 		FROM statistics_around_the s
 			JOIN player p ON p.id = s.player_id
 			JOIN leg l ON l.id = s.leg_id
 			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg_parameters lp ON lp.leg_id = l.id
 			LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
 			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
 		WHERE m.updated_at >= ? AND m.updated_at < ?
@@ -493,30 +494,31 @@ func GetShanghaiStatisticsForMatch(id int) ([]*models.StatisticsAroundThe, error
 			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
 			SUM(s.mpr) / COUNT(DISTINCT l.id) as 'mpr',
 			SUM(s.total_hit_rate) / COUNT(l.id) as 'total_hit_rate',
-			IFNULL(SUM(s.hit_rate_1) / SUM(IF(shanghai < 1, 0, 1)), 0) as 'hit_rate_1',
-			IFNULL(SUM(s.hit_rate_2) / SUM(IF(shanghai < 2, 0, 1)), 0) as 'hit_rate_2',
-			IFNULL(SUM(s.hit_rate_3) / SUM(IF(shanghai < 3, 0, 1)), 0) as 'hit_rate_3',
-			IFNULL(SUM(s.hit_rate_4) / SUM(IF(shanghai < 4, 0, 1)), 0) as 'hit_rate_4',
-			IFNULL(SUM(s.hit_rate_5) / SUM(IF(shanghai < 5, 0, 1)), 0) as 'hit_rate_5',
-			IFNULL(SUM(s.hit_rate_6) / SUM(IF(shanghai < 6, 0, 1)), 0) as 'hit_rate_6',
-			IFNULL(SUM(s.hit_rate_7) / SUM(IF(shanghai < 7, 0, 1)), 0) as 'hit_rate_7',
-			IFNULL(SUM(s.hit_rate_8) / SUM(IF(shanghai < 8, 0, 1)), 0) as 'hit_rate_8',
-			IFNULL(SUM(s.hit_rate_9) / SUM(IF(shanghai < 9, 0, 1)), 0) as 'hit_rate_9',
-			IFNULL(SUM(s.hit_rate_10) / SUM(IF(shanghai < 10, 0, 1)), 0) as 'hit_rate_10',
-			IFNULL(SUM(s.hit_rate_11) / SUM(IF(shanghai < 11, 0, 1)), 0) as 'hit_rate_11',
-			IFNULL(SUM(s.hit_rate_12) / SUM(IF(shanghai < 12, 0, 1)), 0) as 'hit_rate_12',
-			IFNULL(SUM(s.hit_rate_13) / SUM(IF(shanghai < 13, 0, 1)), 0) as 'hit_rate_13',
-			IFNULL(SUM(s.hit_rate_14) / SUM(IF(shanghai < 14, 0, 1)), 0) as 'hit_rate_14',
-			IFNULL(SUM(s.hit_rate_15) / SUM(IF(shanghai < 15, 0, 1)), 0) as 'hit_rate_15',
-			IFNULL(SUM(s.hit_rate_16) / SUM(IF(shanghai < 16, 0, 1)), 0) as 'hit_rate_16',
-			IFNULL(SUM(s.hit_rate_17) / SUM(IF(shanghai < 17, 0, 1)), 0) as 'hit_rate_17',
-			IFNULL(SUM(s.hit_rate_18) / SUM(IF(shanghai < 18, 0, 1)), 0) as 'hit_rate_18',
-			IFNULL(SUM(s.hit_rate_19) / SUM(IF(shanghai < 19, 0, 1)), 0) as 'hit_rate_19',
-			IFNULL(SUM(s.hit_rate_20) / SUM(IF(shanghai < 20, 0, 1)), 0) as 'hit_rate_20'
+			IFNULL(SUM(s.hit_rate_1) / SUM(IF(shanghai < 1 OR IF(lp.rounds > 0, lp.rounds, 20) < 1, 0, 1)), 0) as 'hit_rate_1',
+			IFNULL(SUM(s.hit_rate_2) / SUM(IF(shanghai < 2 OR IF(lp.rounds > 0, lp.rounds, 20) < 2, 0, 1)), 0) as 'hit_rate_2',
+			IFNULL(SUM(s.hit_rate_3) / SUM(IF(shanghai < 3 OR IF(lp.rounds > 0, lp.rounds, 20) < 3, 0, 1)), 0) as 'hit_rate_3',
+			IFNULL(SUM(s.hit_rate_4) / SUM(IF(shanghai < 4 OR IF(lp.rounds > 0, lp.rounds, 20) < 4, 0, 1)), 0) as 'hit_rate_4',
+			IFNULL(SUM(s.hit_rate_5) / SUM(IF(shanghai < 5 OR IF(lp.rounds > 0, lp.rounds, 20) < 5, 0, 1)), 0) as 'hit_rate_5',
+			IFNULL(SUM(s.hit_rate_6) / SUM(IF(shanghai < 6 OR IF(lp.rounds > 0, lp.rounds, 20) < 6, 0, 1)), 0) as 'hit_rate_6',
+			IFNULL(SUM(s.hit_rate_7) / SUM(IF(shanghai < 7 OR IF(lp.rounds > 0, lp.rounds, 20) < 7, 0, 1)), 0) as 'hit_rate_7',
+			IFNULL(SUM(s.hit_rate_8) / SUM(IF(shanghai < 8 OR IF(lp.rounds > 0, lp.rounds, 20) < 8, 0, 1)), 0) as 'hit_rate_8',
+			IFNULL(SUM(s.hit_rate_9) / SUM(IF(shanghai < 9 OR IF(lp.rounds > 0, lp.rounds, 20) < 9, 0, 1)), 0) as 'hit_rate_9',
+			IFNULL(SUM(s.hit_rate_10) / SUM(IF(shanghai < 10 OR IF(lp.rounds > 0, lp.rounds, 20) < 10, 0, 1)), 0) as 'hit_rate_10',
+			IFNULL(SUM(s.hit_rate_11) / SUM(IF(shanghai < 11 OR IF(lp.rounds > 0, lp.rounds, 20) < 11, 0, 1)), 0) as 'hit_rate_11',
+			IFNULL(SUM(s.hit_rate_12) / SUM(IF(shanghai < 12 OR IF(lp.rounds > 0, lp.rounds, 20) < 12, 0, 1)), 0) as 'hit_rate_12',
+			IFNULL(SUM(s.hit_rate_13) / SUM(IF(shanghai < 13 OR IF(lp.rounds > 0, lp.rounds, 20) < 13, 0, 1)), 0) as 'hit_rate_13',
+			IFNULL(SUM(s.hit_rate_14) / SUM(IF(shanghai < 14 OR IF(lp.rounds > 0, lp.rounds, 20) < 14, 0, 1)), 0) as 'hit_rate_14',
+			IFNULL(SUM(s.hit_rate_15) / SUM(IF(shanghai < 15 OR IF(lp.rounds > 0, lp.rounds, 20) < 15, 0, 1)), 0) as 'hit_rate_15',
+			IFNULL(SUM(s.hit_rate_16) / SUM(IF(shanghai < 16 OR IF(lp.rounds > 0, lp.rounds, 20) < 16, 0, 1)), 0) as 'hit_rate_16',
+			IFNULL(SUM(s.hit_rate_17) / SUM(IF(shanghai < 17 OR IF(lp.rounds > 0, lp.rounds, 20) < 17, 0, 1)), 0) as 'hit_rate_17',
+			IFNULL(SUM(s.hit_rate_18) / SUM(IF(shanghai < 18 OR IF(lp.rounds > 0, lp.rounds, 20) < 18, 0, 1)), 0) as 'hit_rate_18',
+			IFNULL(SUM(s.hit_rate_19) / SUM(IF(shanghai < 19 OR IF(lp.rounds > 0, lp.rounds, 20) < 19, 0, 1)), 0) as 'hit_rate_19',
+			IFNULL(SUM(s.hit_rate_20) / SUM(IF(shanghai < 20 OR IF(lp.rounds > 0, lp.rounds, 20) < 20, 0, 1)), 0) as 'hit_rate_20'
 		FROM statistics_around_the s
 			JOIN player p ON p.id = s.player_id
 			JOIN leg l ON l.id = s.leg_id
 			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg_parameters lp ON lp.leg_id = l.id
 			JOIN player2leg p2l ON p2l.leg_id = l.id AND p2l.player_id = s.player_id
 		WHERE m.id = ?
 		GROUP BY p.id
@@ -566,30 +568,31 @@ func GetShanghaiStatisticsForPlayer(id int) (*models.StatisticsAroundThe, error)
 			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
 			SUM(s.mpr) / COUNT(DISTINCT l.id) as 'mpr',
 			SUM(s.total_hit_rate) / COUNT(l.id) as 'total_hit_rate',
-			SUM(s.hit_rate_1) / SUM(IF(shanghai < 1, 0, 1)) as 'hit_rate_1',
-			SUM(s.hit_rate_2) / SUM(IF(shanghai < 2, 0, 1)) as 'hit_rate_2',
-			SUM(s.hit_rate_3) / SUM(IF(shanghai < 3, 0, 1)) as 'hit_rate_3',
-			SUM(s.hit_rate_4) / SUM(IF(shanghai < 4, 0, 1)) as 'hit_rate_4',
-			SUM(s.hit_rate_5) / SUM(IF(shanghai < 5, 0, 1)) as 'hit_rate_5',
-			SUM(s.hit_rate_6) / SUM(IF(shanghai < 6, 0, 1)) as 'hit_rate_6',
-			SUM(s.hit_rate_7) / SUM(IF(shanghai < 7, 0, 1)) as 'hit_rate_7',
-			SUM(s.hit_rate_8) / SUM(IF(shanghai < 8, 0, 1)) as 'hit_rate_8',
-			SUM(s.hit_rate_9) / SUM(IF(shanghai < 9, 0, 1)) as 'hit_rate_9',
-			SUM(s.hit_rate_10) / SUM(IF(shanghai < 10, 0, 1)) as 'hit_rate_10',
-			SUM(s.hit_rate_11) / SUM(IF(shanghai < 11, 0, 1)) as 'hit_rate_11',
-			SUM(s.hit_rate_12) / SUM(IF(shanghai < 12, 0, 1)) as 'hit_rate_12',
-			SUM(s.hit_rate_13) / SUM(IF(shanghai < 13, 0, 1)) as 'hit_rate_13',
-			SUM(s.hit_rate_14) / SUM(IF(shanghai < 14, 0, 1)) as 'hit_rate_14',
-			SUM(s.hit_rate_15) / SUM(IF(shanghai < 15, 0, 1)) as 'hit_rate_15',
-			SUM(s.hit_rate_16) / SUM(IF(shanghai < 16, 0, 1)) as 'hit_rate_16',
-			SUM(s.hit_rate_17) / SUM(IF(shanghai < 17, 0, 1)) as 'hit_rate_17',
-			SUM(s.hit_rate_18) / SUM(IF(shanghai < 18, 0, 1)) as 'hit_rate_18',
-			SUM(s.hit_rate_19) / SUM(IF(shanghai < 19, 0, 1)) as 'hit_rate_19',
-			SUM(s.hit_rate_20) / SUM(IF(shanghai < 20, 0, 1)) as 'hit_rate_20'
+			SUM(s.hit_rate_1) / SUM(IF(shanghai < 1 OR IF(lp.rounds > 0, lp.rounds, 20) < 1, 0, 1)) as 'hit_rate_1',
+			SUM(s.hit_rate_2) / SUM(IF(shanghai < 2 OR IF(lp.rounds > 0, lp.rounds, 20) < 2, 0, 1)) as 'hit_rate_2',
+			SUM(s.hit_rate_3) / SUM(IF(shanghai < 3 OR IF(lp.rounds > 0, lp.rounds, 20) < 3, 0, 1)) as 'hit_rate_3',
+			SUM(s.hit_rate_4) / SUM(IF(shanghai < 4 OR IF(lp.rounds > 0, lp.rounds, 20) < 4, 0, 1)) as 'hit_rate_4',
+			SUM(s.hit_rate_5) / SUM(IF(shanghai < 5 OR IF(lp.rounds > 0, lp.rounds, 20) < 5, 0, 1)) as 'hit_rate_5',
+			SUM(s.hit_rate_6) / SUM(IF(shanghai < 6 OR IF(lp.rounds > 0, lp.rounds, 20) < 6, 0, 1)) as 'hit_rate_6',
+			SUM(s.hit_rate_7) / SUM(IF(shanghai < 7 OR IF(lp.rounds > 0, lp.rounds, 20) < 7, 0, 1)) as 'hit_rate_7',
+			SUM(s.hit_rate_8) / SUM(IF(shanghai < 8 OR IF(lp.rounds > 0, lp.rounds, 20) < 8, 0, 1)) as 'hit_rate_8',
+			SUM(s.hit_rate_9) / SUM(IF(shanghai < 9 OR IF(lp.rounds > 0, lp.rounds, 20) < 9, 0, 1)) as 'hit_rate_9',
+			SUM(s.hit_rate_10) / SUM(IF(shanghai < 10 OR IF(lp.rounds > 0, lp.rounds, 20) < 10, 0, 1)) as 'hit_rate_10',
+			SUM(s.hit_rate_11) / SUM(IF(shanghai < 11 OR IF(lp.rounds > 0, lp.rounds, 20) < 11, 0, 1)) as 'hit_rate_11',
+			SUM(s.hit_rate_12) / SUM(IF(shanghai < 12 OR IF(lp.rounds > 0, lp.rounds, 20) < 12, 0, 1)) as 'hit_rate_12',
+			SUM(s.hit_rate_13) / SUM(IF(shanghai < 13 OR IF(lp.rounds > 0, lp.rounds, 20) < 13, 0, 1)) as 'hit_rate_13',
+			SUM(s.hit_rate_14) / SUM(IF(shanghai < 14 OR IF(lp.rounds > 0, lp.rounds, 20) < 14, 0, 1)) as 'hit_rate_14',
+			SUM(s.hit_rate_15) / SUM(IF(shanghai < 15 OR IF(lp.rounds > 0, lp.rounds, 20) < 15, 0, 1)) as 'hit_rate_15',
+			SUM(s.hit_rate_16) / SUM(IF(shanghai < 16 OR IF(lp.rounds > 0, lp.rounds, 20) < 16, 0, 1)) as 'hit_rate_16',
+			SUM(s.hit_rate_17) / SUM(IF(shanghai < 17 OR IF(lp.rounds > 0, lp.rounds, 20) < 17, 0, 1)) as 'hit_rate_17',
+			SUM(s.hit_rate_18) / SUM(IF(shanghai < 18 OR IF(lp.rounds > 0, lp.rounds, 20) < 18, 0, 1)) as 'hit_rate_18',
+			SUM(s.hit_rate_19) / SUM(IF(shanghai < 19 OR IF(lp.rounds > 0, lp.rounds, 20) < 19, 0, 1)) as 'hit_rate_19',
+			SUM(s.hit_rate_20) / SUM(IF(shanghai < 20 OR IF(lp.rounds > 0, lp.rounds, 20) < 20, 0, 1)) as 'hit_rate_20'
 		FROM statistics_around_the s
 			JOIN player p ON p.id = s.player_id
 			JOIN leg l ON l.id = s.leg_id
 			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg_parameters lp ON lp.leg_id = l.id
 			LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
 			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
 		WHERE s.player_id = ?
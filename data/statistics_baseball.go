@@ -0,0 +1,350 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/kcapp/api/models"
+)
+
+// GetBaseballStatistics will return statistics for all players active during the given period
+func GetBaseballStatistics(from string, to string) ([]*models.StatisticsBaseball, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			p.id,
+			COUNT(DISTINCT m.id) AS 'matches_played',
+			COUNT(DISTINCT m2.id) AS 'matches_won',
+			COUNT(DISTINCT l.id) AS 'legs_played',
+			COUNT(DISTINCT l2.id) AS 'legs_won',
+			m.office_id AS 'office_id',
+			SUM(s.darts_thrown) as 'darts_thrown',
+			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
+			SUM(s.innings_played) as 'innings_played',
+			SUM(s.total_hit_rate) / COUNT(l.id) as 'total_hit_rate',
+			AVG(s.inning_1) as 'inning_1',
+			AVG(s.inning_2) as 'inning_2',
+			AVG(s.inning_3) as 'inning_3',
+			AVG(s.inning_4) as 'inning_4',
+			AVG(s.inning_5) as 'inning_5',
+			AVG(s.inning_6) as 'inning_6',
+			AVG(s.inning_7) as 'inning_7',
+			AVG(s.inning_8) as 'inning_8',
+			AVG(s.inning_9) as 'inning_9',
+			AVG(s.extra_inning_runs) as 'extra_inning_runs'
+		FROM statistics_baseball s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
+			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
+		WHERE m.updated_at >= ? AND m.updated_at < ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 19
+		GROUP BY p.id, m.office_id
+		ORDER BY(COUNT(DISTINCT m2.id) / COUNT(DISTINCT m.id)) DESC, matches_played DESC`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsBaseball, 0)
+	for rows.Next() {
+		s := new(models.StatisticsBaseball)
+		h := make([]float64, 10)
+		err := rows.Scan(&s.PlayerID, &s.MatchesPlayed, &s.MatchesWon, &s.LegsPlayed, &s.LegsWon, &s.OfficeID, &s.DartsThrown, &s.Score,
+			&s.InningsPlayed, &s.TotalHitRate, &h[1], &h[2], &h[3], &h[4], &h[5], &h[6], &h[7], &h[8], &h[9], &s.ExtraInningRuns)
+		if err != nil {
+			return nil, err
+		}
+		s.Innings = getBaseballInnings(h)
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetBaseballStatisticsForLeg will return statistics for all players in the given leg
+func GetBaseballStatisticsForLeg(id int) ([]*models.StatisticsBaseball, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			l.id,
+			p.id,
+			s.darts_thrown,
+			s.score,
+			s.innings_played,
+			s.total_hit_rate,
+			s.inning_1,
+			s.inning_2,
+			s.inning_3,
+			s.inning_4,
+			s.inning_5,
+			s.inning_6,
+			s.inning_7,
+			s.inning_8,
+			s.inning_9,
+			s.extra_inning_runs
+		FROM statistics_baseball s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN player2leg p2l on l.id = p2l.leg_id AND p.id = p2l.player_id
+		WHERE l.id = ? GROUP BY p.id ORDER BY p2l.order`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsBaseball, 0)
+	for rows.Next() {
+		s := new(models.StatisticsBaseball)
+		h := make([]float64, 10)
+		err := rows.Scan(&s.LegID, &s.PlayerID, &s.DartsThrown, &s.Score, &s.InningsPlayed, &s.TotalHitRate,
+			&h[1], &h[2], &h[3], &h[4], &h[5], &h[6], &h[7], &h[8], &h[9], &s.ExtraInningRuns)
+		if err != nil {
+			return nil, err
+		}
+		s.Innings = getBaseballInnings(h)
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetBaseballStatisticsForMatch will return statistics for all players in the given match
+func GetBaseballStatisticsForMatch(id int) ([]*models.StatisticsBaseball, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			p.id,
+			SUM(s.darts_thrown) as 'darts_thrown',
+			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
+			SUM(s.innings_played) as 'innings_played',
+			SUM(s.total_hit_rate) / COUNT(l.id) as 'total_hit_rate',
+			AVG(s.inning_1) as 'inning_1',
+			AVG(s.inning_2) as 'inning_2',
+			AVG(s.inning_3) as 'inning_3',
+			AVG(s.inning_4) as 'inning_4',
+			AVG(s.inning_5) as 'inning_5',
+			AVG(s.inning_6) as 'inning_6',
+			AVG(s.inning_7) as 'inning_7',
+			AVG(s.inning_8) as 'inning_8',
+			AVG(s.inning_9) as 'inning_9',
+			AVG(s.extra_inning_runs) as 'extra_inning_runs'
+		FROM statistics_baseball s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			JOIN player2leg p2l ON p2l.leg_id = l.id AND p2l.player_id = s.player_id
+		WHERE m.id = ?
+		GROUP BY p.id
+		ORDER BY p2l.order`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsBaseball, 0)
+	for rows.Next() {
+		s := new(models.StatisticsBaseball)
+		h := make([]float64, 10)
+		err := rows.Scan(&s.PlayerID, &s.DartsThrown, &s.Score, &s.InningsPlayed, &s.TotalHitRate,
+			&h[1], &h[2], &h[3], &h[4], &h[5], &h[6], &h[7], &h[8], &h[9], &s.ExtraInningRuns)
+		if err != nil {
+			return nil, err
+		}
+		s.Innings = getBaseballInnings(h)
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetBaseballStatisticsForPlayer will return Baseball statistics for the given player
+func GetBaseballStatisticsForPlayer(id int) (*models.StatisticsBaseball, error) {
+	s := new(models.StatisticsBaseball)
+	h := make([]float64, 10)
+	err := models.DB.QueryRow(`
+		SELECT
+			p.id,
+			COUNT(DISTINCT m.id) AS 'matches_played',
+			COUNT(DISTINCT m2.id) AS 'matches_won',
+			COUNT(DISTINCT l.id) AS 'legs_played',
+			COUNT(DISTINCT l2.id) AS 'legs_won',
+			SUM(s.darts_thrown) as 'darts_thrown',
+			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
+			SUM(s.innings_played) as 'innings_played',
+			SUM(s.total_hit_rate) / COUNT(l.id) as 'total_hit_rate',
+			AVG(s.inning_1) as 'inning_1',
+			AVG(s.inning_2) as 'inning_2',
+			AVG(s.inning_3) as 'inning_3',
+			AVG(s.inning_4) as 'inning_4',
+			AVG(s.inning_5) as 'inning_5',
+			AVG(s.inning_6) as 'inning_6',
+			AVG(s.inning_7) as 'inning_7',
+			AVG(s.inning_8) as 'inning_8',
+			AVG(s.inning_9) as 'inning_9',
+			AVG(s.extra_inning_runs) as 'extra_inning_runs'
+		FROM statistics_baseball s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
+			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
+		WHERE s.player_id = ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 19
+		GROUP BY p.id`, id).Scan(&s.PlayerID, &s.MatchesPlayed, &s.MatchesWon, &s.LegsPlayed, &s.LegsWon, &s.DartsThrown, &s.Score,
+		&s.InningsPlayed, &s.TotalHitRate, &h[1], &h[2], &h[3], &h[4], &h[5], &h[6], &h[7], &h[8], &h[9], &s.ExtraInningRuns)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return new(models.StatisticsBaseball), nil
+		}
+		return nil, err
+	}
+	s.Innings = getBaseballInnings(h)
+	return s, nil
+}
+
+// GetBaseballHistoryForPlayer will return history of Baseball statistics for the given player
+func GetBaseballHistoryForPlayer(id int, limit int) ([]*models.Leg, error) {
+	legs, err := GetLegsOfType(models.BASEBALL, false)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[int]*models.Leg)
+	for _, leg := range legs {
+		m[leg.ID] = leg
+	}
+
+	rows, err := models.DB.Query(`
+		SELECT
+			l.id,
+			p.id,
+			s.darts_thrown,
+			s.score,
+			s.innings_played,
+			s.total_hit_rate,
+			s.inning_1,
+			s.inning_2,
+			s.inning_3,
+			s.inning_4,
+			s.inning_5,
+			s.inning_6,
+			s.inning_7,
+			s.inning_8,
+			s.inning_9,
+			s.extra_inning_runs
+		FROM statistics_baseball s
+			LEFT JOIN player p ON p.id = s.player_id
+			LEFT JOIN leg l ON l.id = s.leg_id
+			LEFT JOIN matches m ON m.id = l.match_id
+		WHERE s.player_id = ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 19
+		ORDER BY l.id DESC
+		LIMIT ?`, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	legs = make([]*models.Leg, 0)
+	for rows.Next() {
+		s := new(models.StatisticsBaseball)
+		h := make([]float64, 10)
+		err := rows.Scan(&s.LegID, &s.PlayerID, &s.DartsThrown, &s.Score, &s.InningsPlayed, &s.TotalHitRate,
+			&h[1], &h[2], &h[3], &h[4], &h[5], &h[6], &h[7], &h[8], &h[9], &s.ExtraInningRuns)
+		if err != nil {
+			return nil, err
+		}
+		s.Innings = getBaseballInnings(h)
+
+		leg := m[s.LegID]
+		leg.Statistics = s
+		legs = append(legs, leg)
+	}
+	return legs, nil
+}
+
+// CalculateBaseballStatistics will generate Baseball statistics for the given leg
+func CalculateBaseballStatistics(legID int) (map[int]*models.StatisticsBaseball, error) {
+	leg, err := GetLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := GetPlayersScore(legID)
+	if err != nil {
+		return nil, err
+	}
+
+	statisticsMap := make(map[int]*models.StatisticsBaseball)
+	for _, player := range players {
+		stats := new(models.StatisticsBaseball)
+		stats.PlayerID = player.PlayerID
+		stats.Innings = make(map[int]float64)
+		for i := 1; i <= models.BaseballInnings; i++ {
+			stats.Innings[i] = 0
+		}
+		statisticsMap[player.PlayerID] = stats
+	}
+
+	hits := make(map[int]int)
+	inning := 1
+	for i, visit := range leg.Visits {
+		if i > 0 && i%len(players) == 0 {
+			inning++
+		}
+		stats := statisticsMap[visit.PlayerID]
+
+		runs := visit.CalculateBaseballScore(inning)
+		if inning <= models.BaseballInnings {
+			stats.Innings[inning] = float64(runs)
+		} else {
+			stats.ExtraInningRuns += float64(runs)
+		}
+		stats.Score += runs
+		stats.InningsPlayed = inning
+		stats.DartsThrown += 3
+
+		for _, dart := range []*models.Dart{visit.FirstDart, visit.SecondDart, visit.ThirdDart} {
+			if dart.ValueRaw() == models.GetBaseballTarget(inning) {
+				hits[visit.PlayerID]++
+			}
+		}
+	}
+
+	for playerID, stats := range statisticsMap {
+		if stats.DartsThrown > 0 {
+			stats.TotalHitRate = float64(hits[playerID]) / float64(stats.DartsThrown)
+		}
+	}
+	return statisticsMap, nil
+}
+
+// ReCalculateBaseballStatistics will recaulcate statistics for Baseball legs
+func ReCalculateBaseballStatistics() (map[int]map[int]*models.StatisticsBaseball, error) {
+	legs, err := GetLegsOfType(models.BASEBALL, true)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make(map[int]map[int]*models.StatisticsBaseball)
+	for _, leg := range legs {
+		stats, err := CalculateBaseballStatistics(leg.ID)
+		if err != nil {
+			return nil, err
+		}
+		for playerID, stat := range stats {
+			log.Printf(`UPDATE statistics_baseball SET darts_thrown = %d, score = %d, innings_played = %d, total_hit_rate = %f, extra_inning_runs = %f
+			WHERE leg_id = %d AND player_id = %d;`, stat.DartsThrown, stat.Score, stat.InningsPlayed, stat.TotalHitRate, stat.ExtraInningRuns, leg.ID, playerID)
+		}
+		s[leg.ID] = stats
+	}
+
+	return s, err
+}
+
+// getBaseballInnings will return the given runs for each of the nine innings
+func getBaseballInnings(h []float64) map[int]float64 {
+	innings := make(map[int]float64)
+	for i := 1; i <= models.BaseballInnings; i++ {
+		innings[i] = h[i]
+	}
+	return innings
+}
@@ -0,0 +1,424 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// GetBobs27Statistics will return statistics for all players active during the given period
+func GetBobs27Statistics(from string, to string) ([]*models.StatisticsBobs27, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			p.id,
+			COUNT(DISTINCT m.id) AS 'matches_played',
+			COUNT(DISTINCT m2.id) AS 'matches_won',
+			COUNT(DISTINCT l.id) AS 'legs_played',
+			COUNT(DISTINCT l2.id) AS 'legs_won',
+			m.office_id AS 'office_id',
+			SUM(s.darts_thrown) as 'darts_thrown',
+			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
+			MAX(s.highest_score_reached) as 'highest_score_reached',
+			CAST(SUM(s.rounds_played) / COUNT(DISTINCT l.id) AS SIGNED) as 'rounds_played',
+			SUM(s.doubles_hit) as 'doubles_hit',
+			SUM(s.total_hit_rate) / COUNT(l.id) as 'total_hit_rate',
+			AVG(s.hit_rate_1) as 'hit_rate_1',
+			AVG(s.hit_rate_2) as 'hit_rate_2',
+			AVG(s.hit_rate_3) as 'hit_rate_3',
+			AVG(s.hit_rate_4) as 'hit_rate_4',
+			AVG(s.hit_rate_5) as 'hit_rate_5',
+			AVG(s.hit_rate_6) as 'hit_rate_6',
+			AVG(s.hit_rate_7) as 'hit_rate_7',
+			AVG(s.hit_rate_8) as 'hit_rate_8',
+			AVG(s.hit_rate_9) as 'hit_rate_9',
+			AVG(s.hit_rate_10) as 'hit_rate_10',
+			AVG(s.hit_rate_11) as 'hit_rate_11',
+			AVG(s.hit_rate_12) as 'hit_rate_12',
+			AVG(s.hit_rate_13) as 'hit_rate_13',
+			AVG(s.hit_rate_14) as 'hit_rate_14',
+			AVG(s.hit_rate_15) as 'hit_rate_15',
+			AVG(s.hit_rate_16) as 'hit_rate_16',
+			AVG(s.hit_rate_17) as 'hit_rate_17',
+			AVG(s.hit_rate_18) as 'hit_rate_18',
+			AVG(s.hit_rate_19) as 'hit_rate_19',
+			AVG(s.hit_rate_20) as 'hit_rate_20',
+			AVG(s.hit_rate_bull) as 'hit_rate_bull'
+		FROM statistics_bobs_27 s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
+			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
+		WHERE m.updated_at >= ? AND m.updated_at < ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 20
+		GROUP BY p.id, m.office_id
+		ORDER BY(COUNT(DISTINCT m2.id) / COUNT(DISTINCT m.id)) DESC, matches_played DESC`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsBobs27, 0)
+	for rows.Next() {
+		s := new(models.StatisticsBobs27)
+		h := make([]null.Float, 22)
+		err := rows.Scan(&s.PlayerID, &s.MatchesPlayed, &s.MatchesWon, &s.LegsPlayed, &s.LegsWon, &s.OfficeID, &s.DartsThrown, &s.Score,
+			&s.HighestScoreReached, &s.RoundsPlayed, &s.DoublesHit, &s.TotalHitRate,
+			&h[1], &h[2], &h[3], &h[4], &h[5], &h[6], &h[7], &h[8], &h[9], &h[10],
+			&h[11], &h[12], &h[13], &h[14], &h[15], &h[16], &h[17], &h[18], &h[19], &h[20], &h[21])
+		if err != nil {
+			return nil, err
+		}
+		s.Hitrates = getBobs27Hitrates(h)
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetBobs27StatisticsForLeg will return statistics for all players in the given leg
+func GetBobs27StatisticsForLeg(id int) ([]*models.StatisticsBobs27, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			l.id,
+			p.id,
+			s.darts_thrown,
+			s.score,
+			s.highest_score_reached,
+			s.rounds_played,
+			s.doubles_hit,
+			s.total_hit_rate,
+			s.hit_rate_1,
+			s.hit_rate_2,
+			s.hit_rate_3,
+			s.hit_rate_4,
+			s.hit_rate_5,
+			s.hit_rate_6,
+			s.hit_rate_7,
+			s.hit_rate_8,
+			s.hit_rate_9,
+			s.hit_rate_10,
+			s.hit_rate_11,
+			s.hit_rate_12,
+			s.hit_rate_13,
+			s.hit_rate_14,
+			s.hit_rate_15,
+			s.hit_rate_16,
+			s.hit_rate_17,
+			s.hit_rate_18,
+			s.hit_rate_19,
+			s.hit_rate_20,
+			s.hit_rate_bull
+		FROM statistics_bobs_27 s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN player2leg p2l on l.id = p2l.leg_id AND p.id = p2l.player_id
+		WHERE l.id = ? GROUP BY p.id ORDER BY p2l.order`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsBobs27, 0)
+	for rows.Next() {
+		s := new(models.StatisticsBobs27)
+		h := make([]null.Float, 22)
+		err := rows.Scan(&s.LegID, &s.PlayerID, &s.DartsThrown, &s.Score, &s.HighestScoreReached, &s.RoundsPlayed, &s.DoublesHit, &s.TotalHitRate,
+			&h[1], &h[2], &h[3], &h[4], &h[5], &h[6], &h[7], &h[8], &h[9], &h[10],
+			&h[11], &h[12], &h[13], &h[14], &h[15], &h[16], &h[17], &h[18], &h[19], &h[20], &h[21])
+		if err != nil {
+			return nil, err
+		}
+		s.Hitrates = getBobs27Hitrates(h)
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetBobs27StatisticsForMatch will return statistics for all players in the given match
+func GetBobs27StatisticsForMatch(id int) ([]*models.StatisticsBobs27, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			p.id,
+			SUM(s.darts_thrown) as 'darts_thrown',
+			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
+			MAX(s.highest_score_reached) as 'highest_score_reached',
+			CAST(SUM(s.rounds_played) / COUNT(DISTINCT l.id) AS SIGNED) as 'rounds_played',
+			SUM(s.doubles_hit) as 'doubles_hit',
+			SUM(s.total_hit_rate) / COUNT(l.id) as 'total_hit_rate',
+			AVG(s.hit_rate_1) as 'hit_rate_1',
+			AVG(s.hit_rate_2) as 'hit_rate_2',
+			AVG(s.hit_rate_3) as 'hit_rate_3',
+			AVG(s.hit_rate_4) as 'hit_rate_4',
+			AVG(s.hit_rate_5) as 'hit_rate_5',
+			AVG(s.hit_rate_6) as 'hit_rate_6',
+			AVG(s.hit_rate_7) as 'hit_rate_7',
+			AVG(s.hit_rate_8) as 'hit_rate_8',
+			AVG(s.hit_rate_9) as 'hit_rate_9',
+			AVG(s.hit_rate_10) as 'hit_rate_10',
+			AVG(s.hit_rate_11) as 'hit_rate_11',
+			AVG(s.hit_rate_12) as 'hit_rate_12',
+			AVG(s.hit_rate_13) as 'hit_rate_13',
+			AVG(s.hit_rate_14) as 'hit_rate_14',
+			AVG(s.hit_rate_15) as 'hit_rate_15',
+			AVG(s.hit_rate_16) as 'hit_rate_16',
+			AVG(s.hit_rate_17) as 'hit_rate_17',
+			AVG(s.hit_rate_18) as 'hit_rate_18',
+			AVG(s.hit_rate_19) as 'hit_rate_19',
+			AVG(s.hit_rate_20) as 'hit_rate_20',
+			AVG(s.hit_rate_bull) as 'hit_rate_bull'
+		FROM statistics_bobs_27 s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			JOIN player2leg p2l ON p2l.leg_id = l.id AND p2l.player_id = s.player_id
+		WHERE m.id = ?
+		GROUP BY p.id
+		ORDER BY p2l.order`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsBobs27, 0)
+	for rows.Next() {
+		s := new(models.StatisticsBobs27)
+		h := make([]null.Float, 22)
+		err := rows.Scan(&s.PlayerID, &s.DartsThrown, &s.Score, &s.HighestScoreReached, &s.RoundsPlayed, &s.DoublesHit, &s.TotalHitRate,
+			&h[1], &h[2], &h[3], &h[4], &h[5], &h[6], &h[7], &h[8], &h[9], &h[10],
+			&h[11], &h[12], &h[13], &h[14], &h[15], &h[16], &h[17], &h[18], &h[19], &h[20], &h[21])
+		if err != nil {
+			return nil, err
+		}
+		s.Hitrates = getBobs27Hitrates(h)
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetBobs27StatisticsForPlayer will return Bob's 27 statistics for the given player
+func GetBobs27StatisticsForPlayer(id int) (*models.StatisticsBobs27, error) {
+	s := new(models.StatisticsBobs27)
+	h := make([]null.Float, 22)
+	err := models.DB.QueryRow(`
+		SELECT
+			p.id,
+			COUNT(DISTINCT m.id) AS 'matches_played',
+			COUNT(DISTINCT m2.id) AS 'matches_won',
+			COUNT(DISTINCT l.id) AS 'legs_played',
+			COUNT(DISTINCT l2.id) AS 'legs_won',
+			SUM(s.darts_thrown) as 'darts_thrown',
+			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
+			MAX(s.highest_score_reached) as 'highest_score_reached',
+			CAST(SUM(s.rounds_played) / COUNT(DISTINCT l.id) AS SIGNED) as 'rounds_played',
+			SUM(s.doubles_hit) as 'doubles_hit',
+			SUM(s.total_hit_rate) / COUNT(l.id) as 'total_hit_rate',
+			AVG(s.hit_rate_1) as 'hit_rate_1',
+			AVG(s.hit_rate_2) as 'hit_rate_2',
+			AVG(s.hit_rate_3) as 'hit_rate_3',
+			AVG(s.hit_rate_4) as 'hit_rate_4',
+			AVG(s.hit_rate_5) as 'hit_rate_5',
+			AVG(s.hit_rate_6) as 'hit_rate_6',
+			AVG(s.hit_rate_7) as 'hit_rate_7',
+			AVG(s.hit_rate_8) as 'hit_rate_8',
+			AVG(s.hit_rate_9) as 'hit_rate_9',
+			AVG(s.hit_rate_10) as 'hit_rate_10',
+			AVG(s.hit_rate_11) as 'hit_rate_11',
+			AVG(s.hit_rate_12) as 'hit_rate_12',
+			AVG(s.hit_rate_13) as 'hit_rate_13',
+			AVG(s.hit_rate_14) as 'hit_rate_14',
+			AVG(s.hit_rate_15) as 'hit_rate_15',
+			AVG(s.hit_rate_16) as 'hit_rate_16',
+			AVG(s.hit_rate_17) as 'hit_rate_17',
+			AVG(s.hit_rate_18) as 'hit_rate_18',
+			AVG(s.hit_rate_19) as 'hit_rate_19',
+			AVG(s.hit_rate_20) as 'hit_rate_20',
+			AVG(s.hit_rate_bull) as 'hit_rate_bull'
+		FROM statistics_bobs_27 s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
+			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
+		WHERE s.player_id = ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 20
+		GROUP BY p.id`, id).Scan(&s.PlayerID, &s.MatchesPlayed, &s.MatchesWon, &s.LegsPlayed, &s.LegsWon, &s.DartsThrown, &s.Score,
+		&s.HighestScoreReached, &s.RoundsPlayed, &s.DoublesHit, &s.TotalHitRate,
+		&h[1], &h[2], &h[3], &h[4], &h[5], &h[6], &h[7], &h[8], &h[9], &h[10],
+		&h[11], &h[12], &h[13], &h[14], &h[15], &h[16], &h[17], &h[18], &h[19], &h[20], &h[21])
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return new(models.StatisticsBobs27), nil
+		}
+		return nil, err
+	}
+	s.Hitrates = getBobs27Hitrates(h)
+	return s, nil
+}
+
+// GetBobs27HistoryForPlayer will return history of Bob's 27 statistics for the given player
+func GetBobs27HistoryForPlayer(id int, limit int) ([]*models.Leg, error) {
+	legs, err := GetLegsOfType(models.BOBS27, false)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[int]*models.Leg)
+	for _, leg := range legs {
+		m[leg.ID] = leg
+	}
+
+	rows, err := models.DB.Query(`
+		SELECT
+			l.id,
+			p.id,
+			s.darts_thrown,
+			s.score,
+			s.highest_score_reached,
+			s.rounds_played,
+			s.doubles_hit,
+			s.total_hit_rate,
+			s.hit_rate_1,
+			s.hit_rate_2,
+			s.hit_rate_3,
+			s.hit_rate_4,
+			s.hit_rate_5,
+			s.hit_rate_6,
+			s.hit_rate_7,
+			s.hit_rate_8,
+			s.hit_rate_9,
+			s.hit_rate_10,
+			s.hit_rate_11,
+			s.hit_rate_12,
+			s.hit_rate_13,
+			s.hit_rate_14,
+			s.hit_rate_15,
+			s.hit_rate_16,
+			s.hit_rate_17,
+			s.hit_rate_18,
+			s.hit_rate_19,
+			s.hit_rate_20,
+			s.hit_rate_bull
+		FROM statistics_bobs_27 s
+			LEFT JOIN player p ON p.id = s.player_id
+			LEFT JOIN leg l ON l.id = s.leg_id
+			LEFT JOIN matches m ON m.id = l.match_id
+		WHERE s.player_id = ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 20
+		ORDER BY l.id DESC
+		LIMIT ?`, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	legs = make([]*models.Leg, 0)
+	for rows.Next() {
+		s := new(models.StatisticsBobs27)
+		h := make([]null.Float, 22)
+		err := rows.Scan(&s.LegID, &s.PlayerID, &s.DartsThrown, &s.Score, &s.HighestScoreReached, &s.RoundsPlayed, &s.DoublesHit, &s.TotalHitRate,
+			&h[1], &h[2], &h[3], &h[4], &h[5], &h[6], &h[7], &h[8], &h[9], &h[10],
+			&h[11], &h[12], &h[13], &h[14], &h[15], &h[16], &h[17], &h[18], &h[19], &h[20], &h[21])
+		if err != nil {
+			return nil, err
+		}
+		s.Hitrates = getBobs27Hitrates(h)
+
+		leg := m[s.LegID]
+		leg.Statistics = s
+		legs = append(legs, leg)
+	}
+	return legs, nil
+}
+
+// CalculateBobs27Statistics will generate Bob's 27 statistics for the given leg
+func CalculateBobs27Statistics(legID int) (map[int]*models.StatisticsBobs27, error) {
+	leg, err := GetLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := GetPlayersScore(legID)
+	if err != nil {
+		return nil, err
+	}
+
+	statisticsMap := make(map[int]*models.StatisticsBobs27)
+	for _, player := range players {
+		stats := new(models.StatisticsBobs27)
+		stats.PlayerID = player.PlayerID
+		stats.Score = models.Bobs27StartingScore
+		stats.HighestScoreReached = models.Bobs27StartingScore
+		stats.Hitrates = make(map[int]float64)
+		statisticsMap[player.PlayerID] = stats
+	}
+
+	for _, visit := range leg.Visits {
+		stats := statisticsMap[visit.PlayerID]
+		if stats.RoundsPlayed >= len(models.TargetsBobs27) {
+			continue
+		}
+		target := models.TargetsBobs27[stats.RoundsPlayed]
+		stats.Score += visit.CalculateBobs27Score(stats.RoundsPlayed)
+
+		hits := 0
+		for _, dart := range []*models.Dart{visit.FirstDart, visit.SecondDart, visit.ThirdDart} {
+			if dart.IsDouble() && dart.ValueRaw() == target {
+				hits++
+			}
+		}
+		stats.Hitrates[target] = float64(hits) / 3.0
+		stats.DoublesHit += hits
+		stats.DartsThrown += 3
+		stats.RoundsPlayed++
+
+		if stats.Score > stats.HighestScoreReached {
+			stats.HighestScoreReached = stats.Score
+		}
+	}
+
+	for _, stats := range statisticsMap {
+		if stats.DartsThrown > 0 {
+			stats.TotalHitRate = float64(stats.DoublesHit) / float64(stats.DartsThrown)
+		}
+	}
+	return statisticsMap, nil
+}
+
+// ReCalculateBobs27Statistics will recaulcate statistics for Bob's 27 legs
+func ReCalculateBobs27Statistics() (map[int]map[int]*models.StatisticsBobs27, error) {
+	legs, err := GetLegsOfType(models.BOBS27, true)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make(map[int]map[int]*models.StatisticsBobs27)
+	for _, leg := range legs {
+		stats, err := CalculateBobs27Statistics(leg.ID)
+		if err != nil {
+			return nil, err
+		}
+		for playerID, stat := range stats {
+			log.Printf(`UPDATE statistics_bobs_27 SET darts_thrown = %d, score = %d, highest_score_reached = %d, rounds_played = %d, doubles_hit = %d,
+			total_hit_rate = %f WHERE leg_id = %d AND player_id = %d;`, stat.DartsThrown, stat.Score, stat.HighestScoreReached, stat.RoundsPlayed,
+				stat.DoublesHit, stat.TotalHitRate, leg.ID, playerID)
+		}
+		s[leg.ID] = stats
+	}
+
+	return s, err
+}
+
+// getBobs27Hitrates will return the given hit rates for each double, skipping doubles not thrown at
+func getBobs27Hitrates(h []null.Float) map[int]float64 {
+	hitrates := make(map[int]float64)
+	for i, target := range models.TargetsBobs27 {
+		if h[i+1].Valid {
+			hitrates[target] = h[i+1].Float64
+		}
+	}
+	return hitrates
+}
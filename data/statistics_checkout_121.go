@@ -0,0 +1,286 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/kcapp/api/models"
+)
+
+// GetCheckout121Statistics will return statistics for all players active during the given period
+func GetCheckout121Statistics(from string, to string) ([]*models.StatisticsCheckout121, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			p.id,
+			COUNT(DISTINCT m.id) AS 'matches_played',
+			COUNT(DISTINCT m2.id) AS 'matches_won',
+			COUNT(DISTINCT l.id) AS 'legs_played',
+			COUNT(DISTINCT l2.id) AS 'legs_won',
+			m.office_id AS 'office_id',
+			SUM(s.darts_thrown) as 'darts_thrown',
+			MAX(s.highest_target) as 'highest_target',
+			SUM(s.targets_attempted) as 'targets_attempted',
+			SUM(s.checkouts) as 'checkouts',
+			IFNULL(SUM(s.checkouts) / SUM(s.targets_attempted), 0) as 'success_rate',
+			IFNULL(SUM(s.darts_per_checkout * s.checkouts) / SUM(s.checkouts), 0) as 'darts_per_checkout',
+			SUM(s.checkout_attempts) as 'checkout_attempts',
+			IFNULL(SUM(s.checkouts) / SUM(s.checkout_attempts), 0) as 'checkout_percentage'
+		FROM statistics_checkout_121 s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
+			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
+		WHERE m.updated_at >= ? AND m.updated_at < ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 22
+		GROUP BY p.id, m.office_id
+		ORDER BY highest_target DESC, success_rate DESC`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsCheckout121, 0)
+	for rows.Next() {
+		s := new(models.StatisticsCheckout121)
+		err := rows.Scan(&s.PlayerID, &s.MatchesPlayed, &s.MatchesWon, &s.LegsPlayed, &s.LegsWon, &s.OfficeID, &s.DartsThrown, &s.HighestTarget,
+			&s.TargetsAttempted, &s.Checkouts, &s.SuccessRate, &s.DartsPerCheckout, &s.CheckoutAttempts, &s.CheckoutPercentage)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetCheckout121StatisticsForLeg will return statistics for all players in the given leg
+func GetCheckout121StatisticsForLeg(id int) ([]*models.StatisticsCheckout121, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			l.id,
+			p.id,
+			s.darts_thrown,
+			s.highest_target,
+			s.targets_attempted,
+			s.checkouts,
+			s.success_rate,
+			s.darts_per_checkout,
+			s.checkout_attempts,
+			s.checkout_percentage
+		FROM statistics_checkout_121 s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN player2leg p2l on l.id = p2l.leg_id AND p.id = p2l.player_id
+		WHERE l.id = ? GROUP BY p.id ORDER BY p2l.order`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsCheckout121, 0)
+	for rows.Next() {
+		s := new(models.StatisticsCheckout121)
+		err := rows.Scan(&s.LegID, &s.PlayerID, &s.DartsThrown, &s.HighestTarget, &s.TargetsAttempted, &s.Checkouts, &s.SuccessRate,
+			&s.DartsPerCheckout, &s.CheckoutAttempts, &s.CheckoutPercentage)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetCheckout121StatisticsForMatch will return statistics for all players in the given match
+func GetCheckout121StatisticsForMatch(id int) ([]*models.StatisticsCheckout121, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			p.id,
+			SUM(s.darts_thrown) as 'darts_thrown',
+			MAX(s.highest_target) as 'highest_target',
+			SUM(s.targets_attempted) as 'targets_attempted',
+			SUM(s.checkouts) as 'checkouts',
+			IFNULL(SUM(s.checkouts) / SUM(s.targets_attempted), 0) as 'success_rate',
+			IFNULL(SUM(s.darts_per_checkout * s.checkouts) / SUM(s.checkouts), 0) as 'darts_per_checkout',
+			SUM(s.checkout_attempts) as 'checkout_attempts',
+			IFNULL(SUM(s.checkouts) / SUM(s.checkout_attempts), 0) as 'checkout_percentage'
+		FROM statistics_checkout_121 s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			JOIN player2leg p2l ON p2l.leg_id = l.id AND p2l.player_id = s.player_id
+		WHERE m.id = ?
+		GROUP BY p.id
+		ORDER BY p2l.order`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsCheckout121, 0)
+	for rows.Next() {
+		s := new(models.StatisticsCheckout121)
+		err := rows.Scan(&s.PlayerID, &s.DartsThrown, &s.HighestTarget, &s.TargetsAttempted, &s.Checkouts, &s.SuccessRate,
+			&s.DartsPerCheckout, &s.CheckoutAttempts, &s.CheckoutPercentage)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetCheckout121StatisticsForPlayer will return Checkout 121 statistics for the given player
+func GetCheckout121StatisticsForPlayer(id int) (*models.StatisticsCheckout121, error) {
+	s := new(models.StatisticsCheckout121)
+	err := models.DB.QueryRow(`
+		SELECT
+			p.id,
+			COUNT(DISTINCT m.id) AS 'matches_played',
+			COUNT(DISTINCT m2.id) AS 'matches_won',
+			COUNT(DISTINCT l.id) AS 'legs_played',
+			COUNT(DISTINCT l2.id) AS 'legs_won',
+			SUM(s.darts_thrown) as 'darts_thrown',
+			MAX(s.highest_target) as 'highest_target',
+			SUM(s.targets_attempted) as 'targets_attempted',
+			SUM(s.checkouts) as 'checkouts',
+			IFNULL(SUM(s.checkouts) / SUM(s.targets_attempted), 0) as 'success_rate',
+			IFNULL(SUM(s.darts_per_checkout * s.checkouts) / SUM(s.checkouts), 0) as 'darts_per_checkout',
+			SUM(s.checkout_attempts) as 'checkout_attempts',
+			IFNULL(SUM(s.checkouts) / SUM(s.checkout_attempts), 0) as 'checkout_percentage'
+		FROM statistics_checkout_121 s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
+			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
+		WHERE s.player_id = ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 22
+		GROUP BY p.id`, id).Scan(&s.PlayerID, &s.MatchesPlayed, &s.MatchesWon, &s.LegsPlayed, &s.LegsWon, &s.DartsThrown, &s.HighestTarget,
+		&s.TargetsAttempted, &s.Checkouts, &s.SuccessRate, &s.DartsPerCheckout, &s.CheckoutAttempts, &s.CheckoutPercentage)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return new(models.StatisticsCheckout121), nil
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetCheckout121HistoryForPlayer will return history of Checkout 121 statistics for the given player, showing the progress up the ladder
+func GetCheckout121HistoryForPlayer(id int, limit int) ([]*models.Leg, error) {
+	legs, err := GetLegsOfType(models.CHECKOUT121, false)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[int]*models.Leg)
+	for _, leg := range legs {
+		m[leg.ID] = leg
+	}
+
+	rows, err := models.DB.Query(`
+		SELECT
+			l.id,
+			p.id,
+			s.darts_thrown,
+			s.highest_target,
+			s.targets_attempted,
+			s.checkouts,
+			s.success_rate,
+			s.darts_per_checkout,
+			s.checkout_attempts,
+			s.checkout_percentage
+		FROM statistics_checkout_121 s
+			LEFT JOIN player p ON p.id = s.player_id
+			LEFT JOIN leg l ON l.id = s.leg_id
+			LEFT JOIN matches m ON m.id = l.match_id
+		WHERE s.player_id = ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 22
+		ORDER BY l.id DESC
+		LIMIT ?`, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	legs = make([]*models.Leg, 0)
+	for rows.Next() {
+		s := new(models.StatisticsCheckout121)
+		err := rows.Scan(&s.LegID, &s.PlayerID, &s.DartsThrown, &s.HighestTarget, &s.TargetsAttempted, &s.Checkouts, &s.SuccessRate,
+			&s.DartsPerCheckout, &s.CheckoutAttempts, &s.CheckoutPercentage)
+		if err != nil {
+			return nil, err
+		}
+		leg := m[s.LegID]
+		leg.Statistics = s
+		legs = append(legs, leg)
+	}
+	return legs, nil
+}
+
+// CalculateCheckout121Statistics will generate Checkout 121 statistics for the given leg
+func CalculateCheckout121Statistics(legID int) (map[int]*models.StatisticsCheckout121, error) {
+	visits, err := GetLegVisits(legID)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := GetPlayersScore(legID)
+	if err != nil {
+		return nil, err
+	}
+
+	statisticsMap := make(map[int]*models.StatisticsCheckout121)
+	for _, player := range players {
+		stats := new(models.StatisticsCheckout121)
+		stats.PlayerID = player.PlayerID
+		statisticsMap[player.PlayerID] = stats
+
+		ladder := player.Checkout121
+		stats.HighestTarget = ladder.HighestTarget
+		stats.TargetsAttempted = ladder.TargetsAttempted
+		stats.Checkouts = ladder.Checkouts
+		stats.CheckoutAttempts = ladder.CheckoutAttempts
+		if ladder.TargetsAttempted > 0 {
+			stats.SuccessRate = float64(ladder.Checkouts) / float64(ladder.TargetsAttempted)
+		}
+		if ladder.Checkouts > 0 {
+			stats.DartsPerCheckout = float64(ladder.CheckoutDarts) / float64(ladder.Checkouts)
+		}
+		if ladder.CheckoutAttempts > 0 {
+			stats.CheckoutPercentage = float64(ladder.Checkouts) / float64(ladder.CheckoutAttempts)
+		}
+	}
+
+	for _, visit := range visits {
+		statisticsMap[visit.PlayerID].DartsThrown += visit.GetDartsThrown()
+	}
+	return statisticsMap, nil
+}
+
+// ReCalculateCheckout121Statistics will recaulcate statistics for Checkout 121 legs
+func ReCalculateCheckout121Statistics() (map[int]map[int]*models.StatisticsCheckout121, error) {
+	legs, err := GetLegsOfType(models.CHECKOUT121, true)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make(map[int]map[int]*models.StatisticsCheckout121)
+	for _, leg := range legs {
+		stats, err := CalculateCheckout121Statistics(leg.ID)
+		if err != nil {
+			return nil, err
+		}
+		for playerID, stat := range stats {
+			log.Printf(`UPDATE statistics_checkout_121 SET darts_thrown = %d, highest_target = %d, targets_attempted = %d, checkouts = %d,
+			success_rate = %f, darts_per_checkout = %f, checkout_attempts = %d, checkout_percentage = %f WHERE leg_id = %d AND player_id = %d;`,
+				stat.DartsThrown, stat.HighestTarget, stat.TargetsAttempted, stat.Checkouts, stat.SuccessRate, stat.DartsPerCheckout,
+				stat.CheckoutAttempts, stat.CheckoutPercentage, leg.ID, playerID)
+		}
+		s[leg.ID] = stats
+	}
+
+	return s, err
+}
@@ -0,0 +1,324 @@
+package data
+
+import (
+	"log"
+
+	"github.com/kcapp/api/models"
+)
+
+// GetCustomStatistics will return statistics for all players active during the given period, for each game definition played
+func GetCustomStatistics(from string, to string) ([]*models.StatisticsCustom, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			p.id,
+			s.game_definition_id,
+			COUNT(DISTINCT m.id) AS 'matches_played',
+			COUNT(DISTINCT m2.id) AS 'matches_won',
+			COUNT(DISTINCT l.id) AS 'legs_played',
+			COUNT(DISTINCT l2.id) AS 'legs_won',
+			m.office_id AS 'office_id',
+			SUM(s.darts_thrown) as 'darts_thrown',
+			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
+			CAST(SUM(s.hit_count) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_hit_count',
+			SUM(s.total_hit_rate) / COUNT(l.id) as 'total_hit_rate'
+		FROM statistics_custom s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
+			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
+		WHERE m.updated_at >= ? AND m.updated_at < ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 23
+		GROUP BY p.id, s.game_definition_id, m.office_id
+		ORDER BY s.game_definition_id, (COUNT(DISTINCT m2.id) / COUNT(DISTINCT m.id)) DESC, matches_played DESC`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsCustom, 0)
+	for rows.Next() {
+		s := new(models.StatisticsCustom)
+		err := rows.Scan(&s.PlayerID, &s.GameDefinitionID, &s.MatchesPlayed, &s.MatchesWon, &s.LegsPlayed, &s.LegsWon, &s.OfficeID,
+			&s.DartsThrown, &s.Score, &s.HitCount, &s.TotalHitRate)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetCustomStatisticsForLeg will return statistics for all players in the given leg
+func GetCustomStatisticsForLeg(id int) ([]*models.StatisticsCustom, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			l.id,
+			p.id,
+			s.game_definition_id,
+			s.darts_thrown,
+			s.score,
+			s.hit_count,
+			s.total_hit_rate
+		FROM statistics_custom s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN player2leg p2l on l.id = p2l.leg_id AND p.id = p2l.player_id
+		WHERE l.id = ? GROUP BY p.id ORDER BY p2l.order`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsCustom, 0)
+	for rows.Next() {
+		s := new(models.StatisticsCustom)
+		err := rows.Scan(&s.LegID, &s.PlayerID, &s.GameDefinitionID, &s.DartsThrown, &s.Score, &s.HitCount, &s.TotalHitRate)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	hitrates, err := getRoundHitrates("SELECT player_id, round, hit_rate FROM statistics_custom_round WHERE leg_id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stats {
+		s.Hitrates = hitrates[s.PlayerID]
+	}
+	return stats, nil
+}
+
+// GetCustomStatisticsForMatch will return statistics for all players in the given match
+func GetCustomStatisticsForMatch(id int) ([]*models.StatisticsCustom, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			p.id,
+			s.game_definition_id,
+			SUM(s.darts_thrown) as 'darts_thrown',
+			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
+			CAST(SUM(s.hit_count) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_hit_count',
+			SUM(s.total_hit_rate) / COUNT(l.id) as 'total_hit_rate'
+		FROM statistics_custom s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			JOIN player2leg p2l ON p2l.leg_id = l.id AND p2l.player_id = s.player_id
+		WHERE m.id = ?
+		GROUP BY p.id, s.game_definition_id
+		ORDER BY p2l.order`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsCustom, 0)
+	for rows.Next() {
+		s := new(models.StatisticsCustom)
+		err := rows.Scan(&s.PlayerID, &s.GameDefinitionID, &s.DartsThrown, &s.Score, &s.HitCount, &s.TotalHitRate)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	hitrates, err := getRoundHitrates(`
+		SELECT s.player_id, s.round, AVG(s.hit_rate)
+		FROM statistics_custom_round s
+			JOIN leg l ON l.id = s.leg_id
+		WHERE l.match_id = ?
+		GROUP BY s.player_id, s.round`, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stats {
+		s.Hitrates = hitrates[s.PlayerID]
+	}
+	return stats, nil
+}
+
+// GetCustomStatisticsForPlayer will return custom game statistics for the given player, for each game definition played
+func GetCustomStatisticsForPlayer(id int) ([]*models.StatisticsCustom, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			p.id,
+			s.game_definition_id,
+			COUNT(DISTINCT m.id) AS 'matches_played',
+			COUNT(DISTINCT m2.id) AS 'matches_won',
+			COUNT(DISTINCT l.id) AS 'legs_played',
+			COUNT(DISTINCT l2.id) AS 'legs_won',
+			SUM(s.darts_thrown) as 'darts_thrown',
+			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
+			CAST(SUM(s.hit_count) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_hit_count',
+			SUM(s.total_hit_rate) / COUNT(l.id) as 'total_hit_rate'
+		FROM statistics_custom s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
+			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
+		WHERE s.player_id = ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 23
+		GROUP BY p.id, s.game_definition_id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsCustom, 0)
+	for rows.Next() {
+		s := new(models.StatisticsCustom)
+		err := rows.Scan(&s.PlayerID, &s.GameDefinitionID, &s.MatchesPlayed, &s.MatchesWon, &s.LegsPlayed, &s.LegsWon, &s.DartsThrown,
+			&s.Score, &s.HitCount, &s.TotalHitRate)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Rounds are grouped by game definition, since each definition has its own targets
+	hitrates, err := getRoundHitrates(`
+		SELECT s.game_definition_id, r.round, AVG(r.hit_rate)
+		FROM statistics_custom_round r
+			JOIN statistics_custom s ON s.leg_id = r.leg_id AND s.player_id = r.player_id
+			JOIN leg l ON l.id = r.leg_id
+			JOIN matches m ON m.id = l.match_id
+		WHERE r.player_id = ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+		GROUP BY s.game_definition_id, r.round`, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stats {
+		s.Hitrates = hitrates[s.GameDefinitionID]
+	}
+	return stats, nil
+}
+
+// GetCustomHistoryForPlayer will return history of custom game statistics for the given player
+func GetCustomHistoryForPlayer(id int, limit int) ([]*models.Leg, error) {
+	legs, err := GetLegsOfType(models.CUSTOM, false)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[int]*models.Leg)
+	for _, leg := range legs {
+		m[leg.ID] = leg
+	}
+
+	rows, err := models.DB.Query(`
+		SELECT
+			l.id,
+			p.id,
+			s.game_definition_id,
+			s.darts_thrown,
+			s.score,
+			s.hit_count,
+			s.total_hit_rate
+		FROM statistics_custom s
+			LEFT JOIN player p ON p.id = s.player_id
+			LEFT JOIN leg l ON l.id = s.leg_id
+			LEFT JOIN matches m ON m.id = l.match_id
+		WHERE s.player_id = ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 23
+		ORDER BY l.id DESC
+		LIMIT ?`, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	legs = make([]*models.Leg, 0)
+	for rows.Next() {
+		s := new(models.StatisticsCustom)
+		err := rows.Scan(&s.LegID, &s.PlayerID, &s.GameDefinitionID, &s.DartsThrown, &s.Score, &s.HitCount, &s.TotalHitRate)
+		if err != nil {
+			return nil, err
+		}
+		leg := m[s.LegID]
+		leg.Statistics = s
+		legs = append(legs, leg)
+	}
+	return legs, nil
+}
+
+// CalculateCustomStatistics will generate custom game statistics for the given leg
+func CalculateCustomStatistics(legID int) (map[int]*models.StatisticsCustom, error) {
+	leg, err := GetLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := GetPlayersScore(legID)
+	if err != nil {
+		return nil, err
+	}
+
+	def := leg.Parameters.GameDefinition
+	statisticsMap := make(map[int]*models.StatisticsCustom)
+	for _, player := range players {
+		stats := new(models.StatisticsCustom)
+		stats.PlayerID = player.PlayerID
+		stats.GameDefinitionID = def.ID
+		stats.Hitrates = make(map[int]float64)
+		for i := 1; i <= len(def.Targets); i++ {
+			stats.Hitrates[i] = 0
+		}
+		statisticsMap[player.PlayerID] = stats
+	}
+
+	for i, visit := range leg.Visits {
+		round := i / len(players)
+		if round >= len(def.Targets) {
+			break
+		}
+		stats := statisticsMap[visit.PlayerID]
+		stats.Score = def.CalculateScore(visit, round, stats.Score)
+
+		hits := def.GetHits(visit, round)
+		stats.Hitrates[round+1] = float64(hits) / 3.0
+		stats.HitCount += hits
+		stats.DartsThrown += 3
+	}
+
+	for _, stats := range statisticsMap {
+		stats.TotalHitRate = float64(stats.HitCount) / float64(len(def.Targets)*3)
+	}
+	return statisticsMap, nil
+}
+
+// ReCalculateCustomStatistics will recaulcate statistics for custom game legs
+func ReCalculateCustomStatistics() (map[int]map[int]*models.StatisticsCustom, error) {
+	legs, err := GetLegsOfType(models.CUSTOM, true)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make(map[int]map[int]*models.StatisticsCustom)
+	for _, leg := range legs {
+		stats, err := CalculateCustomStatistics(leg.ID)
+		if err != nil {
+			return nil, err
+		}
+		for playerID, stat := range stats {
+			log.Printf(`UPDATE statistics_custom SET darts_thrown = %d, score = %d, hit_count = %d, total_hit_rate = %f WHERE leg_id = %d AND player_id = %d;`,
+				stat.DartsThrown, stat.Score, stat.HitCount, stat.TotalHitRate, leg.ID, playerID)
+		}
+		s[leg.ID] = stats
+	}
+
+	return s, err
+}
@@ -21,7 +21,7 @@ func GetDartsAtXStatistics(from string, to string) ([]*models.StatisticsDartsAtX
 			SUM(s.singles) as 'singles',
 			SUM(s.doubles) as 'doubles',
 			SUM(s.triples) as 'triples',
-			SUM(s.singles + s.doubles + s.triples) / (99 * COUNT(DISTINCT l.id)) as 'hit_rate',
+			SUM(s.singles + s.doubles + s.triples) / SUM(IF(lp.darts > 0, CEIL(lp.darts / 3) * 3, 99)) as 'hit_rate',
 			SUM(s.hits5) as 'hits5',
 			SUM(s.hits6) as 'hits6',
 			SUM(s.hits7) as 'hits7',
@@ -31,6 +31,7 @@ func GetDartsAtXStatistics(from string, to string) ([]*models.StatisticsDartsAtX
 			JOIN player p ON p.id = s.player_id
 			JOIN leg l ON l.id = s.leg_id
 			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg_parameters lp ON lp.leg_id = l.id
 			LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
 			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
 		WHERE m.updated_at >= ? AND m.updated_at < ?
@@ -103,7 +104,7 @@ func GetDartsAtXStatisticsForMatch(id int) ([]*models.StatisticsDartsAtX, error)
 			SUM(s.singles) as 'singles',
 			SUM(s.doubles) as 'doubles',
 			SUM(s.triples) as 'triples',
-			SUM(s.singles + s.doubles + s.triples) / 99 * COUNT(DISTINCT l.id) as 'hit_rate',
+			SUM(s.singles + s.doubles + s.triples) / SUM(IF(lp.darts > 0, CEIL(lp.darts / 3) * 3, 99)) as 'hit_rate',
 			SUM(s.hits5) as 'hits5',
 			SUM(s.hits6) as 'hits6',
 			SUM(s.hits7) as 'hits7',
@@ -113,6 +114,7 @@ func GetDartsAtXStatisticsForMatch(id int) ([]*models.StatisticsDartsAtX, error)
 			JOIN player p ON p.id = s.player_id
 			JOIN leg l ON l.id = s.leg_id
 			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg_parameters lp ON lp.leg_id = l.id
 			JOIN player2leg p2l ON p2l.leg_id = l.id AND p2l.player_id = s.player_id
 		WHERE m.id = ?
 		GROUP BY p.id
@@ -148,7 +150,7 @@ func GetDartsAtXStatisticsForPlayer(id int) (*models.StatisticsDartsAtX, error)
 			SUM(s.singles) as 'singles',
 			SUM(s.doubles) as 'doubles',
 			SUM(s.triples) as 'triples',
-			SUM(s.singles + s.doubles + s.triples) / (99 * COUNT(DISTINCT l.id)) as 'hit_rate',
+			SUM(s.singles + s.doubles + s.triples) / SUM(IF(lp.darts > 0, CEIL(lp.darts / 3) * 3, 99)) as 'hit_rate',
 			SUM(s.hits5) as 'hits5',
 			SUM(s.hits6) as 'hits6',
 			SUM(s.hits7) as 'hits7',
@@ -158,6 +160,7 @@ func GetDartsAtXStatisticsForPlayer(id int) (*models.StatisticsDartsAtX, error)
 			JOIN player p ON p.id = s.player_id
 			JOIN leg l ON l.id = s.leg_id
 			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg_parameters lp ON lp.leg_id = l.id
 			LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
 			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
 		WHERE s.player_id = ?
@@ -306,8 +309,9 @@ func CalculateDartsAtXStatistics(legID int) (map[int]*models.StatisticsDartsAtX,
 			stats.Hits9++
 		}
 	}
+	darts := leg.Parameters.GetDartsAtXDarts()
 	for _, stat := range statisticsMap {
-		stat.HitRate = float32(stat.Singles+stat.Doubles+stat.Triples) / 99
+		stat.HitRate = float32(stat.Singles+stat.Doubles+stat.Triples) / float32(darts)
 	}
 	return statisticsMap, nil
 }
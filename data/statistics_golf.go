@@ -0,0 +1,372 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// GetGolfStatistics will return statistics for all players active during the given period
+func GetGolfStatistics(from string, to string) ([]*models.StatisticsGolf, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			p.id,
+			COUNT(DISTINCT m.id) AS 'matches_played',
+			COUNT(DISTINCT m2.id) AS 'matches_won',
+			COUNT(DISTINCT l.id) AS 'legs_played',
+			COUNT(DISTINCT l2.id) AS 'legs_won',
+			m.office_id AS 'office_id',
+			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
+			MIN(CASE WHEN s.holes = 9 THEN s.score END) as 'best_nine',
+			MIN(CASE WHEN s.holes = 18 THEN s.score END) as 'best_eighteen',
+			AVG(s.hole_1) as 'hole_1',
+			AVG(s.hole_2) as 'hole_2',
+			AVG(s.hole_3) as 'hole_3',
+			AVG(s.hole_4) as 'hole_4',
+			AVG(s.hole_5) as 'hole_5',
+			AVG(s.hole_6) as 'hole_6',
+			AVG(s.hole_7) as 'hole_7',
+			AVG(s.hole_8) as 'hole_8',
+			AVG(s.hole_9) as 'hole_9',
+			AVG(s.hole_10) as 'hole_10',
+			AVG(s.hole_11) as 'hole_11',
+			AVG(s.hole_12) as 'hole_12',
+			AVG(s.hole_13) as 'hole_13',
+			AVG(s.hole_14) as 'hole_14',
+			AVG(s.hole_15) as 'hole_15',
+			AVG(s.hole_16) as 'hole_16',
+			AVG(s.hole_17) as 'hole_17',
+			AVG(s.hole_18) as 'hole_18'
+		FROM statistics_golf s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
+			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
+		WHERE m.updated_at >= ? AND m.updated_at < ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 17
+		GROUP BY p.id, m.office_id
+		ORDER BY(COUNT(DISTINCT m2.id) / COUNT(DISTINCT m.id)) DESC, matches_played DESC`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsGolf, 0)
+	for rows.Next() {
+		s := new(models.StatisticsGolf)
+		h := make([]null.Float, 19)
+		err := rows.Scan(&s.PlayerID, &s.MatchesPlayed, &s.MatchesWon, &s.LegsPlayed, &s.LegsWon, &s.OfficeID, &s.Score, &s.BestNine, &s.BestEighteen,
+			&h[1], &h[2], &h[3], &h[4], &h[5], &h[6], &h[7], &h[8], &h[9],
+			&h[10], &h[11], &h[12], &h[13], &h[14], &h[15], &h[16], &h[17], &h[18])
+		if err != nil {
+			return nil, err
+		}
+		s.HoleScores = getGolfHoleScores(h)
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetGolfStatisticsForLeg will return statistics for all players in the given leg
+func GetGolfStatisticsForLeg(id int) ([]*models.StatisticsGolf, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			l.id,
+			p.id,
+			s.holes,
+			s.score,
+			s.hole_1,
+			s.hole_2,
+			s.hole_3,
+			s.hole_4,
+			s.hole_5,
+			s.hole_6,
+			s.hole_7,
+			s.hole_8,
+			s.hole_9,
+			s.hole_10,
+			s.hole_11,
+			s.hole_12,
+			s.hole_13,
+			s.hole_14,
+			s.hole_15,
+			s.hole_16,
+			s.hole_17,
+			s.hole_18
+		FROM statistics_golf s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN player2leg p2l on l.id = p2l.leg_id AND p.id = p2l.player_id
+		WHERE l.id = ? GROUP BY p.id ORDER BY p2l.order`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsGolf, 0)
+	for rows.Next() {
+		s := new(models.StatisticsGolf)
+		h := make([]null.Float, 19)
+		err := rows.Scan(&s.LegID, &s.PlayerID, &s.Holes, &s.Score,
+			&h[1], &h[2], &h[3], &h[4], &h[5], &h[6], &h[7], &h[8], &h[9],
+			&h[10], &h[11], &h[12], &h[13], &h[14], &h[15], &h[16], &h[17], &h[18])
+		if err != nil {
+			return nil, err
+		}
+		s.HoleScores = getGolfHoleScores(h)
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetGolfStatisticsForMatch will return statistics for all players in the given match
+func GetGolfStatisticsForMatch(id int) ([]*models.StatisticsGolf, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			p.id,
+			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
+			MIN(CASE WHEN s.holes = 9 THEN s.score END) as 'best_nine',
+			MIN(CASE WHEN s.holes = 18 THEN s.score END) as 'best_eighteen',
+			AVG(s.hole_1) as 'hole_1',
+			AVG(s.hole_2) as 'hole_2',
+			AVG(s.hole_3) as 'hole_3',
+			AVG(s.hole_4) as 'hole_4',
+			AVG(s.hole_5) as 'hole_5',
+			AVG(s.hole_6) as 'hole_6',
+			AVG(s.hole_7) as 'hole_7',
+			AVG(s.hole_8) as 'hole_8',
+			AVG(s.hole_9) as 'hole_9',
+			AVG(s.hole_10) as 'hole_10',
+			AVG(s.hole_11) as 'hole_11',
+			AVG(s.hole_12) as 'hole_12',
+			AVG(s.hole_13) as 'hole_13',
+			AVG(s.hole_14) as 'hole_14',
+			AVG(s.hole_15) as 'hole_15',
+			AVG(s.hole_16) as 'hole_16',
+			AVG(s.hole_17) as 'hole_17',
+			AVG(s.hole_18) as 'hole_18'
+		FROM statistics_golf s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			JOIN player2leg p2l ON p2l.leg_id = l.id AND p2l.player_id = s.player_id
+		WHERE m.id = ?
+		GROUP BY p.id
+		ORDER BY p2l.order`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsGolf, 0)
+	for rows.Next() {
+		s := new(models.StatisticsGolf)
+		h := make([]null.Float, 19)
+		err := rows.Scan(&s.PlayerID, &s.Score, &s.BestNine, &s.BestEighteen,
+			&h[1], &h[2], &h[3], &h[4], &h[5], &h[6], &h[7], &h[8], &h[9],
+			&h[10], &h[11], &h[12], &h[13], &h[14], &h[15], &h[16], &h[17], &h[18])
+		if err != nil {
+			return nil, err
+		}
+		s.HoleScores = getGolfHoleScores(h)
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetGolfStatisticsForPlayer will return Golf statistics for the given player
+func GetGolfStatisticsForPlayer(id int) (*models.StatisticsGolf, error) {
+	s := new(models.StatisticsGolf)
+	h := make([]null.Float, 19)
+	err := models.DB.QueryRow(`
+		SELECT
+			p.id,
+			COUNT(DISTINCT m.id) AS 'matches_played',
+			COUNT(DISTINCT m2.id) AS 'matches_won',
+			COUNT(DISTINCT l.id) AS 'legs_played',
+			COUNT(DISTINCT l2.id) AS 'legs_won',
+			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
+			MIN(CASE WHEN s.holes = 9 THEN s.score END) as 'best_nine',
+			MIN(CASE WHEN s.holes = 18 THEN s.score END) as 'best_eighteen',
+			AVG(s.hole_1) as 'hole_1',
+			AVG(s.hole_2) as 'hole_2',
+			AVG(s.hole_3) as 'hole_3',
+			AVG(s.hole_4) as 'hole_4',
+			AVG(s.hole_5) as 'hole_5',
+			AVG(s.hole_6) as 'hole_6',
+			AVG(s.hole_7) as 'hole_7',
+			AVG(s.hole_8) as 'hole_8',
+			AVG(s.hole_9) as 'hole_9',
+			AVG(s.hole_10) as 'hole_10',
+			AVG(s.hole_11) as 'hole_11',
+			AVG(s.hole_12) as 'hole_12',
+			AVG(s.hole_13) as 'hole_13',
+			AVG(s.hole_14) as 'hole_14',
+			AVG(s.hole_15) as 'hole_15',
+			AVG(s.hole_16) as 'hole_16',
+			AVG(s.hole_17) as 'hole_17',
+			AVG(s.hole_18) as 'hole_18'
+		FROM statistics_golf s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
+			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
+		WHERE s.player_id = ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 17
+		GROUP BY p.id`, id).Scan(&s.PlayerID, &s.MatchesPlayed, &s.MatchesWon, &s.LegsPlayed, &s.LegsWon, &s.Score, &s.BestNine, &s.BestEighteen,
+		&h[1], &h[2], &h[3], &h[4], &h[5], &h[6], &h[7], &h[8], &h[9],
+		&h[10], &h[11], &h[12], &h[13], &h[14], &h[15], &h[16], &h[17], &h[18])
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return new(models.StatisticsGolf), nil
+		}
+		return nil, err
+	}
+	s.HoleScores = getGolfHoleScores(h)
+	return s, nil
+}
+
+// GetGolfHistoryForPlayer will return history of Golf statistics for the given player
+func GetGolfHistoryForPlayer(id int, limit int) ([]*models.Leg, error) {
+	legs, err := GetLegsOfType(models.GOLF, false)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[int]*models.Leg)
+	for _, leg := range legs {
+		m[leg.ID] = leg
+	}
+
+	rows, err := models.DB.Query(`
+		SELECT
+			l.id,
+			p.id,
+			s.holes,
+			s.score,
+			s.hole_1,
+			s.hole_2,
+			s.hole_3,
+			s.hole_4,
+			s.hole_5,
+			s.hole_6,
+			s.hole_7,
+			s.hole_8,
+			s.hole_9,
+			s.hole_10,
+			s.hole_11,
+			s.hole_12,
+			s.hole_13,
+			s.hole_14,
+			s.hole_15,
+			s.hole_16,
+			s.hole_17,
+			s.hole_18
+		FROM statistics_golf s
+			LEFT JOIN player p ON p.id = s.player_id
+			LEFT JOIN leg l ON l.id = s.leg_id
+			LEFT JOIN matches m ON m.id = l.match_id
+		WHERE s.player_id = ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 17
+		ORDER BY l.id DESC
+		LIMIT ?`, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	legs = make([]*models.Leg, 0)
+	for rows.Next() {
+		s := new(models.StatisticsGolf)
+		h := make([]null.Float, 19)
+		err := rows.Scan(&s.LegID, &s.PlayerID, &s.Holes, &s.Score,
+			&h[1], &h[2], &h[3], &h[4], &h[5], &h[6], &h[7], &h[8], &h[9],
+			&h[10], &h[11], &h[12], &h[13], &h[14], &h[15], &h[16], &h[17], &h[18])
+		if err != nil {
+			return nil, err
+		}
+		s.HoleScores = getGolfHoleScores(h)
+
+		leg := m[s.LegID]
+		leg.Statistics = s
+		legs = append(legs, leg)
+	}
+	return legs, nil
+}
+
+// CalculateGolfStatistics will generate Golf statistics for the given leg
+func CalculateGolfStatistics(legID int) (map[int]*models.StatisticsGolf, error) {
+	leg, err := GetLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := GetPlayersScore(legID)
+	if err != nil {
+		return nil, err
+	}
+
+	statisticsMap := make(map[int]*models.StatisticsGolf)
+	for _, player := range players {
+		stats := new(models.StatisticsGolf)
+		stats.PlayerID = player.PlayerID
+		stats.Holes = leg.Parameters.GetGolfHoles()
+		stats.HoleScores = make(map[int]float64)
+		statisticsMap[player.PlayerID] = stats
+	}
+
+	hole := 0
+	for i, visit := range leg.Visits {
+		if i > 0 && i%len(players) == 0 {
+			hole++
+		}
+		if hole >= leg.Parameters.GetGolfHoles() {
+			break
+		}
+		stats := statisticsMap[visit.PlayerID]
+		strokes := visit.CalculateGolfScore(hole)
+		stats.HoleScores[hole+1] = float64(strokes)
+		stats.Score += strokes
+	}
+	return statisticsMap, nil
+}
+
+// ReCalculateGolfStatistics will recaulcate statistics for Golf legs
+func ReCalculateGolfStatistics() (map[int]map[int]*models.StatisticsGolf, error) {
+	legs, err := GetLegsOfType(models.GOLF, true)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make(map[int]map[int]*models.StatisticsGolf)
+	for _, leg := range legs {
+		stats, err := CalculateGolfStatistics(leg.ID)
+		if err != nil {
+			return nil, err
+		}
+		for playerID, stat := range stats {
+			log.Printf(`UPDATE statistics_golf SET holes = %d, score = %d WHERE leg_id = %d AND player_id = %d;`,
+				stat.Holes, stat.Score, leg.ID, playerID)
+		}
+		s[leg.ID] = stats
+	}
+
+	return s, err
+}
+
+// getGolfHoleScores will return the given strokes for each hole played
+func getGolfHoleScores(h []null.Float) map[int]float64 {
+	holes := make(map[int]float64)
+	for i := 1; i <= 18; i++ {
+		if h[i].Valid {
+			holes[i] = h[i].Float64
+		}
+	}
+	return holes
+}
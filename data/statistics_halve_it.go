@@ -0,0 +1,355 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/kcapp/api/models"
+)
+
+// GetHalveItStatistics will return statistics for all players active during the given period
+func GetHalveItStatistics(from string, to string) ([]*models.StatisticsHalveIt, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			p.id,
+			COUNT(DISTINCT m.id) AS 'matches_played',
+			COUNT(DISTINCT m2.id) AS 'matches_won',
+			COUNT(DISTINCT l.id) AS 'legs_played',
+			COUNT(DISTINCT l2.id) AS 'legs_won',
+			m.office_id AS 'office_id',
+			SUM(s.darts_thrown) as 'darts_thrown',
+			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
+			SUM(s.mpr) / COUNT(l.id) as 'mpr',
+			MAX(s.highest_score_reached) as 'highest_score_reached',
+			SUM(s.times_halved) as 'times_halved',
+			SUM(s.total_hit_rate) / COUNT(l.id) as 'total_hit_rate',
+			CAST(SUM(s.hit_count) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_hit_count'
+		FROM statistics_halve_it s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
+			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
+		WHERE m.updated_at >= ? AND m.updated_at < ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 18
+		GROUP BY p.id, m.office_id
+		ORDER BY(COUNT(DISTINCT m2.id) / COUNT(DISTINCT m.id)) DESC, matches_played DESC`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsHalveIt, 0)
+	for rows.Next() {
+		s := new(models.StatisticsHalveIt)
+		err := rows.Scan(&s.PlayerID, &s.MatchesPlayed, &s.MatchesWon, &s.LegsPlayed, &s.LegsWon, &s.OfficeID, &s.DartsThrown,
+			&s.Score, &s.MPR, &s.HighestScoreReached, &s.TimesHalved, &s.TotalHitRate, &s.HitCount)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetHalveItStatisticsForLeg will return statistics for all players in the given leg
+func GetHalveItStatisticsForLeg(id int) ([]*models.StatisticsHalveIt, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			l.id,
+			p.id,
+			s.darts_thrown,
+			s.score,
+			s.mpr,
+			s.total_marks,
+			s.highest_score_reached,
+			s.times_halved,
+			s.total_hit_rate,
+			s.hit_count
+		FROM statistics_halve_it s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN player2leg p2l on l.id = p2l.leg_id AND p.id = p2l.player_id
+		WHERE l.id = ? GROUP BY p.id ORDER BY p2l.order`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsHalveIt, 0)
+	for rows.Next() {
+		s := new(models.StatisticsHalveIt)
+		err := rows.Scan(&s.LegID, &s.PlayerID, &s.DartsThrown, &s.Score, &s.MPR, &s.TotalMarks, &s.HighestScoreReached,
+			&s.TimesHalved, &s.TotalHitRate, &s.HitCount)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	hitrates, err := getRoundHitrates("SELECT player_id, round, hit_rate FROM statistics_halve_it_round WHERE leg_id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stats {
+		s.Hitrates = hitrates[s.PlayerID]
+	}
+	return stats, nil
+}
+
+// GetHalveItStatisticsForMatch will return statistics for all players in the given match
+func GetHalveItStatisticsForMatch(id int) ([]*models.StatisticsHalveIt, error) {
+	rows, err := models.DB.Query(`
+		SELECT
+			p.id,
+			SUM(s.darts_thrown) as 'darts_thrown',
+			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
+			SUM(s.mpr) / COUNT(l.id) as 'mpr',
+			SUM(s.total_marks) as 'total_marks',
+			MAX(s.highest_score_reached) as 'highest_score_reached',
+			SUM(s.times_halved) as 'times_halved',
+			SUM(s.total_hit_rate) / COUNT(l.id) as 'total_hit_rate',
+			CAST(SUM(s.hit_count) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_hit_count'
+		FROM statistics_halve_it s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			JOIN player2leg p2l ON p2l.leg_id = l.id AND p2l.player_id = s.player_id
+		WHERE m.id = ?
+		GROUP BY p.id
+		ORDER BY p2l.order`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsHalveIt, 0)
+	for rows.Next() {
+		s := new(models.StatisticsHalveIt)
+		err := rows.Scan(&s.PlayerID, &s.DartsThrown, &s.Score, &s.MPR, &s.TotalMarks, &s.HighestScoreReached, &s.TimesHalved,
+			&s.TotalHitRate, &s.HitCount)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	hitrates, err := getRoundHitrates(`
+		SELECT s.player_id, s.round, AVG(s.hit_rate)
+		FROM statistics_halve_it_round s
+			JOIN leg l ON l.id = s.leg_id
+		WHERE l.match_id = ?
+		GROUP BY s.player_id, s.round`, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range stats {
+		s.Hitrates = hitrates[s.PlayerID]
+	}
+	return stats, nil
+}
+
+// GetHalveItStatisticsForPlayer will return Halve-It statistics for the given player
+func GetHalveItStatisticsForPlayer(id int) (*models.StatisticsHalveIt, error) {
+	s := new(models.StatisticsHalveIt)
+	err := models.DB.QueryRow(`
+		SELECT
+			p.id,
+			COUNT(DISTINCT m.id) AS 'matches_played',
+			COUNT(DISTINCT m2.id) AS 'matches_won',
+			COUNT(DISTINCT l.id) AS 'legs_played',
+			COUNT(DISTINCT l2.id) AS 'legs_won',
+			SUM(s.darts_thrown) as 'darts_thrown',
+			CAST(SUM(s.score) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_score',
+			SUM(s.mpr) / COUNT(l.id) as 'mpr',
+			MAX(s.highest_score_reached) as 'highest_score_reached',
+			SUM(s.times_halved) as 'times_halved',
+			SUM(s.total_hit_rate) / COUNT(l.id) as 'total_hit_rate',
+			CAST(SUM(s.hit_count) / COUNT(DISTINCT l.id) AS SIGNED) as 'avg_hit_count'
+		FROM statistics_halve_it s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			JOIN matches m ON m.id = l.match_id
+			LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
+			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
+		WHERE s.player_id = ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 18
+		GROUP BY p.id`, id).Scan(&s.PlayerID, &s.MatchesPlayed, &s.MatchesWon, &s.LegsPlayed, &s.LegsWon, &s.DartsThrown, &s.Score,
+		&s.MPR, &s.HighestScoreReached, &s.TimesHalved, &s.TotalHitRate, &s.HitCount)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return new(models.StatisticsHalveIt), nil
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetHalveItHistoryForPlayer will return history of Halve-It statistics for the given player
+func GetHalveItHistoryForPlayer(id int, limit int) ([]*models.Leg, error) {
+	legs, err := GetLegsOfType(models.HALVEIT, false)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[int]*models.Leg)
+	for _, leg := range legs {
+		m[leg.ID] = leg
+	}
+
+	rows, err := models.DB.Query(`
+		SELECT
+			l.id,
+			p.id,
+			s.darts_thrown,
+			s.score,
+			s.mpr,
+			s.total_marks,
+			s.highest_score_reached,
+			s.times_halved,
+			s.total_hit_rate,
+			s.hit_count
+		FROM statistics_halve_it s
+			LEFT JOIN player p ON p.id = s.player_id
+			LEFT JOIN leg l ON l.id = s.leg_id
+			LEFT JOIN matches m ON m.id = l.match_id
+		WHERE s.player_id = ?
+			AND l.is_finished = 1 AND m.is_abandoned = 0
+			AND m.match_type_id = 18
+		ORDER BY l.id DESC
+		LIMIT ?`, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	legs = make([]*models.Leg, 0)
+	for rows.Next() {
+		s := new(models.StatisticsHalveIt)
+		err := rows.Scan(&s.LegID, &s.PlayerID, &s.DartsThrown, &s.Score, &s.MPR, &s.TotalMarks, &s.HighestScoreReached,
+			&s.TimesHalved, &s.TotalHitRate, &s.HitCount)
+		if err != nil {
+			return nil, err
+		}
+		leg := m[s.LegID]
+		leg.Statistics = s
+		legs = append(legs, leg)
+	}
+	return legs, nil
+}
+
+// CalculateHalveItStatistics will generate Halve-It statistics for the given leg
+func CalculateHalveItStatistics(legID int) (map[int]*models.StatisticsHalveIt, error) {
+	leg, err := GetLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := GetPlayersScore(legID)
+	if err != nil {
+		return nil, err
+	}
+
+	def := leg.Parameters.GetHalveItDefinition()
+	statisticsMap := make(map[int]*models.StatisticsHalveIt)
+	for _, player := range players {
+		stats := new(models.StatisticsHalveIt)
+		stats.PlayerID = player.PlayerID
+		stats.Hitrates = make(map[int]float64)
+		for i := 1; i <= len(def.Targets); i++ {
+			stats.Hitrates[i] = 0
+		}
+		statisticsMap[player.PlayerID] = stats
+	}
+
+	for i, visit := range leg.Visits {
+		round := i / len(players)
+		if round >= len(def.Targets) {
+			break
+		}
+		stats := statisticsMap[visit.PlayerID]
+
+		hits := def.GetHits(visit, round)
+		if hits == 0 {
+			stats.TimesHalved++
+		}
+		stats.Score = def.CalculateScore(visit, round, stats.Score)
+
+		marks := 0
+		for _, dart := range []*models.Dart{visit.FirstDart, visit.SecondDart, visit.ThirdDart} {
+			if dart.IsTargetHit(def.Targets[round]) {
+				marks += int(dart.Multiplier)
+			}
+		}
+		stats.Hitrates[round+1] = float64(hits) / 3.0
+
+		stats.TotalMarks += marks
+		stats.HitCount += hits
+		stats.DartsThrown += 3
+
+		if stats.Score > stats.HighestScoreReached {
+			stats.HighestScoreReached = stats.Score
+		}
+	}
+
+	for _, stats := range statisticsMap {
+		stats.MPR = float64(stats.TotalMarks) / float64(len(def.Targets))
+		stats.TotalHitRate = float64(stats.HitCount) / float64(len(def.Targets)*3)
+	}
+	return statisticsMap, nil
+}
+
+// ReCalculateHalveItStatistics will recaulcate statistics for Halve-It legs
+func ReCalculateHalveItStatistics() (map[int]map[int]*models.StatisticsHalveIt, error) {
+	legs, err := GetLegsOfType(models.HALVEIT, true)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make(map[int]map[int]*models.StatisticsHalveIt)
+	for _, leg := range legs {
+		stats, err := CalculateHalveItStatistics(leg.ID)
+		if err != nil {
+			return nil, err
+		}
+		for playerID, stat := range stats {
+			log.Printf(`UPDATE statistics_halve_it SET darts_thrown = %d, score = %d, mpr = %f, total_marks = %d, highest_score_reached = %d, times_halved = %d,
+			total_hit_rate = %f, hit_count = %d WHERE leg_id = %d AND player_id = %d;`,
+				stat.DartsThrown, stat.Score, stat.MPR, stat.TotalMarks, stat.HighestScoreReached, stat.TimesHalved, stat.TotalHitRate, stat.HitCount, leg.ID, playerID)
+		}
+		s[leg.ID] = stats
+	}
+
+	return s, err
+}
+
+// getRoundHitrates will return the hit rate of each round for each id, from a query returning id (usually player), round and hit rate
+func getRoundHitrates(query string, args ...interface{}) (map[int]map[int]float64, error) {
+	rows, err := models.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hitrates := make(map[int]map[int]float64)
+	for rows.Next() {
+		var playerID, round int
+		var hitrate float64
+		err := rows.Scan(&playerID, &round, &hitrate)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := hitrates[playerID]; !ok {
+			hitrates[playerID] = make(map[int]float64)
+		}
+		hitrates[playerID][round] = hitrate
+	}
+	return hitrates, nil
+}
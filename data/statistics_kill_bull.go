@@ -237,7 +237,7 @@ func CalculateKillBullStatistics(legID int) (map[int]*models.StatisticsKillBull,
 	for _, player := range players {
 		stats := new(models.StatisticsKillBull)
 		stats.PlayerID = player.PlayerID
-		stats.Score = player.StartingScore
+		stats.Score = leg.Parameters.GetKillBullStartingScore(player.StartingScore)
 		stats.TotalHitRate = 0
 		statisticsMap[player.PlayerID] = stats
 	}
@@ -246,12 +246,13 @@ func CalculateKillBullStatistics(legID int) (map[int]*models.StatisticsKillBull,
 		stats := statisticsMap[visit.PlayerID]
 		player := players[visit.PlayerID]
 
+		startingScore := leg.Parameters.GetKillBullStartingScore(player.StartingScore)
 		score := visit.CalculateKillBullScore()
 		if score == 0 {
-			if stats.Score < player.StartingScore {
+			if stats.Score < startingScore {
 				stats.TimesBusted++
 			}
-			stats.Score = player.StartingScore
+			stats.Score = startingScore
 		} else {
 			stats.Score -= score
 			if stats.Score < 0 {
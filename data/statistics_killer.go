@@ -0,0 +1,287 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/guregu/null"
+	"github.com/kcapp/api/models"
+)
+
+// GetKillerStatistics will return statistics for all players active during the given period
+func GetKillerStatistics(from string, to string) ([]*models.StatisticsKiller, error) {
+	rows, err := models.DB.Query(`
+			SELECT
+				p.id,
+				COUNT(DISTINCT m.id) AS 'matches_played',
+				COUNT(DISTINCT m2.id) AS 'matches_won',
+				COUNT(DISTINCT l.id) AS 'legs_played',
+				COUNT(DISTINCT l2.id) AS 'legs_won',
+				m.office_id AS 'office_id',
+				SUM(s.darts_thrown) as 'darts_thrown',
+				CAST(AVG(s.darts_to_become_killer) AS SIGNED) as 'darts_to_become_killer',
+				SUM(s.lives_lost) as 'lives_lost',
+				SUM(s.lives_taken) as 'lives_taken',
+				CAST(SUM(s.final_position) / COUNT(DISTINCT l.id) AS SIGNED) as 'final_position'
+			FROM statistics_killer s
+				JOIN player p ON p.id = s.player_id
+				JOIN leg l ON l.id = s.leg_id
+				JOIN matches m ON m.id = l.match_id
+				LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
+				LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
+			WHERE m.updated_at >= ? AND m.updated_at < ?
+				AND l.is_finished = 1 AND m.is_abandoned = 0
+				AND m.match_type_id = 16
+			GROUP BY p.id, m.office_id
+			ORDER BY(COUNT(DISTINCT m2.id) / COUNT(DISTINCT m.id)) DESC, matches_played DESC`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsKiller, 0)
+	for rows.Next() {
+		s := new(models.StatisticsKiller)
+		err := rows.Scan(&s.PlayerID, &s.MatchesPlayed, &s.MatchesWon, &s.LegsPlayed, &s.LegsWon, &s.OfficeID, &s.DartsThrown,
+			&s.DartsToBecomeKiller, &s.LivesLost, &s.LivesTaken, &s.FinalPosition)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetKillerStatisticsForLeg will return statistics for all players in the given leg
+func GetKillerStatisticsForLeg(id int) ([]*models.StatisticsKiller, error) {
+	rows, err := models.DB.Query(`
+			SELECT
+				l.id,
+				p.id,
+				s.darts_thrown,
+				s.darts_to_become_killer,
+				s.lives_lost,
+				s.lives_taken,
+				s.final_position
+			FROM statistics_killer s
+				JOIN player p ON p.id = s.player_id
+				JOIN leg l ON l.id = s.leg_id
+				JOIN player2leg p2l on l.id = p2l.leg_id AND p.id = p2l.player_id
+			WHERE l.id = ? GROUP BY p.id ORDER BY p2l.order`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsKiller, 0)
+	for rows.Next() {
+		s := new(models.StatisticsKiller)
+		err := rows.Scan(&s.LegID, &s.PlayerID, &s.DartsThrown, &s.DartsToBecomeKiller, &s.LivesLost, &s.LivesTaken, &s.FinalPosition)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetKillerStatisticsForMatch will return statistics for all players in the given match
+func GetKillerStatisticsForMatch(id int) ([]*models.StatisticsKiller, error) {
+	rows, err := models.DB.Query(`
+			SELECT
+				p.id,
+				SUM(s.darts_thrown) as 'darts_thrown',
+				CAST(AVG(s.darts_to_become_killer) AS SIGNED) as 'darts_to_become_killer',
+				SUM(s.lives_lost) as 'lives_lost',
+				SUM(s.lives_taken) as 'lives_taken',
+				CAST(SUM(s.final_position) / COUNT(DISTINCT l.id) AS SIGNED) as 'final_position'
+			FROM statistics_killer s
+				JOIN player p ON p.id = s.player_id
+				JOIN leg l ON l.id = s.leg_id
+				JOIN matches m ON m.id = l.match_id
+				JOIN player2leg p2l ON p2l.leg_id = l.id AND p2l.player_id = s.player_id
+			WHERE m.id = ?
+			GROUP BY p.id
+			ORDER BY p2l.order`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsKiller, 0)
+	for rows.Next() {
+		s := new(models.StatisticsKiller)
+		err := rows.Scan(&s.PlayerID, &s.DartsThrown, &s.DartsToBecomeKiller, &s.LivesLost, &s.LivesTaken, &s.FinalPosition)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetKillerStatisticsForPlayer will return Killer statistics for the given player
+func GetKillerStatisticsForPlayer(id int) (*models.StatisticsKiller, error) {
+	s := new(models.StatisticsKiller)
+	err := models.DB.QueryRow(`
+			SELECT
+				p.id,
+				COUNT(DISTINCT m.id) AS 'matches_played',
+				COUNT(DISTINCT m2.id) AS 'matches_won',
+				COUNT(DISTINCT l.id) AS 'legs_played',
+				COUNT(DISTINCT l2.id) AS 'legs_won',
+				SUM(s.darts_thrown) as 'darts_thrown',
+				CAST(AVG(s.darts_to_become_killer) AS SIGNED) as 'darts_to_become_killer',
+				SUM(s.lives_lost) as 'lives_lost',
+				SUM(s.lives_taken) as 'lives_taken',
+				CAST(SUM(s.final_position) / COUNT(DISTINCT l.id) AS SIGNED) as 'final_position'
+			FROM statistics_killer s
+				JOIN player p ON p.id = s.player_id
+				JOIN leg l ON l.id = s.leg_id
+				JOIN matches m ON m.id = l.match_id
+				LEFT JOIN leg l2 ON l2.id = s.leg_id AND l2.winner_id = p.id
+				LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
+			WHERE s.player_id = ?
+				AND l.is_finished = 1 AND m.is_abandoned = 0
+				AND m.match_type_id = 16
+			GROUP BY p.id`, id).Scan(&s.PlayerID, &s.MatchesPlayed, &s.MatchesWon, &s.LegsPlayed, &s.LegsWon, &s.DartsThrown,
+		&s.DartsToBecomeKiller, &s.LivesLost, &s.LivesTaken, &s.FinalPosition)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return new(models.StatisticsKiller), nil
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// GetKillerHistoryForPlayer will return history of Killer statistics for the given player
+func GetKillerHistoryForPlayer(id int, limit int) ([]*models.Leg, error) {
+	legs, err := GetLegsOfType(models.KILLER, false)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[int]*models.Leg)
+	for _, leg := range legs {
+		m[leg.ID] = leg
+	}
+
+	rows, err := models.DB.Query(`
+			SELECT
+				l.id,
+				p.id,
+				s.darts_thrown,
+				s.darts_to_become_killer,
+				s.lives_lost,
+				s.lives_taken,
+				s.final_position
+			FROM statistics_killer s
+				LEFT JOIN player p ON p.id = s.player_id
+				LEFT JOIN leg l ON l.id = s.leg_id
+				LEFT JOIN matches m ON m.id = l.match_id
+			WHERE s.player_id = ?
+				AND l.is_finished = 1 AND m.is_abandoned = 0
+				AND m.match_type_id = 16
+			ORDER BY l.id DESC
+			LIMIT ?`, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	legs = make([]*models.Leg, 0)
+	for rows.Next() {
+		s := new(models.StatisticsKiller)
+		err := rows.Scan(&s.LegID, &s.PlayerID, &s.DartsThrown, &s.DartsToBecomeKiller, &s.LivesLost, &s.LivesTaken, &s.FinalPosition)
+		if err != nil {
+			return nil, err
+		}
+		leg := m[s.LegID]
+		leg.Statistics = s
+		legs = append(legs, leg)
+	}
+	return legs, nil
+}
+
+// CalculateKillerStatistics will generate Killer statistics for the given leg
+func CalculateKillerStatistics(legID int) (map[int]*models.StatisticsKiller, error) {
+	leg, err := GetLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+
+	players, err := GetPlayersScore(legID)
+	if err != nil {
+		return nil, err
+	}
+
+	statisticsMap := make(map[int]*models.StatisticsKiller)
+	for _, player := range players {
+		stats := new(models.StatisticsKiller)
+		stats.PlayerID = player.PlayerID
+		statisticsMap[player.PlayerID] = stats
+		player.Lives = leg.Parameters.StartingLives
+	}
+	params := *leg.Parameters
+	params.Killers = make(map[int]bool)
+
+	finalPosition := len(players)
+	for _, visit := range leg.Visits {
+		stats := statisticsMap[visit.PlayerID]
+		isKiller := params.Killers[visit.PlayerID]
+		lives := make(map[int]int64)
+		for id, player := range players {
+			lives[id] = player.Lives.Int64
+		}
+
+		stats.LivesTaken += visit.CalculateKillerScore(players, &params)
+		if !isKiller && params.Killers[visit.PlayerID] {
+			for i, dart := range visit.GetDarts() {
+				if dart.IsDouble() && dart.ValueRaw() == params.PlayerNumbers[visit.PlayerID] {
+					stats.DartsToBecomeKiller = null.IntFrom(int64(stats.DartsThrown + i + 1))
+					break
+				}
+			}
+		}
+		stats.DartsThrown += visit.GetDartsThrown()
+
+		for id, player := range players {
+			if lives[id] > 0 && player.Lives.Int64 == 0 {
+				statisticsMap[id].FinalPosition = finalPosition
+				finalPosition--
+			}
+		}
+	}
+
+	for playerID, stats := range statisticsMap {
+		stats.LivesLost = int(leg.Parameters.StartingLives.Int64 - players[playerID].Lives.Int64)
+		if stats.FinalPosition == 0 {
+			stats.FinalPosition = finalPosition
+		}
+	}
+	return statisticsMap, nil
+}
+
+// ReCalculateKillerStatistics will recaulcate statistics for Killer legs
+func ReCalculateKillerStatistics() (map[int]map[int]*models.StatisticsKiller, error) {
+	legs, err := GetLegsOfType(models.KILLER, true)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make(map[int]map[int]*models.StatisticsKiller)
+	for _, leg := range legs {
+		stats, err := CalculateKillerStatistics(leg.ID)
+		if err != nil {
+			return nil, err
+		}
+		for playerID, stat := range stats {
+			log.Printf(`UPDATE statistics_killer SET darts_thrown = %d, darts_to_become_killer = %d, lives_lost = %d, lives_taken = %d,
+			final_position = %d WHERE leg_id = %d AND player_id = %d;`,
+				stat.DartsThrown, stat.DartsToBecomeKiller.Int64, stat.LivesLost, stat.LivesTaken, stat.FinalPosition, leg.ID, playerID)
+		}
+		s[leg.ID] = stats
+	}
+
+	return s, err
+}
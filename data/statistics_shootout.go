@@ -31,7 +31,7 @@ func GetShootoutStatistics(from string, to string) ([]*models.StatisticsShootout
 			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
 		WHERE m.updated_at >= ? AND m.updated_at < ?
 			AND m.is_finished = 1 AND m.is_abandoned = 0
-			AND m.match_type_id = 2
+			AND m.match_type_id IN (2, 21)
 		GROUP BY p.id, m.office_id
 		ORDER BY(COUNT(DISTINCT m2.id) / COUNT(DISTINCT m.id)) DESC, matches_played DESC, ppd DESC`, from, to)
 	if err != nil {
@@ -70,7 +70,7 @@ func GetShootoutStatisticsForMatch(matchID int) ([]*models.StatisticsShootout, e
 			JOIN matches m ON m.id = l.match_id
 		WHERE m.id = ?
 			AND m.is_finished = 1 AND m.is_abandoned = 0
-			AND m.match_type_id = 2
+			AND m.match_type_id IN (2, 21)
 		GROUP BY p.id`, matchID)
 	if err != nil {
 		return nil, err
@@ -146,7 +146,7 @@ func GetShootoutStatisticsForPlayer(id int) (*models.StatisticsShootout, error)
 			LEFT JOIN matches m2 ON m2.id = l.match_id AND m2.winner_id = p.id
 		WHERE s.player_id = ?
 			AND l.is_finished = 1 AND m.is_abandoned = 0
-			AND m.match_type_id = 2
+			AND m.match_type_id IN (2, 21)
 		GROUP BY p.id`, id).Scan(&s.PlayerID, &s.MatchesPlayed, &s.MatchesWon, &s.LegsPlayed, &s.LegsWon, &s.Score, &s.PPD, &s.Score60sPlus, &s.Score100sPlus, &s.Score140sPlus, &s.Score180s)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -159,7 +159,18 @@ func GetShootoutStatisticsForPlayer(id int) (*models.StatisticsShootout, error)
 
 // GetShootoutHistoryForPlayer will return history of Shootout statistics for the given player
 func GetShootoutHistoryForPlayer(id int, limit int) ([]*models.Leg, error) {
-	legs, err := GetLegsOfType(models.SHOOTOUT, false)
+	return getShootoutHistoryForPlayer(id, limit, models.SHOOTOUT)
+}
+
+// GetCountUpHistoryForPlayer will return history of Count-Up statistics for the given player
+func GetCountUpHistoryForPlayer(id int, limit int) ([]*models.Leg, error) {
+	return getShootoutHistoryForPlayer(id, limit, models.COUNTUP)
+}
+
+// getShootoutHistoryForPlayer will return history of statistics for the given player in legs of the given type,
+// since Shootout and Count-Up share the same statistics
+func getShootoutHistoryForPlayer(id int, limit int, matchType int) ([]*models.Leg, error) {
+	legs, err := GetLegsOfType(matchType, false)
 	if err != nil {
 		return nil, err
 	}
@@ -184,9 +195,9 @@ func GetShootoutHistoryForPlayer(id int, limit int) ([]*models.Leg, error) {
 			LEFT JOIN matches m ON m.id = l.match_id
 		WHERE s.player_id = ?
 			AND l.is_finished = 1 AND m.is_abandoned = 0
-			AND m.match_type_id = 2
+			AND m.match_type_id = ?
 		ORDER BY l.id DESC
-		LIMIT ?`, id, limit)
+		LIMIT ?`, id, matchType, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -248,12 +259,17 @@ func CalculateShootoutStatistics(legID int) (map[int]*models.StatisticsShootout,
 	return statisticsMap, nil
 }
 
-// ReCalculateShootoutStatistics will recaulcate statistics for Shootout matches
+// ReCalculateShootoutStatistics will recaulcate statistics for Shootout and Count-Up matches
 func ReCalculateShootoutStatistics() (map[int]map[int]*models.StatisticsShootout, error) {
 	legs, err := GetLegsOfType(models.SHOOTOUT, true)
 	if err != nil {
 		return nil, err
 	}
+	countUpLegs, err := GetLegsOfType(models.COUNTUP, true)
+	if err != nil {
+		return nil, err
+	}
+	legs = append(legs, countUpLegs...)
 
 	s := make(map[int]map[int]*models.StatisticsShootout)
 	for _, leg := range legs {
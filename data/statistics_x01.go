@@ -2,6 +2,7 @@ package data
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
 
 	"github.com/guregu/null"
@@ -87,16 +88,18 @@ func GetX01StatisticsForLeg(id int) ([]*models.StatisticsX01, error) {
 			s.overall_accuracy,
 			s.darts_thrown,
 			s.checkout_attempts,
-			IFNULL(s.checkout_percentage, 0) AS 'checkout_percentage'
+			IFNULL(s.checkout_percentage, 0) AS 'checkout_percentage',
+			s.darts_to_get_in,
+			s.double_in_percentage
 		FROM statistics_x01 s
 			JOIN player p ON p.id = s.player_id
 			JOIN leg l ON l.id = s.leg_id
 			JOIN matches m ON m.id = l.match_id
-			JOIN player2leg p2l ON p2l.leg_id = l.id AND p2l.player_id = s.player_id
+			LEFT JOIN player2leg p2l ON p2l.leg_id = l.id AND p2l.player_id = s.player_id
 		WHERE l.id = ?
 			AND m.match_type_id IN (1,3)
 		GROUP BY p.id
-		ORDER BY p2l.order`, id)
+		ORDER BY p2l.order IS NULL, p2l.order`, id)
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +110,7 @@ func GetX01StatisticsForLeg(id int) ([]*models.StatisticsX01, error) {
 		s := new(models.StatisticsX01)
 		err := rows.Scan(&s.LegID, &s.PlayerID, &s.PPD, &s.FirstNinePPD, &s.ThreeDartAvg, &s.FirstNineThreeDartAvg, &s.Score60sPlus, &s.Score100sPlus,
 			&s.Score140sPlus, &s.Score180s, &s.Accuracy20, &s.Accuracy19, &s.AccuracyOverall, &s.DartsThrown,
-			&s.CheckoutAttempts, &s.CheckoutPercentage)
+			&s.CheckoutAttempts, &s.CheckoutPercentage, &s.DartsToGetIn, &s.DoubleInPercentage)
 		if err != nil {
 			return nil, err
 		}
@@ -138,11 +141,75 @@ func GetX01StatisticsForMatch(id int) ([]*models.StatisticsX01, error) {
 			JOIN player p ON p.id = s.player_id
 			JOIN leg l ON l.id = s.leg_id
 			JOIN matches m ON m.id = l.match_id
-			JOIN player2leg p2l ON p2l.leg_id = l.id AND p2l.player_id = s.player_id
+			LEFT JOIN player2leg p2l ON p2l.leg_id = l.id AND p2l.player_id = s.player_id
 		WHERE m.id = ?
 			AND m.match_type_id IN (1, 3)
 		GROUP BY p.id
-		ORDER BY p2l.order`, id)
+		ORDER BY p2l.order IS NULL, p2l.order`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]*models.StatisticsX01, 0)
+	for rows.Next() {
+		s := new(models.StatisticsX01)
+		err := rows.Scan(&s.PlayerID, &s.PPD, &s.FirstNinePPD, &s.ThreeDartAvg, &s.FirstNineThreeDartAvg, &s.Score60sPlus,
+			&s.Score100sPlus, &s.Score140sPlus, &s.Score180s, &s.Accuracy20, &s.Accuracy19, &s.AccuracyOverall, &s.CheckoutAttempts,
+			&s.CheckoutPercentage)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// GetX01StatisticsForSet will return statistics for all players in the given set of the given match
+func GetX01StatisticsForSet(matchID int, set int) ([]*models.StatisticsX01, error) {
+	match, err := GetMatch(matchID)
+	if err != nil {
+		return nil, err
+	}
+	if set < 1 || set > len(match.Sets) {
+		return nil, fmt.Errorf("match %d does not have set %d", matchID, set)
+	}
+	offset := 0
+	for _, s := range match.Sets[:set-1] {
+		offset += s.Legs
+	}
+	legIDs := make([]int, 0)
+	for _, leg := range match.Legs[offset : offset+match.Sets[set-1].Legs] {
+		legIDs = append(legIDs, leg.ID)
+	}
+
+	q, args, err := sqlx.In(`
+		SELECT
+			p.id AS 'player_id',
+			SUM(s.ppd_score) / SUM(s.darts_thrown) AS 'ppd',
+			SUM(s.first_nine_ppd) / COUNT(p.id) AS 'first_nine_ppd',
+			(SUM(s.ppd_score) / SUM(s.darts_thrown)) * 3 as 'three_dart_avg',
+			SUM(s.first_nine_ppd) / COUNT(p.id) * 3 as 'first_nine_three_dart_avg',
+			SUM(s.60s_plus) AS '60s_plus',
+			SUM(s.100s_plus) AS '100s_plus',
+			SUM(s.140s_plus) AS '140s_plus',
+			SUM(s.180s) AS '180s',
+			SUM(s.accuracy_20) / COUNT(s.accuracy_20) AS 'accuracy_20s',
+			SUM(s.accuracy_19) / COUNT(s.accuracy_19) AS 'accuracy_19s',
+			SUM(s.overall_accuracy) / COUNT(s.overall_accuracy) AS 'accuracy_overall',
+			SUM(s.checkout_attempts) AS 'checkout_attempts',
+			COUNT(s.checkout_percentage) / SUM(s.checkout_attempts) * 100 AS 'checkout_percentage'
+		FROM statistics_x01 s
+			JOIN player p ON p.id = s.player_id
+			JOIN leg l ON l.id = s.leg_id
+			LEFT JOIN player2leg p2l ON p2l.leg_id = l.id AND p2l.player_id = s.player_id
+		WHERE l.id IN (?)
+		GROUP BY p.id
+		ORDER BY p2l.order IS NULL, p2l.order`, legIDs)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := models.DB.Query(q, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -514,8 +581,9 @@ func GetX01HistoryForPlayer(id int, limit int, matchType int) ([]*models.Leg, er
 	return legs, nil
 }
 
-// CalculateX01Statistics will calculate x01 statistics for the given leg
-func CalculateX01Statistics(legID int, winnerID int, startingScore int) (map[int]*models.StatisticsX01, error) {
+// CalculateX01Statistics will calculate x01 statistics for the given leg, using the given outshot type for checkout attempts
+// and the given inshot type for darts needed to get in
+func CalculateX01Statistics(legID int, winnerID int, startingScore int, outshotType int, inshotType int) (map[int]*models.StatisticsX01, error) {
 	visits, err := GetLegVisits(legID)
 	if err != nil {
 		return nil, err
@@ -525,8 +593,15 @@ func CalculateX01Statistics(legID int, winnerID int, startingScore int) (map[int
 	if err != nil {
 		return nil, err
 	}
+	matchTeams, err := GetTeamsForLeg(legID)
+	if err != nil {
+		return nil, err
+	}
+	match := models.Match{Teams: matchTeams}
 	statisticsMap := make(map[int]*models.StatisticsX01)
 	playersMap := make(map[int]*models.Player2Leg)
+	teams := make(map[int]int)
+	isIn := make(map[int]bool)
 	for _, player := range players {
 		stats := new(models.StatisticsX01)
 		stats.AccuracyStatistics = new(models.AccuracyStatistics)
@@ -540,19 +615,35 @@ func CalculateX01Statistics(legID int, winnerID int, startingScore int) (map[int
 	}
 
 	for _, visit := range visits {
+		// Score is shared by all players in a team, but statistics are credited to the player throwing
 		player := playersMap[visit.PlayerID]
-		stats := statisticsMap[visit.PlayerID]
+		throwerID := match.GetThrowerID(*visit)
+		stats, ok := statisticsMap[throwerID]
+		if !ok {
+			stats = new(models.StatisticsX01)
+			stats.AccuracyStatistics = new(models.AccuracyStatistics)
+			statisticsMap[throwerID] = stats
+		}
+		if throwerID != visit.PlayerID {
+			teams[throwerID] = visit.PlayerID
+		}
 
-		currentScore := player.CurrentScore
-		if visit.FirstDart.IsCheckoutAttempt(currentScore, 1) {
+		// Darts thrown before the player is in do not score, so add them back to the score of the player
+		scoreBeforeIn := 0
+		if inshotType != models.OUTSHOTANY && !isIn[visit.PlayerID] {
+			scoreBeforeIn = visit.GetScoreBeforeIn(inshotType)
+			isIn[visit.PlayerID] = models.GetDartsToGetIn([]*models.Visit{visit}, visit.PlayerID, inshotType).Valid
+		}
+		currentScore := player.CurrentScore + scoreBeforeIn
+		if visit.FirstDart.IsCheckoutAttempt(currentScore, 1, outshotType) {
 			stats.CheckoutAttempts++
 		}
 		currentScore -= visit.FirstDart.GetScore()
-		if visit.SecondDart.IsCheckoutAttempt(currentScore, 2) {
+		if visit.SecondDart.IsCheckoutAttempt(currentScore, 2, outshotType) {
 			stats.CheckoutAttempts++
 		}
 		currentScore -= visit.SecondDart.GetScore()
-		if visit.ThirdDart.IsCheckoutAttempt(currentScore, 3) {
+		if visit.ThirdDart.IsCheckoutAttempt(currentScore, 3, outshotType) {
 			stats.CheckoutAttempts++
 		}
 		currentScore -= visit.ThirdDart.GetScore()
@@ -562,7 +653,7 @@ func CalculateX01Statistics(legID int, winnerID int, startingScore int) (map[int
 			continue
 		}
 
-		visitScore := visit.GetScore()
+		visitScore := visit.GetScore() - scoreBeforeIn
 		if stats.DartsThrown <= 9 {
 			stats.FirstNinePPDScore += visitScore
 		}
@@ -579,7 +670,7 @@ func CalculateX01Statistics(legID int, winnerID int, startingScore int) (map[int
 		}
 
 		// Get accuracy stats
-		accuracyScore := player.CurrentScore
+		accuracyScore := player.CurrentScore + scoreBeforeIn
 		if visit.FirstDart.Value.Valid {
 			stats.AccuracyStatistics.GetAccuracyStats(accuracyScore, visit.FirstDart)
 			accuracyScore -= visit.FirstDart.GetScore()
@@ -595,8 +686,12 @@ func CalculateX01Statistics(legID int, winnerID int, startingScore int) (map[int
 		player.CurrentScore = currentScore
 	}
 
+	checkoutPlayerID := winnerID
+	if len(visits) > 0 && visits[len(visits)-1].PlayerID == winnerID {
+		checkoutPlayerID = match.GetThrowerID(*visits[len(visits)-1])
+	}
 	for playerID, stats := range statisticsMap {
-		if playerID == winnerID {
+		if playerID == checkoutPlayerID {
 			stats.CheckoutPercentage = null.FloatFrom(100 / float64(stats.CheckoutAttempts))
 
 			// When checking out, it might be done in 1, 2 or 3 darts, so make
@@ -608,6 +703,19 @@ func CalculateX01Statistics(legID int, winnerID int, startingScore int) (map[int
 		}
 		stats.AccuracyStatistics.SetAccuracy()
 
+		if inshotType != models.OUTSHOTANY {
+			sideID := playerID
+			if teamID, ok := teams[playerID]; ok {
+				sideID = teamID
+			}
+			stats.DartsToGetIn = models.GetDartsToGetIn(visits, sideID, inshotType)
+			if stats.DartsToGetIn.Valid {
+				stats.DoubleInPercentage = null.FloatFrom(100 / float64(stats.DartsToGetIn.Int64))
+			} else {
+				stats.DoubleInPercentage = null.FloatFrom(0)
+			}
+		}
+
 		// Set PPD and First 9 PPD
 		stats.PPD = float32(stats.PPDScore) / float32(stats.DartsThrown)
 		stats.FirstNinePPD = float32(stats.FirstNinePPDScore) / float32(9)
@@ -909,7 +1017,11 @@ func RecalculateX01Statistics() (map[int]map[int]*models.StatisticsX01, error) {
 
 	m := make(map[int]map[int]*models.StatisticsX01)
 	for _, leg := range legs {
-		stats, err := CalculateX01Statistics(leg.ID, int(leg.WinnerPlayerID.Int64), leg.StartingScore)
+		leg.Parameters, err = getOptionalLegParameters(leg.ID)
+		if err != nil {
+			return nil, err
+		}
+		stats, err := CalculateX01Statistics(leg.ID, int(leg.WinnerPlayerID.Int64), leg.StartingScore, leg.GetOutshotTypeID(), leg.GetInshotTypeID())
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,59 @@
+package data
+
+import (
+	"database/sql"
+
+	"github.com/kcapp/api/models"
+)
+
+// GetTeams will return all teams for the given match, with players in throwing order
+func GetTeams(matchID int) ([]*models.Team, error) {
+	return getTeams("SELECT player_id, member_id FROM team2match WHERE match_id = ? ORDER BY player_id, `order`", matchID)
+}
+
+// GetTeamsForLeg will return all teams for the match of the given leg, with players in throwing order
+func GetTeamsForLeg(legID int) ([]*models.Team, error) {
+	return getTeams("SELECT t.player_id, t.member_id FROM team2match t JOIN leg l ON l.match_id = t.match_id WHERE l.id = ? ORDER BY t.player_id, t.`order`", legID)
+}
+
+// getTeams will return the teams returned by the given query, which must be ordered by team
+func getTeams(query string, args ...interface{}) ([]*models.Team, error) {
+	rows, err := models.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := make([]*models.Team, 0)
+	var team *models.Team
+	for rows.Next() {
+		var playerID, memberID int
+		err := rows.Scan(&playerID, &memberID)
+		if err != nil {
+			return nil, err
+		}
+		if team == nil || team.PlayerID != playerID {
+			team = &models.Team{PlayerID: playerID, Players: make([]int, 0)}
+			teams = append(teams, team)
+		}
+		team.Players = append(team.Players, memberID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+// insertTeams will write the given teams of the given match, which must be validated by Match.ValidateTeams
+func insertTeams(tx *sql.Tx, match models.Match, matchID int64) error {
+	for _, team := range match.Teams {
+		for idx, memberID := range team.Players {
+			_, err := tx.Exec("INSERT INTO team2match (match_id, player_id, member_id, `order`) VALUES (?, ?, ?, ?)",
+				matchID, team.PlayerID, memberID, idx+1)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -26,9 +26,9 @@ func AddVenue(venue models.Venue) error {
 		return err
 	}
 
-	_, err = tx.Exec(`INSERT INTO venue_configuration (venue_id, has_dual_monitor, has_led_lights, has_smartboard, smartboard_uuid, smartboard_button_number)
-		VALUES (?, ?, ?, ?, ?, ?)`, venueID, venue.Config.HasDualMonitor, venue.Config.HasLEDLights, venue.Config.HasSmartboard,
-		venue.Config.SmartboardUUID, venue.Config.SmartboardButtonNumber)
+	_, err = tx.Exec(`INSERT INTO venue_configuration (venue_id, has_dual_monitor, has_led_lights, has_smartboard, smartboard_uuid, smartboard_button_number,
+		led_controller_url) VALUES (?, ?, ?, ?, ?, ?, ?)`, venueID, venue.Config.HasDualMonitor, venue.Config.HasLEDLights, venue.Config.HasSmartboard,
+		venue.Config.SmartboardUUID, venue.Config.SmartboardButtonNumber, venue.Config.LEDControllerURL)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -51,9 +51,10 @@ func UpdateVenue(venueID int, venue models.Venue) error {
 		tx.Rollback()
 		return err
 	}
-	_, err = tx.Exec(`UPDATE venue_configuration SET has_dual_monitor = ?, has_led_lights = ?, has_smartboard = ?, smartboard_uuid = ?, smartboard_button_number = ? WHERE venue_id = ?`,
+	_, err = tx.Exec(`UPDATE venue_configuration SET has_dual_monitor = ?, has_led_lights = ?, has_smartboard = ?, smartboard_uuid = ?, smartboard_button_number = ?,
+		led_controller_url = ? WHERE venue_id = ?`,
 		venue.Config.HasDualMonitor, venue.Config.HasLEDLights, venue.Config.HasSmartboard,
-		venue.Config.SmartboardUUID, venue.Config.SmartboardButtonNumber, venueID)
+		venue.Config.SmartboardUUID, venue.Config.SmartboardButtonNumber, venue.Config.LEDControllerURL, venueID)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -85,14 +86,15 @@ func GetVenues() ([]*models.Venue, error) {
 		return nil, err
 	}
 
-	rows, err = models.DB.Query("SELECT venue_id, has_dual_monitor, has_led_lights, has_smartboard, smartboard_uuid, smartboard_button_number FROM venue_configuration")
+	rows, err = models.DB.Query("SELECT venue_id, has_dual_monitor, has_led_lights, has_smartboard, smartboard_uuid, smartboard_button_number, led_controller_url FROM venue_configuration")
 	if err != nil {
 		return nil, err
 	}
 	configs := make(map[int]*models.VenueConfig)
 	for rows.Next() {
 		config := new(models.VenueConfig)
-		err := rows.Scan(&config.VenueID, &config.HasDualMonitor, &config.HasLEDLights, &config.HasSmartboard, &config.SmartboardUUID, &config.SmartboardButtonNumber)
+		err := rows.Scan(&config.VenueID, &config.HasDualMonitor, &config.HasLEDLights, &config.HasSmartboard, &config.SmartboardUUID, &config.SmartboardButtonNumber,
+			&config.LEDControllerURL)
 		if err != nil {
 			return nil, err
 		}
@@ -123,8 +125,9 @@ func GetVenue(id int) (*models.Venue, error) {
 // GetVenueConfiguration will return the configuration for a venue with the given id
 func GetVenueConfiguration(id int) (*models.VenueConfig, error) {
 	config := new(models.VenueConfig)
-	err := models.DB.QueryRow("SELECT venue_id, has_dual_monitor, has_led_lights, has_smartboard, smartboard_uuid, smartboard_button_number FROM venue_configuration WHERE venue_id = ?",
-		id).Scan(&config.VenueID, &config.HasDualMonitor, &config.HasLEDLights, &config.HasSmartboard, &config.SmartboardUUID, &config.SmartboardButtonNumber)
+	err := models.DB.QueryRow("SELECT venue_id, has_dual_monitor, has_led_lights, has_smartboard, smartboard_uuid, smartboard_button_number, led_controller_url FROM venue_configuration WHERE venue_id = ?",
+		id).Scan(&config.VenueID, &config.HasDualMonitor, &config.HasLEDLights, &config.HasSmartboard, &config.SmartboardUUID, &config.SmartboardButtonNumber,
+		&config.LEDControllerURL)
 	if err != nil {
 		return nil, err
 	}
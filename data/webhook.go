@@ -0,0 +1,229 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kcapp/api/models"
+)
+
+// webhookClient is the client used to deliver events to webhooks
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// AddWebhook will add a new webhook, returning the id of the new webhook
+func AddWebhook(webhook models.Webhook) (int64, error) {
+	tx, err := models.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	res, err := tx.Exec("INSERT INTO webhook (url, secret, events, office_id, venue_id, is_active, created_at) VALUES (?, ?, ?, ?, ?, ?, NOW())",
+		webhook.URL, webhook.Secret, strings.Join(webhook.Events, ","), webhook.OfficeID, webhook.VenueID, webhook.IsActive)
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	webhookID, err := res.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	log.Printf("Created new webhook (%d) %s", webhookID, webhook.URL)
+	tx.Commit()
+	return webhookID, nil
+}
+
+// UpdateWebhook will update the given webhook, keeping the existing secret if no new secret is given
+func UpdateWebhook(webhookID int, webhook models.Webhook) error {
+	tx, err := models.DB.Begin()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE webhook SET url = ?, secret = IF(? = '', secret, ?), events = ?, office_id = ?, venue_id = ?, is_active = ? WHERE id = ?",
+		webhook.URL, webhook.Secret, webhook.Secret, strings.Join(webhook.Events, ","), webhook.OfficeID, webhook.VenueID, webhook.IsActive, webhookID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	log.Printf("Updated webhook (%d) %s", webhookID, webhook.URL)
+	tx.Commit()
+	return nil
+}
+
+// DeleteWebhook will delete the given webhook, and all its deliveries
+func DeleteWebhook(webhookID int) error {
+	tx, err := models.DB.Begin()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM webhook_delivery WHERE webhook_id = ?", webhookID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM webhook WHERE id = ?", webhookID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	log.Printf("Deleted webhook %d", webhookID)
+	tx.Commit()
+	return nil
+}
+
+// GetWebhooks will return all webhooks, without their secret
+func GetWebhooks() ([]*models.Webhook, error) {
+	webhooks, err := getWebhooks("SELECT id, url, secret, events, office_id, venue_id, is_active, created_at FROM webhook")
+	if err != nil {
+		return nil, err
+	}
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+	return webhooks, nil
+}
+
+// GetWebhook will return the webhook with the given id, without its secret
+func GetWebhook(id int) (*models.Webhook, error) {
+	webhooks, err := getWebhooks("SELECT id, url, secret, events, office_id, venue_id, is_active, created_at FROM webhook WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, fmt.Errorf("webhook %d not found", id)
+	}
+	webhooks[0].Secret = ""
+	return webhooks[0], nil
+}
+
+// GetWebhookDeliveries will return the latest deliveries for the given webhook
+func GetWebhookDeliveries(webhookID int, limit int) ([]*models.WebhookDelivery, error) {
+	rows, err := models.DB.Query(`
+		SELECT id, webhook_id, event_sequence, event_type, attempt, status_code, error, is_success, created_at
+		FROM webhook_delivery
+		WHERE webhook_id = ?
+		ORDER BY id DESC
+		LIMIT ?`, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	for rows.Next() {
+		d := new(models.WebhookDelivery)
+		err := rows.Scan(&d.ID, &d.WebhookID, &d.EventSequence, &d.EventType, &d.Attempt, &d.StatusCode, &d.Error, &d.IsSuccess, &d.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// getWebhooks will return the webhooks returned by the given query
+func getWebhooks(query string, args ...interface{}) ([]*models.Webhook, error) {
+	rows, err := models.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := make([]*models.Webhook, 0)
+	for rows.Next() {
+		webhook := new(models.Webhook)
+		var events string
+		err := rows.Scan(&webhook.ID, &webhook.URL, &webhook.Secret, &events, &webhook.OfficeID, &webhook.VenueID, &webhook.IsActive, &webhook.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		webhook.Events = make([]string, 0)
+		if events != "" {
+			webhook.Events = strings.Split(events, ",")
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// StartWebhookDispatcher will start sending published events to all matching webhooks
+func StartWebhookDispatcher() {
+	go consumeEvents(models.EventFilter{}, dispatchWebhooks)
+}
+
+// dispatchWebhooks will deliver the given event to all active webhooks subscribing to it
+func dispatchWebhooks(event *models.Event) {
+	webhooks, err := getWebhooks("SELECT id, url, secret, events, office_id, venue_id, is_active, created_at FROM webhook WHERE is_active = 1")
+	if err != nil {
+		log.Printf("Unable to get webhooks for event %d: %s", event.Sequence, err)
+		return
+	}
+	for _, webhook := range webhooks {
+		if webhook.Matches(event) {
+			go deliverWebhook(webhook, event)
+		}
+	}
+}
+
+// deliverWebhook will post the given event to the webhook, retrying with increasing delay until it succeeds. Each attempt is logged
+func deliverWebhook(webhook *models.Webhook, event *models.Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Unable to serialize event %d: %s", event.Sequence, err)
+		return
+	}
+	for attempt := 1; attempt <= models.WebhookMaxAttempts; attempt++ {
+		time.Sleep(models.GetWebhookRetryDelay(attempt))
+
+		delivery := postWebhook(webhook, event, payload)
+		delivery.Attempt = attempt
+		_, err = models.DB.Exec(`
+			INSERT INTO webhook_delivery (webhook_id, event_sequence, event_type, attempt, status_code, error, is_success, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, NOW())`, delivery.WebhookID, delivery.EventSequence, delivery.EventType, delivery.Attempt,
+			delivery.StatusCode, delivery.Error, delivery.IsSuccess)
+		if err != nil {
+			log.Printf("Unable to log delivery of event %d to webhook %d: %s", event.Sequence, webhook.ID, err)
+		}
+		if delivery.IsSuccess {
+			return
+		}
+	}
+	log.Printf("Giving up delivery of event %d to webhook %d after %d attempts", event.Sequence, webhook.ID, models.WebhookMaxAttempts)
+}
+
+// postWebhook will make a single attempt at posting the given payload to the webhook, signed with the secret of the webhook
+func postWebhook(webhook *models.Webhook, event *models.Event, payload []byte) *models.WebhookDelivery {
+	delivery := &models.WebhookDelivery{WebhookID: webhook.ID, EventSequence: event.Sequence, EventType: event.Type}
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		delivery.Error.SetValid(err.Error())
+		return delivery
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Kcapp-Event", event.Type)
+	req.Header.Set("X-Kcapp-Delivery", fmt.Sprintf("%d", event.Sequence))
+	req.Header.Set("X-Kcapp-Signature", webhook.Sign(payload))
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		delivery.Error.SetValid(err.Error())
+		return delivery
+	}
+	defer resp.Body.Close()
+	delivery.StatusCode.SetValid(int64(resp.StatusCode))
+	delivery.IsSuccess = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !delivery.IsSuccess {
+		delivery.Error.SetValid(resp.Status)
+	}
+	return delivery
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/kcapp/api/controllers"
+	"github.com/kcapp/api/data"
 	"github.com/kcapp/api/models"
 )
 
@@ -24,9 +25,15 @@ func main() {
 		panic(err)
 	}
 	models.InitDB(config.GetMysqlConnectionString())
+	data.StartWebhookDispatcher()
+	data.StartLightCues(config.LEDConfig)
+	if config.NotificationConfig.Slack.IsEnabled() {
+		data.StartNotifier(config.NotificationConfig.Slack, models.NewSlackTransport(config.NotificationConfig.Slack.WebhookURL))
+	}
 
 	router := mux.NewRouter()
 	router.HandleFunc("/health", controllers.Healthcheck).Methods("HEAD")
+	router.HandleFunc("/events", controllers.GetEvents).Methods("GET")
 
 	router.HandleFunc("/match", controllers.NewMatch).Methods("POST")
 	router.HandleFunc("/match/active", controllers.GetActiveMatches).Methods("GET")
@@ -38,7 +45,9 @@ func main() {
 	router.HandleFunc("/match/{id}/metadata", controllers.GetMatchMetadata).Methods("GET")
 	router.HandleFunc("/match/{id}/rematch", controllers.ReMatch).Methods("POST")
 	router.HandleFunc("/match/{id}/statistics", controllers.GetStatisticsForMatch).Methods("GET")
+	router.HandleFunc("/match/{id}/statistics/set/{set}", controllers.GetStatisticsForSet).Methods("GET")
 	router.HandleFunc("/match/{id}/legs", controllers.GetLegsForMatch).Methods("GET")
+	router.HandleFunc("/match/{id}/events", controllers.GetMatchEvents).Methods("GET")
 	router.HandleFunc("/match/{start}/{limit}", controllers.GetMatchesLimit).Methods("GET")
 
 	router.HandleFunc("/leg/active", controllers.GetActiveLegs).Methods("GET")
@@ -46,9 +55,17 @@ func main() {
 	router.HandleFunc("/leg/{id}", controllers.DeleteLeg).Methods("DELETE")
 	router.HandleFunc("/leg/{id}/statistics", controllers.GetStatisticsForLeg).Methods("GET")
 	router.HandleFunc("/leg/{id}/players", controllers.GetLegPlayers).Methods("GET")
+	router.HandleFunc("/leg/{id}/checkout", controllers.GetCheckoutSuggestion).Methods("GET")
+	router.HandleFunc("/leg/{id}/state", controllers.GetLegState).Methods("GET")
+	router.HandleFunc("/leg/{id}/events", controllers.GetLegEvents).Methods("GET")
 	router.HandleFunc("/leg/{id}/order", controllers.ChangePlayerOrder).Methods("PUT")
 	router.HandleFunc("/leg/{id}/warmup", controllers.StartWarmup).Methods("PUT")
 	router.HandleFunc("/leg/{id}/undo", controllers.UndoFinishLeg).Methods("PUT")
+	router.HandleFunc("/leg/{id}/dart", controllers.GetPendingVisit).Methods("GET")
+	router.HandleFunc("/leg/{id}/dart", controllers.AddDart).Methods("POST")
+	router.HandleFunc("/leg/{id}/dart", controllers.UndoDart).Methods("DELETE")
+	router.HandleFunc("/leg/{id}/dart/end", controllers.EndVisit).Methods("PUT")
+	router.HandleFunc("/leg/{id}/bot", controllers.AddBotVisit).Methods("POST")
 
 	router.HandleFunc("/visit", controllers.AddVisit).Methods("POST")
 	router.HandleFunc("/visit/{id}/modify", controllers.ModifyVisit).Methods("PUT")
@@ -89,6 +106,7 @@ func main() {
 	router.HandleFunc("/office", controllers.AddOffice).Methods("POST")
 	router.HandleFunc("/office/{id}", controllers.UpdateOffice).Methods("PUT")
 	router.HandleFunc("/office", controllers.GetOffices).Methods("GET")
+	router.HandleFunc("/office/{id}/events", controllers.GetOfficeEvents).Methods("GET")
 
 	router.HandleFunc("/venue", controllers.AddVenue).Methods("POST")
 	router.HandleFunc("/venue/{id}", controllers.UpdateVenue).Methods("PUT")
@@ -98,6 +116,21 @@ func main() {
 	router.HandleFunc("/venue/{id}/spectate", controllers.SpectateVenue).Methods("GET")
 	router.HandleFunc("/venue/{id}/players", controllers.GetRecentPlayers).Methods("GET")
 	router.HandleFunc("/venue/{id}/matches", controllers.GetActiveVenueMatches).Methods("GET")
+	router.HandleFunc("/venue/{id}/events", controllers.GetVenueEvents).Methods("GET")
+	router.HandleFunc("/venue/{id}/lights", controllers.GetVenueLights).Methods("GET")
+
+	router.HandleFunc("/smartboard/{uuid}/event", controllers.HandleSmartboardEvent).Methods("POST")
+
+	router.HandleFunc("/game/definition", controllers.AddGameDefinition).Methods("POST")
+	router.HandleFunc("/game/definition", controllers.GetGameDefinitions).Methods("GET")
+	router.HandleFunc("/game/definition/{id}", controllers.GetGameDefinition).Methods("GET")
+
+	router.HandleFunc("/webhook", controllers.AddWebhook).Methods("POST")
+	router.HandleFunc("/webhook", controllers.GetWebhooks).Methods("GET")
+	router.HandleFunc("/webhook/{id}", controllers.GetWebhook).Methods("GET")
+	router.HandleFunc("/webhook/{id}", controllers.UpdateWebhook).Methods("PUT")
+	router.HandleFunc("/webhook/{id}", controllers.DeleteWebhook).Methods("DELETE")
+	router.HandleFunc("/webhook/{id}/deliveries", controllers.GetWebhookDeliveries).Methods("GET")
 
 	router.HandleFunc("/tournament", controllers.NewTournament).Methods("POST")
 	router.HandleFunc("/tournament", controllers.GetTournaments).Methods("GET")
@@ -0,0 +1,255 @@
+package models
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/guregu/null"
+)
+
+const (
+	// MINBOTSKILL const representing the lowest skill level of a bot
+	MINBOTSKILL = 1
+	// MAXBOTSKILL const representing the highest skill level of a bot
+	MAXBOTSKILL = 10
+)
+
+// Radius (in mm) of the outer edge of each ring of the board
+const (
+	radiusBull        = 6.35
+	radiusOuterBull   = 15.9
+	radiusTripleInner = 99.0
+	radiusTripleOuter = 107.0
+	radiusDoubleInner = 162.0
+	radiusDoubleOuter = 170.0
+)
+
+// boardNumbers var holding the numbers of the board clockwise, starting from the top
+var boardNumbers = []int{20, 1, 18, 4, 13, 6, 10, 15, 2, 17, 3, 19, 7, 16, 8, 11, 14, 9, 12, 5}
+
+// BotThrower interface used for throwing darts aimed at a given target
+type BotThrower interface {
+	Throw(target *Dart) *Dart
+}
+
+// FixedSkillBot struct used for a bot throwing with a spread around the target given by the skill level
+type FixedSkillBot struct {
+	Skill int
+	Rand  *rand.Rand
+}
+
+// MimicBot struct used for a bot throwing according to the hit distribution of a real player
+type MimicBot struct {
+	Hits        map[int64]*Hits
+	DoubleRates map[int]float64
+	Rand        *rand.Rand
+}
+
+// Throw will throw a dart aimed at the given target, landing with a normal distributed spread around the target
+func (bot *FixedSkillBot) Throw(target *Dart) *Dart {
+	skill := bot.Skill
+	if skill < MINBOTSKILL {
+		skill = MINBOTSKILL
+	} else if skill > MAXBOTSKILL {
+		skill = MAXBOTSKILL
+	}
+	spread := 60.0 - 5.0*float64(skill-MINBOTSKILL)
+
+	x, y := getAimPoint(target)
+	return getDartAt(x+bot.Rand.NormFloat64()*spread, y+bot.Rand.NormFloat64()*spread)
+}
+
+// Throw will throw a dart aimed at the given target. Darts aimed at treble 20 are drawn from the darts thrown by the player,
+// darts aimed at a double hit with the double hit rate of the player, and other darts hit the aimed number with the accuracy of the player
+func (bot *MimicBot) Throw(target *Dart) *Dart {
+	if target.ValueRaw() == 20 && target.IsTriple() {
+		return bot.getRandomDart()
+	}
+
+	value := target.Value.Int64
+	if target.IsDouble() {
+		rate, ok := bot.DoubleRates[int(value)]
+		if !ok {
+			rate = 0.2
+		}
+		r := bot.Rand.Float64()
+		if r < rate {
+			return NewDart(target.Value, DOUBLE)
+		} else if r < rate+(1-rate)/2 && value != 25 {
+			return NewDart(target.Value, SINGLE)
+		}
+		return NewDart(null.IntFrom(0), SINGLE)
+	}
+
+	if bot.Rand.Float64() < bot.getAccuracy() {
+		return NewDart(target.Value, target.Multiplier)
+	}
+	if value == 25 {
+		return NewDart(null.IntFrom(int64(boardNumbers[bot.Rand.Intn(len(boardNumbers))])), SINGLE)
+	}
+	return NewDart(null.IntFrom(int64(getNeighbour(int(value), bot.Rand.Intn(2) == 0))), SINGLE)
+}
+
+// getRandomDart will return a dart drawn from all darts thrown by the player
+func (bot *MimicBot) getRandomDart() *Dart {
+	total := 0
+	for _, hit := range bot.Hits {
+		total += hit.Singles + hit.Doubles + hit.Triples
+	}
+	if total == 0 {
+		return NewDart(null.IntFrom(0), SINGLE)
+	}
+	r := bot.Rand.Intn(total)
+	for value := int64(0); value <= 25; value++ {
+		hit, ok := bot.Hits[value]
+		if !ok {
+			continue
+		}
+		if r < hit.Singles {
+			return NewDart(null.IntFrom(value), SINGLE)
+		}
+		r -= hit.Singles
+		if r < hit.Doubles {
+			return NewDart(null.IntFrom(value), DOUBLE)
+		}
+		r -= hit.Doubles
+		if r < hit.Triples {
+			return NewDart(null.IntFrom(value), TRIPLE)
+		}
+		r -= hit.Triples
+	}
+	return NewDart(null.IntFrom(0), SINGLE)
+}
+
+// getAccuracy will return the share of darts thrown by the player in the 20 segment, out of all darts thrown at 20, 1 and 5
+func (bot *MimicBot) getAccuracy() float64 {
+	count := func(value int64) int {
+		if hit, ok := bot.Hits[value]; ok {
+			return hit.Singles + hit.Doubles + hit.Triples
+		}
+		return 0
+	}
+	total := count(20) + count(1) + count(5)
+	if total == 0 {
+		return 0.5
+	}
+	return float64(count(20)) / float64(total)
+}
+
+// GenerateBotVisit will generate a visit for the given player, aiming each dart based on the score left in the given leg
+func GenerateBotVisit(bot BotThrower, leg *Leg, playerID int, score int) Visit {
+	outshotType := leg.GetOutshotTypeID()
+	inshotType := leg.GetInshotTypeID()
+	isIn := inshotType == OUTSHOTANY || GetDartsToGetIn(leg.Visits, playerID, inshotType).Valid
+
+	pending := NewPendingVisit(leg.ID, playerID)
+	for i := 0; i < 3; i++ {
+		var target *Dart
+		if isIn {
+			target = GetBotTarget(score, 3-i, outshotType)
+		} else {
+			target = NewDart(null.IntFrom(20), DOUBLE)
+		}
+		dart := bot.Throw(target)
+		pending.AddDart(*dart)
+
+		if !isIn {
+			if !dart.IsValidOutshot(inshotType) {
+				continue
+			}
+			isIn = true
+		}
+		if dart.IsBust(score, outshotType) {
+			break
+		}
+		score -= dart.GetScore()
+		if score == 0 {
+			break
+		}
+	}
+	return pending.GetVisit(false)
+}
+
+// GetBotTarget will return the target a bot should aim at, given the score left and the number of darts left in the visit
+func GetBotTarget(score int, dartsLeft int, outshotType int) *Dart {
+	routes := GetCheckoutRoutes(score, dartsLeft, outshotType, nil)
+	if len(routes) > 0 {
+		return routes[0].Darts[0]
+	}
+	minScoreLeft := 2
+	if outshotType == OUTSHOTANY {
+		minScoreLeft = 1
+	}
+	if score-60 >= minScoreLeft {
+		return NewDart(null.IntFrom(20), TRIPLE)
+	}
+	// Set up a finish by leaving an even score, where possible
+	for value := 20; value >= 1; value-- {
+		left := score - value
+		if left >= minScoreLeft && (outshotType == OUTSHOTANY || left%2 == 0) {
+			return NewDart(null.IntFrom(int64(value)), SINGLE)
+		}
+	}
+	return NewDart(null.IntFrom(1), SINGLE)
+}
+
+// getAimPoint will return the point (in mm from the center of the board) in the middle of the given target
+func getAimPoint(target *Dart) (float64, float64) {
+	if target.IsBull() {
+		if target.IsDouble() {
+			return 0, 0
+		}
+		return 0, (radiusBull + radiusOuterBull) / 2
+	}
+	radius := (radiusTripleOuter + radiusDoubleInner) / 2
+	if target.IsTriple() {
+		radius = (radiusTripleInner + radiusTripleOuter) / 2
+	} else if target.IsDouble() {
+		radius = (radiusDoubleInner + radiusDoubleOuter) / 2
+	}
+	angle := 0.0
+	for i, number := range boardNumbers {
+		if number == target.ValueRaw() {
+			angle = (90 - float64(i)*18) * math.Pi / 180
+		}
+	}
+	return radius * math.Cos(angle), radius * math.Sin(angle)
+}
+
+// getDartAt will return the dart hit at the given point (in mm from the center of the board)
+func getDartAt(x float64, y float64) *Dart {
+	radius := math.Hypot(x, y)
+	if radius <= radiusBull {
+		return NewDart(null.IntFrom(25), DOUBLE)
+	} else if radius <= radiusOuterBull {
+		return NewDart(null.IntFrom(25), SINGLE)
+	} else if radius > radiusDoubleOuter {
+		return NewDart(null.IntFrom(0), SINGLE)
+	}
+
+	degrees := 90 - math.Atan2(y, x)*180/math.Pi
+	idx := int(math.Floor((degrees+9)/18)) % len(boardNumbers)
+	if idx < 0 {
+		idx += len(boardNumbers)
+	}
+	value := null.IntFrom(int64(boardNumbers[idx]))
+	if radius > radiusTripleInner && radius <= radiusTripleOuter {
+		return NewDart(value, TRIPLE)
+	} else if radius > radiusDoubleInner {
+		return NewDart(value, DOUBLE)
+	}
+	return NewDart(value, SINGLE)
+}
+
+// getNeighbour will return the number next to the given number on the board, either clockwise or counter clockwise
+func getNeighbour(number int, clockwise bool) int {
+	for i, n := range boardNumbers {
+		if n == number {
+			if clockwise {
+				return boardNumbers[(i+1)%len(boardNumbers)]
+			}
+			return boardNumbers[(i+len(boardNumbers)-1)%len(boardNumbers)]
+		}
+	}
+	return number
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetDartAt will check that points on the board are resolved to the correct dart
+func TestGetDartAt(t *testing.T) {
+	assert.Equal(t, getDartAt(0, 0).GetString(), "2-25", "center should be bull")
+	assert.Equal(t, getDartAt(0, 10).GetString(), "1-25", "should be outer bull")
+	assert.Equal(t, getDartAt(0, 103).GetString(), "3-20", "should be treble 20")
+	assert.Equal(t, getDartAt(0, -166).GetString(), "2-3", "should be double 3")
+	assert.Equal(t, getDartAt(134, 0).GetString(), "1-6", "should be single 6")
+	assert.Equal(t, getDartAt(0, 200).IsMiss(), true, "should be outside the board")
+}
+
+// TestGetAimPoint will check that aiming at a target resolves to the same target
+func TestGetAimPoint(t *testing.T) {
+	for _, target := range checkoutTargets {
+		x, y := getAimPoint(target)
+		assert.Equal(t, getDartAt(x, y).GetString(), target.GetString(), "should hit the aimed target")
+	}
+}
+
+// TestGetBotTarget will check that bots aim for checkouts, and otherwise score or set up a finish
+func TestGetBotTarget(t *testing.T) {
+	assert.Equal(t, GetBotTarget(501, 3, OUTSHOTDOUBLE).GetString(), "3-20", "should aim for treble 20")
+	assert.Equal(t, GetBotTarget(40, 1, OUTSHOTDOUBLE).GetString(), "2-20", "should aim for double 20")
+	assert.Equal(t, GetBotTarget(61, 1, OUTSHOTDOUBLE).GetString(), "1-19", "should set up a double")
+	assert.Equal(t, GetBotTarget(62, 1, OUTSHOTANY).GetString(), "3-20", "should aim for treble 20")
+}
+
+// TestGenerateBotVisit will check that a bot visit stops on a checkout
+func TestGenerateBotVisit(t *testing.T) {
+	bot := &FixedSkillBot{Skill: MAXBOTSKILL, Rand: rand.New(rand.NewSource(1))}
+	leg := &Leg{ID: 1, Visits: []*Visit{}}
+	for i := 0; i < 20; i++ {
+		visit := GenerateBotVisit(bot, leg, 2, 40)
+		if visit.FirstDart.GetString() == "2-20" {
+			assert.Equal(t, visit.SecondDart.Value.Valid, false, "should not throw after checkout")
+		}
+		assert.Equal(t, visit.PlayerID, 2, "visit should be for player 2")
+	}
+}
+
+// TestMimicBotThrow will check that a mimic bot throws according to the hit distribution of the player
+func TestMimicBotThrow(t *testing.T) {
+	hits := map[int64]*Hits{20: {Triples: 1}}
+	bot := &MimicBot{Hits: hits, DoubleRates: map[int]float64{16: 1}, Rand: rand.New(rand.NewSource(1))}
+	assert.Equal(t, bot.Throw(NewDart(null.IntFrom(20), TRIPLE)).GetString(), "3-20", "should only throw treble 20")
+	assert.Equal(t, bot.Throw(NewDart(null.IntFrom(16), DOUBLE)).GetString(), "2-16", "should always hit double 16")
+}
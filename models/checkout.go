@@ -0,0 +1,210 @@
+package models
+
+import (
+	"sort"
+
+	"github.com/guregu/null"
+)
+
+// maxCheckoutRoutes is the number of routes suggested for each number of darts
+const maxCheckoutRoutes = 5
+
+var (
+	// BogeyNumbers var holding scores below 170 which cannot be checked out in three darts when finishing on a double
+	BogeyNumbers = []int{169, 168, 166, 165, 163, 162, 159}
+
+	// checkoutTargets var holding all targets which can be aimed at when checking out
+	checkoutTargets = getCheckoutTargets()
+)
+
+// CheckoutRoute struct used for storing a suggested way of checking out
+type CheckoutRoute struct {
+	Darts       []*Dart `json:"darts"`
+	Probability float64 `json:"probability"`
+}
+
+// DoubleAttempts struct used for counting the darts thrown at a double when checking out, and the darts hitting it
+type DoubleAttempts struct {
+	Attempts int
+	Hits     int
+}
+
+// CheckoutSuggestion struct used for storing suggested checkout routes for the current player of a leg
+type CheckoutSuggestion struct {
+	LegID       int              `json:"leg_id"`
+	PlayerID    int              `json:"player_id"`
+	Score       int              `json:"score"`
+	DartsLeft   int              `json:"darts_left"`
+	OutshotType int              `json:"outshot_type_id"`
+	IsBogey     bool             `json:"is_bogey"`
+	IsWeighted  bool             `json:"is_weighted"`
+	Routes      []*CheckoutRoute `json:"routes"`
+}
+
+// GetCheckoutRoutes will return routes for checking out the given score with at most the given number of darts, ranked by
+// the probability of hitting all darts. Probabilities of hitting each double can be given to weight routes for a specific player
+func GetCheckoutRoutes(score int, dartsLeft int, outshotType int, doubleRates map[int]float64) []*CheckoutRoute {
+	routes := make([]*CheckoutRoute, 0)
+	for darts := 1; darts <= dartsLeft; darts++ {
+		found := make([]*CheckoutRoute, 0)
+		for _, setup := range getSetupDarts(darts - 1) {
+			remaining := score
+			probability := 1.0
+			for _, dart := range setup {
+				remaining -= dart.GetScore()
+				probability *= getHitProbability(dart, doubleRates)
+			}
+			if remaining <= 0 {
+				continue
+			}
+			for _, finish := range checkoutTargets {
+				if finish.GetScore() == remaining && finish.IsValidOutshot(outshotType) {
+					route := &CheckoutRoute{Darts: append(append([]*Dart{}, setup...), finish)}
+					route.Probability = probability * getHitProbability(finish, doubleRates)
+					found = append(found, route)
+				}
+			}
+		}
+		sort.SliceStable(found, func(i, j int) bool {
+			return found[i].Probability > found[j].Probability
+		})
+		if len(found) > maxCheckoutRoutes {
+			found = found[:maxCheckoutRoutes]
+		}
+		routes = append(routes, found...)
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].Probability > routes[j].Probability
+	})
+	return routes
+}
+
+// IsBogeyNumber will check if the given score is below the highest checkout, but cannot be checked out in three darts with the given outshot type
+func IsBogeyNumber(score int, outshotType int) bool {
+	if outshotType == OUTSHOTDOUBLE {
+		return containsInt(BogeyNumbers, score)
+	}
+	return score > 1 && score < 180 && len(GetCheckoutRoutes(score, 3, outshotType, nil)) == 0
+}
+
+// GetDoubleHitRates will return the rate of darts hitting the double of each number, out of all darts hitting that number,
+// for numbers hit at least the given number of times
+func GetDoubleHitRates(hits map[int64]*Hits, minAttempts int) map[int]float64 {
+	rates := make(map[int]float64)
+	for value, hit := range hits {
+		attempts := hit.Singles + hit.Doubles
+		if value == 0 || attempts < minAttempts {
+			continue
+		}
+		rates[int(value)] = float64(hit.Doubles) / float64(attempts)
+	}
+	return rates
+}
+
+// CountDoubleAttempts will add the darts thrown by the given player at each double when checking out a double out leg with the
+// given starting score, and the darts hitting it. Visits must be all visits of the side of the player in the leg, in order
+func CountDoubleAttempts(visits []*Visit, startingScore int, playerID int, attempts map[int]*DoubleAttempts) {
+	score := startingScore
+	for _, visit := range visits {
+		remaining := score
+		for _, dart := range []*Dart{visit.FirstDart, visit.SecondDart, visit.ThirdDart} {
+			if dart == nil || !dart.Value.Valid {
+				break
+			}
+			if double := getCheckoutDouble(remaining); double > 0 && visit.GetThrowerID() == playerID {
+				if _, ok := attempts[double]; !ok {
+					attempts[double] = new(DoubleAttempts)
+				}
+				attempts[double].Attempts++
+				if dart.IsDouble() && int(dart.Value.Int64) == double {
+					attempts[double].Hits++
+				}
+			}
+			remaining -= dart.GetScore()
+		}
+		if !visit.IsBust {
+			score = remaining
+		}
+	}
+}
+
+// GetCheckoutDoubleRates will return the rate of darts hitting each double out of the darts thrown at it when checking out,
+// for doubles attempted at least the given number of times
+func GetCheckoutDoubleRates(attempts map[int]*DoubleAttempts, minAttempts int) map[int]float64 {
+	rates := make(map[int]float64)
+	for double, attempt := range attempts {
+		if attempt.Attempts < minAttempts {
+			continue
+		}
+		rates[double] = float64(attempt.Hits) / float64(attempt.Attempts)
+	}
+	return rates
+}
+
+// getCheckoutDouble will return the number of the double which checks out the given score, or 0 if it cannot be checked out with one double
+func getCheckoutDouble(score int) int {
+	if score == 50 {
+		return 25
+	}
+	if score >= 2 && score <= 40 && score%2 == 0 {
+		return score / 2
+	}
+	return 0
+}
+
+// getHitProbability will return the probability of hitting the given dart, using the given probabilities for doubles if available
+func getHitProbability(dart *Dart, doubleRates map[int]float64) float64 {
+	if dart.IsDouble() {
+		if rate, ok := doubleRates[int(dart.Value.Int64)]; ok {
+			return rate
+		}
+		if dart.IsBull() {
+			return 0.15
+		}
+		return 0.35
+	} else if dart.IsTriple() {
+		return 0.3
+	} else if dart.IsBull() {
+		return 0.4
+	}
+	return 0.9
+}
+
+// getSetupDarts will return all combinations of the given number of darts, ignoring the order they are thrown in
+func getSetupDarts(num int) [][]*Dart {
+	combinations := [][]*Dart{{}}
+	indexes := [][]int{{}}
+	for i := 0; i < num; i++ {
+		nextCombinations := make([][]*Dart, 0)
+		nextIndexes := make([][]int, 0)
+		for c, combination := range combinations {
+			start := 0
+			if len(indexes[c]) > 0 {
+				start = indexes[c][len(indexes[c])-1]
+			}
+			for idx := start; idx < len(checkoutTargets); idx++ {
+				nextCombinations = append(nextCombinations, append(append([]*Dart{}, combination...), checkoutTargets[idx]))
+				nextIndexes = append(nextIndexes, append(append([]int{}, indexes[c]...), idx))
+			}
+		}
+		combinations, indexes = nextCombinations, nextIndexes
+	}
+	return combinations
+}
+
+// getCheckoutTargets will return all targets on the board, with the highest scoring first
+func getCheckoutTargets() []*Dart {
+	targets := make([]*Dart, 0)
+	for value := 20; value >= 1; value-- {
+		targets = append(targets, NewDart(null.IntFrom(int64(value)), TRIPLE))
+	}
+	targets = append(targets, NewDart(null.IntFrom(25), DOUBLE))
+	for value := 20; value >= 1; value-- {
+		targets = append(targets, NewDart(null.IntFrom(int64(value)), DOUBLE))
+	}
+	targets = append(targets, NewDart(null.IntFrom(25), SINGLE))
+	for value := 20; value >= 1; value-- {
+		targets = append(targets, NewDart(null.IntFrom(int64(value)), SINGLE))
+	}
+	return targets
+}
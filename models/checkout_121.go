@@ -0,0 +1,72 @@
+package models
+
+const (
+	// Checkout121StartingTarget is the first target to check out in Checkout 121
+	Checkout121StartingTarget = 121
+	// Checkout121Darts is the number of darts each player gets to check out a target in Checkout 121
+	Checkout121Darts = 9
+)
+
+// Checkout121 struct used for tracking the progress of a player on the Checkout 121 ladder
+type Checkout121 struct {
+	Target           int `json:"target"`
+	Remaining        int `json:"remaining"`
+	TargetDarts      int `json:"target_darts"`
+	LastSuccess      int `json:"last_success"`
+	HighestTarget    int `json:"highest_target"`
+	TargetsAttempted int `json:"targets_attempted"`
+	Checkouts        int `json:"checkouts"`
+	CheckoutDarts    int `json:"checkout_darts"`
+	CheckoutAttempts int `json:"checkout_attempts"`
+}
+
+// NewCheckout121 will return a new ladder starting at Checkout121StartingTarget
+func NewCheckout121() *Checkout121 {
+	ladder := new(Checkout121)
+	ladder.LastSuccess = Checkout121StartingTarget
+	ladder.setTarget(Checkout121StartingTarget)
+	return ladder
+}
+
+// AddVisit will move the ladder according to the given visit, which must already have IsBust set for the remaining score.
+// Checking out moves the target up one, while failing to check out within Checkout121Darts moves the target back to the
+// last successful target. Returns true if the visit was a checkout
+func (ladder *Checkout121) AddVisit(visit *Visit, outshotType int) bool {
+	remaining := ladder.Remaining
+	for i, dart := range visit.GetDarts() {
+		if dart.IsCheckoutAttempt(remaining, i+1, outshotType) {
+			ladder.CheckoutAttempts++
+		}
+		remaining -= dart.GetScore()
+	}
+
+	if !visit.IsBust && visit.IsCheckout(ladder.Remaining, outshotType) {
+		ladder.TargetDarts += visit.GetDartsThrown()
+		ladder.TargetsAttempted++
+		ladder.Checkouts++
+		ladder.CheckoutDarts += ladder.TargetDarts
+		if ladder.Target > ladder.HighestTarget {
+			ladder.HighestTarget = ladder.Target
+		}
+		ladder.LastSuccess = ladder.Target
+		ladder.setTarget(ladder.Target + 1)
+		return true
+	}
+
+	ladder.TargetDarts += 3
+	if !visit.IsBust {
+		ladder.Remaining -= visit.GetScore()
+	}
+	if ladder.TargetDarts >= Checkout121Darts {
+		ladder.TargetsAttempted++
+		ladder.setTarget(ladder.LastSuccess)
+	}
+	return false
+}
+
+// setTarget will start a new attempt at the given target
+func (ladder *Checkout121) setTarget(target int) {
+	ladder.Target = target
+	ladder.Remaining = target
+	ladder.TargetDarts = 0
+}
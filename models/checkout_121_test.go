@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCheckout121 will check that a checkout moves the target up, and that failing to check out moves it back to the last success
+func TestCheckout121(t *testing.T) {
+	ladder := NewCheckout121()
+	assert.Equal(t, ladder.Target, 121, "should start at 121")
+
+	visit := &Visit{FirstDart: NewDart(null.IntFrom(20), TRIPLE), SecondDart: NewDart(null.IntFrom(20), TRIPLE), ThirdDart: NewDart(null.IntFrom(1), SINGLE)}
+	visit.SetIsBust(ladder.Remaining, OUTSHOTDOUBLE)
+	assert.Equal(t, ladder.AddVisit(visit, OUTSHOTDOUBLE), false, "bust should not be a checkout")
+	assert.Equal(t, ladder.Remaining, 121, "bust should reset remaining score")
+
+	visit = &Visit{FirstDart: NewDart(null.IntFrom(20), TRIPLE), SecondDart: NewDart(null.IntFrom(11), SINGLE), ThirdDart: NewDart(null.IntFrom(25), DOUBLE)}
+	visit.SetIsBust(ladder.Remaining, OUTSHOTDOUBLE)
+	assert.Equal(t, ladder.AddVisit(visit, OUTSHOTDOUBLE), true, "should check out 121")
+	assert.Equal(t, ladder.Target, 122, "should move up to 122")
+	assert.Equal(t, ladder.HighestTarget, 121, "highest target should be 121")
+	assert.Equal(t, ladder.CheckoutDarts, 6, "should use 6 darts to check out")
+	assert.Equal(t, ladder.CheckoutAttempts, 1, "bull on third dart should be a checkout attempt")
+
+	for i := 0; i < 3; i++ {
+		visit = &Visit{FirstDart: NewDart(null.IntFrom(0), SINGLE), SecondDart: NewDart(null.IntFrom(0), SINGLE), ThirdDart: NewDart(null.IntFrom(0), SINGLE)}
+		visit.SetIsBust(ladder.Remaining, OUTSHOTDOUBLE)
+		ladder.AddVisit(visit, OUTSHOTDOUBLE)
+	}
+	assert.Equal(t, ladder.Target, 121, "should move back to last success after 9 darts")
+	assert.Equal(t, ladder.TargetsAttempted, 2, "should have attempted two targets")
+	assert.Equal(t, ladder.Checkouts, 1, "should have one checkout")
+}
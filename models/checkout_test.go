@@ -0,0 +1,66 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetCheckoutRoutes will check that routes finish on a valid outshot and respect darts left
+func TestGetCheckoutRoutes(t *testing.T) {
+	routes := GetCheckoutRoutes(170, 3, OUTSHOTDOUBLE, nil)
+	assert.Equal(t, len(routes), 1, "170 should have a single route")
+	assert.Equal(t, routes[0].Darts[2].GetString(), "2-25", "170 should finish on bull")
+
+	assert.Equal(t, len(GetCheckoutRoutes(170, 2, OUTSHOTDOUBLE, nil)), 0, "170 should not be possible with two darts")
+	assert.Equal(t, GetCheckoutRoutes(40, 1, OUTSHOTDOUBLE, nil)[0].Darts[0].GetString(), "2-20", "40 should be D20")
+	assert.Equal(t, GetCheckoutRoutes(57, 1, OUTSHOTMASTER, nil)[0].Darts[0].GetString(), "3-19", "57 should be T19")
+	assert.Equal(t, len(GetCheckoutRoutes(57, 1, OUTSHOTDOUBLE, nil)), 0, "57 should not be possible with one dart")
+	assert.Equal(t, GetCheckoutRoutes(17, 1, OUTSHOTANY, nil)[0].Darts[0].GetString(), "1-17", "17 should be S17")
+
+	for _, route := range GetCheckoutRoutes(100, 3, OUTSHOTDOUBLE, nil) {
+		assert.Equal(t, route.Darts[len(route.Darts)-1].IsDouble(), true, "route should finish on a double")
+	}
+}
+
+// TestGetCheckoutRoutesWeighted will check that routes are ranked by the given double hit rates
+func TestGetCheckoutRoutesWeighted(t *testing.T) {
+	routes := GetCheckoutRoutes(32, 1, OUTSHOTDOUBLE, nil)
+	assert.Equal(t, routes[0].Darts[0].GetString(), "2-16", "32 should be D16")
+
+	routes = GetCheckoutRoutes(32, 2, OUTSHOTDOUBLE, map[int]float64{16: 0.05, 8: 0.5})
+	assert.Equal(t, routes[0].Darts[len(routes[0].Darts)-1].GetString(), "2-8", "should prefer D8 with better hit rate")
+}
+
+// TestIsBogeyNumber will check that bogey numbers are the scores which cannot be checked out in three darts
+func TestIsBogeyNumber(t *testing.T) {
+	for score := 2; score <= 170; score++ {
+		noRoute := len(GetCheckoutRoutes(score, 3, OUTSHOTDOUBLE, nil)) == 0
+		assert.Equal(t, IsBogeyNumber(score, OUTSHOTDOUBLE), noRoute, "bogey numbers should match routes for %d", score)
+	}
+	assert.Equal(t, IsBogeyNumber(179, OUTSHOTMASTER), true, "179 should be bogey for master out")
+	assert.Equal(t, IsBogeyNumber(170, OUTSHOTMASTER), false, "170 should not be bogey for master out")
+}
+
+// TestCountDoubleAttempts will check that only darts thrown by the player at a double which checks out the score are counted as attempts
+func TestCountDoubleAttempts(t *testing.T) {
+	miss := NewDart(null.IntFromPtr(nil), SINGLE)
+	visits := []*Visit{
+		{PlayerID: 1, FirstDart: NewDart(null.IntFrom(20), SINGLE), SecondDart: NewDart(null.IntFrom(20), SINGLE), ThirdDart: NewDart(null.IntFrom(20), SINGLE)},
+		{PlayerID: 1, FirstDart: NewDart(null.IntFrom(20), SINGLE), SecondDart: NewDart(null.IntFrom(4), SINGLE), ThirdDart: NewDart(null.IntFrom(8), SINGLE)},
+		{PlayerID: 1, ThrowerID: null.IntFrom(3), FirstDart: NewDart(null.IntFrom(10), DOUBLE), SecondDart: miss, ThirdDart: miss, IsBust: true},
+		{PlayerID: 1, FirstDart: NewDart(null.IntFrom(20), SINGLE), SecondDart: miss, ThirdDart: miss, IsBust: true},
+		{PlayerID: 1, FirstDart: NewDart(null.IntFrom(4), DOUBLE), SecondDart: miss, ThirdDart: miss},
+	}
+	attempts := make(map[int]*DoubleAttempts)
+	CountDoubleAttempts(visits, 100, 1, attempts)
+	assert.Equal(t, attempts[20], &DoubleAttempts{Attempts: 1}, "should have attempted D20 once")
+	assert.Equal(t, attempts[10], &DoubleAttempts{Attempts: 1}, "should have attempted D10 once")
+	assert.Equal(t, attempts[8], &DoubleAttempts{Attempts: 1}, "should have attempted D8 once")
+	assert.Equal(t, attempts[4], &DoubleAttempts{Attempts: 2, Hits: 1}, "should have checked out on D4 with the second attempt")
+	assert.Nil(t, attempts[30], "60 should not be a double attempt")
+
+	rates := GetCheckoutDoubleRates(attempts, 2)
+	assert.Equal(t, rates, map[int]float64{4: 0.5}, "only D4 should be attempted twice")
+}
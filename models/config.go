@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 
+	"github.com/guregu/null"
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -21,10 +22,31 @@ type APIConfig struct {
 	Port int `yaml:"port"`
 }
 
+// SlackConfig struct config, where notifications are sent to the channel of the office of the match, or the default channel
+type SlackConfig struct {
+	WebhookURL     string         `yaml:"webhook_url"`
+	Channel        string         `yaml:"channel"`
+	Username       string         `yaml:"username"`
+	OfficeChannels map[int]string `yaml:"office_channels"`
+	Notifications  []string       `yaml:"notifications"`
+}
+
+// NotificationConfig struct config
+type NotificationConfig struct {
+	Slack SlackConfig `yaml:"slack"`
+}
+
+// LEDConfig struct config, mapping each light cue to the command sent to LED controllers
+type LEDConfig struct {
+	Cues map[string]LightCommand `yaml:"cues"`
+}
+
 // Config type
 type Config struct {
-	DBConfig  DBConfig  `yaml:"db"`
-	APIConfig APIConfig `yaml:"api"`
+	DBConfig           DBConfig           `yaml:"db"`
+	APIConfig          APIConfig          `yaml:"api"`
+	NotificationConfig NotificationConfig `yaml:"notification"`
+	LEDConfig          LEDConfig          `yaml:"led"`
 }
 
 // GetConfig loads configuration from yaml file
@@ -47,6 +69,24 @@ func GetConfig(configFileParam string) (*Config, error) {
 	return config, nil
 }
 
+// IsEnabled will check if Slack notifications are configured
+func (config SlackConfig) IsEnabled() bool {
+	return config.WebhookURL != ""
+}
+
+// IsNotificationEnabled will check if the given type of notification should be sent, where all are sent if none are configured
+func (config SlackConfig) IsNotificationEnabled(notification string) bool {
+	return len(config.Notifications) == 0 || containsString(config.Notifications, notification)
+}
+
+// GetChannel will return the channel to notify for the given office, defaulting to the configured channel
+func (config SlackConfig) GetChannel(officeID null.Int) string {
+	if channel, ok := config.OfficeChannels[int(officeID.Int64)]; ok && officeID.Valid {
+		return channel
+	}
+	return config.Channel
+}
+
 // GetMysqlConnectionString returns mysql connection string
 func (config *Config) GetMysqlConnectionString() string {
 	return fmt.Sprintf(
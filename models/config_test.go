@@ -3,6 +3,7 @@ package models
 import (
 	"testing"
 
+	"github.com/guregu/null"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -16,6 +17,20 @@ func TestGetConfig(t *testing.T) {
 	assert.Equal(t, config.DBConfig.Username, "developer", "username should be developer")
 	assert.Equal(t, config.DBConfig.Password, "abcd1234", "password should be abcd1234")
 	assert.Equal(t, config.DBConfig.Schema, "kcapp", "schema should be kcapp")
+	assert.Equal(t, config.NotificationConfig.Slack.IsEnabled(), false, "slack should be disabled")
+	assert.Equal(t, config.NotificationConfig.Slack.Channel, "#darts", "channel should be #darts")
+	assert.Equal(t, config.LEDConfig.GetCommand(LIGHTCUEBUST, null.StringFromPtr(nil)).Color, "#ff0000", "bust should use the default red")
+}
+
+// TestSlackConfig will check which notifications are enabled, and which channel is used for each office
+func TestSlackConfig(t *testing.T) {
+	config := SlackConfig{Channel: "#darts", OfficeChannels: map[int]string{2: "#darts-oslo"}, Notifications: []string{NOTIFYMATCHRESULT}}
+	assert.Equal(t, config.GetChannel(null.IntFrom(2)), "#darts-oslo", "should use office channel")
+	assert.Equal(t, config.GetChannel(null.IntFrom(1)), "#darts", "should use default channel")
+	assert.Equal(t, config.GetChannel(null.IntFromPtr(nil)), "#darts", "should use default channel without office")
+	assert.True(t, config.IsNotificationEnabled(NOTIFYMATCHRESULT), "match result should be enabled")
+	assert.False(t, config.IsNotificationEnabled(NOTIFYSTANDINGS), "standings should be disabled")
+	assert.True(t, SlackConfig{}.IsNotificationEnabled(NOTIFYSTANDINGS), "all notifications should be enabled by default")
 }
 
 // TestGetMysqlConnectionString will check that we create a correct MySQL connection string
@@ -27,12 +27,14 @@ type Dart struct {
 	Multiplier int64    `json:"multiplier"`
 }
 
-// IsBust will check if the given dart is a bust
-func (dart *Dart) IsBust(currentScore int) bool {
+// IsBust will check if the given dart is a bust for the given outshot type
+func (dart *Dart) IsBust(currentScore int, outshotType int) bool {
 	scoreAfterThrow := currentScore - dart.GetScore()
-	if scoreAfterThrow == 0 && dart.IsDouble() {
+	if scoreAfterThrow == 0 && dart.IsValidOutshot(outshotType) {
 		return false
-	} else if scoreAfterThrow < 2 {
+	} else if scoreAfterThrow < 2 && outshotType != OUTSHOTANY {
+		return true
+	} else if scoreAfterThrow < 1 {
 		return true
 	}
 
@@ -98,6 +100,21 @@ func (dart Dart) Get420Score(target Target) int {
 	return 0
 }
 
+// GetGolfStrokes will get the number of strokes for the given dart on target, or 0 if the target was not hit. A double is
+// an eagle (1), a triple is a birdie (2) and a single is par (3), with GolfDartPenalty strokes added for each dart thrown before it
+func (dart Dart) GetGolfStrokes(target Target, dartsBefore int) int {
+	if target.Value != dart.ValueRaw() || !contains(target.multipliers, dart.Multiplier) {
+		return 0
+	}
+	strokes := 3
+	if dart.IsDouble() {
+		strokes = 1
+	} else if dart.IsTriple() {
+		strokes = 2
+	}
+	return strokes + dartsBefore*GolfDartPenalty
+}
+
 // GetJDCPracticeScore will get the JDC Practice score for the given dart on target
 func (dart Dart) GetJDCPracticeScore(target Target) int {
 	if target.Value == dart.ValueRaw() && contains(target.multipliers, dart.Multiplier) {
@@ -106,18 +123,67 @@ func (dart Dart) GetJDCPracticeScore(target Target) int {
 	return 0
 }
 
-// IsCheckoutAttempt checks if this dart was a checkout attempt
-func (dart Dart) IsCheckoutAttempt(currentScore int, num int) bool {
+// IsTargetHit will check if the given dart hit the target, where a target value of -1 is any number and a list of values
+// allows any of them to be hit
+func (dart Dart) IsTargetHit(target Target) bool {
+	if !contains(target.multipliers, dart.Multiplier) || dart.IsMiss() {
+		return false
+	}
+	if len(target.Values) > 0 {
+		return containsInt(target.Values, dart.ValueRaw())
+	}
+	return target.Value == -1 || target.Value == dart.ValueRaw()
+}
+
+// IsAroundTheClockHit will check if the given dart hit the given Around the Clock target with the required multiplier,
+// where target 21 is the bull. Bull counts for any multiplier unless doubles are required
+func (dart Dart) IsAroundTheClockHit(target int, multiplier int64) bool {
+	if target == 21 && dart.IsBull() {
+		return multiplier != DOUBLE || dart.IsDouble()
+	}
+	return dart.ValueRaw() == target && dart.Multiplier == multiplier
+}
+
+// GetCustomScore will get the score of the given dart on target in a custom game, using the fixed score of the target if set
+func (dart Dart) GetCustomScore(target Target) int {
+	if !dart.IsTargetHit(target) {
+		return 0
+	}
+	if target.score > 0 {
+		return target.score
+	}
+	return dart.GetScore()
+}
+
+// IsValidOutshot will check if this dart can be used to check out with the given outshot type
+func (dart Dart) IsValidOutshot(outshotType int) bool {
+	if outshotType == OUTSHOTANY {
+		return true
+	} else if outshotType == OUTSHOTMASTER {
+		return dart.IsDouble() || dart.IsTriple()
+	}
+	return dart.IsDouble()
+}
+
+// IsCheckoutAttempt checks if this dart was a checkout attempt for the given outshot type
+func (dart Dart) IsCheckoutAttempt(currentScore int, num int, outshotType int) bool {
 	if !dart.Value.Valid {
 		// Dart was not actually thrown, player busted/checked out already
 		return false
 	}
-	if currentScore-dart.GetScore() == 0 && dart.IsDouble() {
+	if currentScore-dart.GetScore() == 0 && dart.IsValidOutshot(outshotType) {
 		// Actual checkout
 		return true
-	} else if (num == 3 && currentScore == 50) || (currentScore <= 40 && currentScore%2 == 0 && currentScore > 1) {
+	} else if num == 3 && currentScore == 50 {
 		// Checkout attempt (bull only counts if it was on the third dart)
 		return true
+	} else if currentScore <= 40 && currentScore%2 == 0 && currentScore > 1 {
+		// Double is a valid outshot for all outshot types
+		return true
+	} else if outshotType == OUTSHOTMASTER && currentScore <= 60 && currentScore%3 == 0 {
+		return true
+	} else if outshotType == OUTSHOTANY && (currentScore <= 20 || (num == 3 && currentScore == 25) || (currentScore <= 60 && currentScore%3 == 0)) {
+		return true
 	}
 	return false
 }
@@ -199,7 +265,8 @@ func (dart *Dart) GetMarksHit(hits map[int]int64, open bool) int64 {
 	return marks
 }
 
-// CalculateCricketScore will calculate the score for each player for the given dart
+// CalculateCricketScore will calculate the score for each player for the given dart. Scoring is cut-throat, so points scored
+// on a closed number are given to each opponent who has not closed it
 func (dart *Dart) CalculateCricketScore(playerID int, scores map[int]*Player2Leg) int {
 	if !dart.Value.Valid {
 		return 0
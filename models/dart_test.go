@@ -11,17 +11,43 @@ import (
 // TestIsBust will check that the given dart is bust
 func TestIsBust(t *testing.T) {
 	dart := Dart{Value: null.IntFrom(20), Multiplier: 1}
-	assert.Equal(t, dart.IsBust(20), true, "should be bust")
+	assert.Equal(t, dart.IsBust(20, OUTSHOTDOUBLE), true, "should be bust")
 
 	dart = Dart{Value: null.IntFrom(10), Multiplier: 2}
-	assert.Equal(t, dart.IsBust(20), false, "should not be bust")
+	assert.Equal(t, dart.IsBust(20, OUTSHOTDOUBLE), false, "should not be bust")
 
 	dart = Dart{Value: null.NewInt(-1, false), Multiplier: 1}
-	assert.Equal(t, dart.IsBust(301), false, "should be bust")
+	assert.Equal(t, dart.IsBust(301, OUTSHOTDOUBLE), false, "should be bust")
 	assert.Equal(t, dart.Value.Valid, true, "should be valid")
 	assert.Equal(t, dart.Value.Int64, int64(0), "should be 0")
 }
 
+// TestIsBustOutshotType will check that the given dart is bust according to the outshot type
+func TestIsBustOutshotType(t *testing.T) {
+	dart := Dart{Value: null.IntFrom(20), Multiplier: 1}
+	assert.Equal(t, dart.IsBust(20, OUTSHOTANY), false, "should not be bust")
+	assert.Equal(t, dart.IsBust(20, OUTSHOTMASTER), true, "should be bust")
+	assert.Equal(t, dart.IsBust(21, OUTSHOTANY), false, "should not be bust")
+	assert.Equal(t, dart.IsBust(21, OUTSHOTMASTER), true, "should be bust")
+	assert.Equal(t, dart.IsBust(19, OUTSHOTANY), true, "should be bust")
+
+	dart = Dart{Value: null.IntFrom(20), Multiplier: 3}
+	assert.Equal(t, dart.IsBust(60, OUTSHOTMASTER), false, "should not be bust")
+	assert.Equal(t, dart.IsBust(60, OUTSHOTDOUBLE), true, "should be bust")
+}
+
+// TestIsValidOutshot will check that the given dart is a valid outshot for the outshot type
+func TestIsValidOutshot(t *testing.T) {
+	dart := Dart{Value: null.IntFrom(20), Multiplier: 1}
+	assert.Equal(t, dart.IsValidOutshot(OUTSHOTDOUBLE), false, "should be false")
+	assert.Equal(t, dart.IsValidOutshot(OUTSHOTMASTER), false, "should be false")
+	assert.Equal(t, dart.IsValidOutshot(OUTSHOTANY), true, "should be true")
+
+	dart = Dart{Value: null.IntFrom(20), Multiplier: 3}
+	assert.Equal(t, dart.IsValidOutshot(OUTSHOTDOUBLE), false, "should be false")
+	assert.Equal(t, dart.IsValidOutshot(OUTSHOTMASTER), true, "should be true")
+}
+
 // TestIsBustAbove will check that the given dart is bust above
 func TestIsBustAbove(t *testing.T) {
 	dart := Dart{Value: null.IntFrom(20), Multiplier: 1}
@@ -136,23 +162,33 @@ func TestGetJDCPracticeScore(t *testing.T) {
 func TestIsCheckoutAttempt(t *testing.T) {
 	// Invalid dart
 	dart := Dart{Value: null.NewInt(0, false), Multiplier: 2}
-	assert.Equal(t, dart.IsCheckoutAttempt(301, 1), false, "should be false")
+	assert.Equal(t, dart.IsCheckoutAttempt(301, 1, OUTSHOTDOUBLE), false, "should be false")
 
 	// Not checkout
 	dart = Dart{Value: null.IntFrom(20), Multiplier: 3}
-	assert.Equal(t, dart.IsCheckoutAttempt(301, 1), false, "should be false")
+	assert.Equal(t, dart.IsCheckoutAttempt(301, 1, OUTSHOTDOUBLE), false, "should be false")
 
 	// Successful checkout
 	dart = Dart{Value: null.IntFrom(20), Multiplier: 2}
-	assert.Equal(t, dart.IsCheckoutAttempt(40, 1), true, "should be true")
+	assert.Equal(t, dart.IsCheckoutAttempt(40, 1, OUTSHOTDOUBLE), true, "should be true")
 
 	// Checkout attempt
 	dart = Dart{Value: null.IntFrom(8), Multiplier: 1}
-	assert.Equal(t, dart.IsCheckoutAttempt(32, 1), true, "should be true")
+	assert.Equal(t, dart.IsCheckoutAttempt(32, 1, OUTSHOTDOUBLE), true, "should be true")
 
 	// Checkout attempt bull
 	dart = Dart{Value: null.IntFrom(18), Multiplier: 1}
-	assert.Equal(t, dart.IsCheckoutAttempt(50, 3), true, "should be true")
+	assert.Equal(t, dart.IsCheckoutAttempt(50, 3, OUTSHOTDOUBLE), true, "should be true")
+
+	// Checkout attempt on triple
+	dart = Dart{Value: null.IntFrom(19), Multiplier: 1}
+	assert.Equal(t, dart.IsCheckoutAttempt(57, 1, OUTSHOTDOUBLE), false, "should be false")
+	assert.Equal(t, dart.IsCheckoutAttempt(57, 1, OUTSHOTMASTER), true, "should be true")
+
+	// Successful checkout on single
+	dart = Dart{Value: null.IntFrom(17), Multiplier: 1}
+	assert.Equal(t, dart.IsCheckoutAttempt(17, 1, OUTSHOTDOUBLE), false, "should be false")
+	assert.Equal(t, dart.IsCheckoutAttempt(17, 1, OUTSHOTANY), true, "should be true")
 }
 
 // TestGetString will check that dart string is created correctly
@@ -338,3 +374,29 @@ func TestIsHit(t *testing.T) {
 	dart = &Dart{Value: null.IntFrom(20), Multiplier: 3}
 	assert.Equal(t, dart.IsHit([]int{5, 17, 3, 8}), false, "dart should be miss")
 }
+
+// TestCalculateCricketScoreCutThroat will check that points are given to opponents who have not closed the number
+func TestCalculateCricketScoreCutThroat(t *testing.T) {
+	scores := make(map[int]*Player2Leg)
+	for _, id := range []int{1, 2, 3} {
+		scores[id] = &Player2Leg{PlayerID: id, Hits: make(map[int]*Hits)}
+	}
+	scores[1].Hits[20] = &Hits{Total: 3}
+	scores[3].Hits[20] = &Hits{Total: 3}
+
+	dart := &Dart{Value: null.IntFrom(20), Multiplier: 2}
+	dart.CalculateCricketScore(1, scores)
+	assert.Equal(t, scores[1].CurrentScore, 0, "scorer should not get points")
+	assert.Equal(t, scores[2].CurrentScore, 40, "open opponent should get points")
+	assert.Equal(t, scores[3].CurrentScore, 0, "closed opponent should not get points")
+}
+
+// TestGetGolfStrokes will check the strokes for a dart hitting the target of the hole
+func TestGetGolfStrokes(t *testing.T) {
+	target := TargetsGolf[4]
+	assert.Equal(t, NewDart(null.IntFrom(5), DOUBLE).GetGolfStrokes(target, 0), 1, "double should be eagle")
+	assert.Equal(t, NewDart(null.IntFrom(5), TRIPLE).GetGolfStrokes(target, 0), 2, "triple should be birdie")
+	assert.Equal(t, NewDart(null.IntFrom(5), SINGLE).GetGolfStrokes(target, 0), 3, "single should be par")
+	assert.Equal(t, NewDart(null.IntFrom(5), SINGLE).GetGolfStrokes(target, 2), 9, "third dart should add two penalties")
+	assert.Equal(t, NewDart(null.IntFrom(20), DOUBLE).GetGolfStrokes(target, 0), 0, "miss should not count")
+}
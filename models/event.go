@@ -0,0 +1,226 @@
+package models
+
+import (
+	"sync"
+	"time"
+
+	"github.com/guregu/null"
+)
+
+const (
+	// EVENTVISITADDED is published when a visit is added to a leg
+	EVENTVISITADDED = "visit_added"
+	// EVENTVISITMODIFIED is published when a visit is modified
+	EVENTVISITMODIFIED = "visit_modified"
+	// EVENTVISITDELETED is published when a visit is deleted
+	EVENTVISITDELETED = "visit_deleted"
+	// EVENTLEGSTARTED is published when a new leg is started
+	EVENTLEGSTARTED = "leg_started"
+	// EVENTLEGFINISHED is published when a leg is finished
+	EVENTLEGFINISHED = "leg_finished"
+	// EVENTLEGFINISHUNDONE is published when a finished leg is reopened
+	EVENTLEGFINISHUNDONE = "leg_finish_undone"
+	// EVENTMATCHFINISHED is published when a match is finished
+	EVENTMATCHFINISHED = "match_finished"
+	// EVENTWARMUPSTARTED is published when warmup is started for a leg
+	EVENTWARMUPSTARTED = "warmup_started"
+	// EVENTPLAYERORDERCHANGED is published when the player order of a leg is changed
+	EVENTPLAYERORDERCHANGED = "player_order_changed"
+	// EVENTELOUPDATED is published when the Elo of the players in a match is updated
+	EVENTELOUPDATED = "elo_updated"
+	// EVENTONEEIGHTY is published when a visit scores 180
+	EVENTONEEIGHTY = "one_eighty"
+	// EVENTHIGHESTCHECKOUT is published when a player beats their highest X01 checkout
+	EVENTHIGHESTCHECKOUT = "highest_checkout"
+	// EVENTTOURNAMENTMATCHDECIDED is published when a tournament match is finished
+	EVENTTOURNAMENTMATCHDECIDED = "tournament_match_decided"
+	// EVENTLIGHTCUE is published when lights at a venue should change
+	EVENTLIGHTCUE = "light_cue"
+	// EVENTRESYNC is sent to a subscriber resuming from a sequence number no longer kept, so it can reload its state
+	EVENTRESYNC = "resync"
+
+	// eventHistorySize is the number of events kept to allow clients to resume
+	eventHistorySize = 1000
+	// eventBufferSize is the number of events buffered for each subscriber before the subscription is closed
+	eventBufferSize = 64
+)
+
+// EventTypes contains all types of events published
+var EventTypes = []string{EVENTVISITADDED, EVENTVISITMODIFIED, EVENTVISITDELETED, EVENTLEGSTARTED, EVENTLEGFINISHED, EVENTLEGFINISHUNDONE, EVENTMATCHFINISHED,
+	EVENTWARMUPSTARTED, EVENTPLAYERORDERCHANGED, EVENTELOUPDATED, EVENTONEEIGHTY, EVENTHIGHESTCHECKOUT, EVENTTOURNAMENTMATCHDECIDED, EVENTLIGHTCUE}
+
+// Events is the event bus used to publish events within the API
+var Events = NewEventBus(eventHistorySize)
+
+// Event struct used for storing a single event, scoped to the leg, match, venue and office it happened in
+type Event struct {
+	Sequence  uint64      `json:"sequence"`
+	Type      string      `json:"type"`
+	LegID     null.Int    `json:"leg_id"`
+	MatchID   null.Int    `json:"match_id"`
+	VenueID   null.Int    `json:"venue_id"`
+	OfficeID  null.Int    `json:"office_id"`
+	Data      interface{} `json:"data,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// LegFinishedEvent struct used for the data of leg finished events
+type LegFinishedEvent struct {
+	WinnerID        null.Int `json:"winner_id"`
+	MatchTypeID     int      `json:"match_type_id"`
+	IsMatchFinished bool     `json:"is_match_finished"`
+}
+
+// MatchFinishedEvent struct used for the data of match finished events
+type MatchFinishedEvent struct {
+	WinnerID null.Int `json:"winner_id"`
+}
+
+// HighestCheckoutEvent struct used for the data of highest checkout events
+type HighestCheckoutEvent struct {
+	PlayerID         int      `json:"player_id"`
+	Checkout         int      `json:"checkout"`
+	PreviousCheckout null.Int `json:"previous_checkout"`
+}
+
+// TournamentMatchEvent struct used for the data of tournament match decided events
+type TournamentMatchEvent struct {
+	TournamentID int      `json:"tournament_id"`
+	WinnerID     null.Int `json:"winner_id"`
+}
+
+// EventFilter struct used for selecting events, where only the fields which are set are checked
+type EventFilter struct {
+	LegID    null.Int
+	MatchID  null.Int
+	VenueID  null.Int
+	OfficeID null.Int
+	Types    []string
+}
+
+// Matches will check if the given event is within the scope of the filter
+func (filter EventFilter) Matches(event *Event) bool {
+	if filter.LegID.Valid && filter.LegID != event.LegID {
+		return false
+	}
+	if filter.MatchID.Valid && filter.MatchID != event.MatchID {
+		return false
+	}
+	if filter.VenueID.Valid && filter.VenueID != event.VenueID {
+		return false
+	}
+	if filter.OfficeID.Valid && filter.OfficeID != event.OfficeID {
+		return false
+	}
+	return len(filter.Types) == 0 || containsString(filter.Types, event.Type)
+}
+
+// EventSubscription struct used for receiving events matching a filter, where Sequence is the last sequence number published
+// before the subscription was made
+type EventSubscription struct {
+	Events   chan *Event
+	Sequence uint64
+	filter   EventFilter
+}
+
+// EventBus struct used to publish events to all subscribers, keeping the last events so clients can resume from a sequence number
+type EventBus struct {
+	lock        sync.Mutex
+	sequence    uint64
+	history     []*Event
+	historySize int
+	subscribers map[*EventSubscription]bool
+}
+
+// NewEventBus will return a new event bus keeping the given number of events
+func NewEventBus(historySize int) *EventBus {
+	return &EventBus{
+		history:     make([]*Event, 0, historySize),
+		historySize: historySize,
+		subscribers: make(map[*EventSubscription]bool),
+	}
+}
+
+// Publish will assign the next sequence number to the given event, and send it to all matching subscribers.
+// Subscribers not keeping up are closed, and have to resume from the last sequence number received
+func (bus *EventBus) Publish(event Event) *Event {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+
+	bus.sequence++
+	event.Sequence = bus.sequence
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	if len(bus.history) == bus.historySize {
+		bus.history = bus.history[1:]
+	}
+	bus.history = append(bus.history, &event)
+
+	for sub := range bus.subscribers {
+		if !sub.filter.Matches(&event) {
+			continue
+		}
+		select {
+		case sub.Events <- &event:
+		default:
+			delete(bus.subscribers, sub)
+			close(sub.Events)
+		}
+	}
+	return &event
+}
+
+// Subscribe will return a new subscription for events matching the given filter, together with all kept events after
+// the given sequence number, or no events if the sequence number is 0. See Resume
+func (bus *EventBus) Subscribe(filter EventFilter, since uint64) (*EventSubscription, []*Event) {
+	if since == 0 {
+		bus.lock.Lock()
+		defer bus.lock.Unlock()
+		return bus.subscribe(filter), make([]*Event, 0)
+	}
+	return bus.Resume(filter, since)
+}
+
+// Resume will return a new subscription for events matching the given filter, together with all kept events after the given
+// sequence number. If events after the sequence number are no longer kept, or the sequence number was never published
+// because the bus was restarted, a resync event is returned first
+func (bus *EventBus) Resume(filter EventFilter, since uint64) (*EventSubscription, []*Event) {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+
+	sub := bus.subscribe(filter)
+	missed := make([]*Event, 0)
+	if since == bus.sequence {
+		return sub, missed
+	}
+	if since > bus.sequence || len(bus.history) == 0 {
+		return sub, append(missed, &Event{Sequence: bus.sequence, Type: EVENTRESYNC, CreatedAt: time.Now()})
+	}
+	if bus.history[0].Sequence > since+1 {
+		missed = append(missed, &Event{Sequence: bus.history[0].Sequence - 1, Type: EVENTRESYNC, CreatedAt: time.Now()})
+	}
+	for _, event := range bus.history {
+		if event.Sequence > since && filter.Matches(event) {
+			missed = append(missed, event)
+		}
+	}
+	return sub, missed
+}
+
+// subscribe will add a new subscription for events matching the given filter. The lock of the bus must be held
+func (bus *EventBus) subscribe(filter EventFilter) *EventSubscription {
+	sub := &EventSubscription{Events: make(chan *Event, eventBufferSize), Sequence: bus.sequence, filter: filter}
+	bus.subscribers[sub] = true
+	return sub
+}
+
+// Unsubscribe will stop sending events to the given subscription, and close it
+func (bus *EventBus) Unsubscribe(sub *EventSubscription) {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+	if bus.subscribers[sub] {
+		delete(bus.subscribers, sub)
+		close(sub.Events)
+	}
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEventFilter will check that events are only matched within the scope of the filter
+func TestEventFilter(t *testing.T) {
+	event := &Event{Type: EVENTVISITADDED, LegID: null.IntFrom(1), MatchID: null.IntFrom(2), VenueID: null.IntFrom(3)}
+	assert.True(t, EventFilter{}.Matches(event), "empty filter should match all events")
+	assert.True(t, EventFilter{MatchID: null.IntFrom(2), VenueID: null.IntFrom(3)}.Matches(event), "should match match and venue")
+	assert.False(t, EventFilter{LegID: null.IntFrom(2)}.Matches(event), "should not match other leg")
+	assert.False(t, EventFilter{OfficeID: null.IntFrom(1)}.Matches(event), "should not match event without office")
+	assert.True(t, EventFilter{Types: []string{EVENTLEGFINISHED, EVENTVISITADDED}}.Matches(event), "should match type")
+	assert.False(t, EventFilter{Types: []string{EVENTLEGFINISHED}}.Matches(event), "should not match other type")
+}
+
+// TestEventBusPublish will check that published events are numbered and sent to matching subscribers only
+func TestEventBusPublish(t *testing.T) {
+	bus := NewEventBus(10)
+	sub, missed := bus.Subscribe(EventFilter{LegID: null.IntFrom(1)}, 0)
+	assert.Equal(t, len(missed), 0, "should not replay events for new subscribers")
+
+	bus.Publish(Event{Type: EVENTVISITADDED, LegID: null.IntFrom(2)})
+	published := bus.Publish(Event{Type: EVENTVISITADDED, LegID: null.IntFrom(1)})
+	assert.Equal(t, published.Sequence, uint64(2), "should assign next sequence")
+
+	event := <-sub.Events
+	assert.Equal(t, event.Sequence, uint64(2), "should only receive events for leg 1")
+
+	bus.Unsubscribe(sub)
+	_, ok := <-sub.Events
+	assert.False(t, ok, "subscription should be closed")
+}
+
+// TestEventBusResume will check that subscribers resuming get missed events, and a resync event if they are no longer kept
+func TestEventBusResume(t *testing.T) {
+	bus := NewEventBus(3)
+	for i := 0; i < 5; i++ {
+		bus.Publish(Event{Type: EVENTVISITADDED, LegID: null.IntFrom(1)})
+	}
+
+	_, missed := bus.Subscribe(EventFilter{}, 3)
+	assert.Equal(t, len(missed), 2, "should replay events after sequence 3")
+	assert.Equal(t, missed[0].Sequence, uint64(4), "should replay from sequence 4")
+
+	_, missed = bus.Subscribe(EventFilter{}, 1)
+	assert.Equal(t, missed[0].Type, EVENTRESYNC, "should resync when events are no longer kept")
+	assert.Equal(t, len(missed), 4, "should replay all kept events after resync")
+
+	_, missed = bus.Subscribe(EventFilter{}, 10)
+	assert.Equal(t, len(missed), 1, "should only resync when resuming after a restart")
+	assert.Equal(t, missed[0].Type, EVENTRESYNC, "should resync when sequence was never published")
+	assert.Equal(t, missed[0].Sequence, uint64(5), "should resync to last sequence")
+}
+
+// TestEventBusResumeFromStart will check that a subscription made before any event was published can resume from the start
+func TestEventBusResumeFromStart(t *testing.T) {
+	bus := NewEventBus(10)
+	sub, _ := bus.Subscribe(EventFilter{}, 0)
+	assert.Equal(t, sub.Sequence, uint64(0), "should keep sequence at time of subscription")
+	bus.Unsubscribe(sub)
+
+	bus.Publish(Event{Type: EVENTVISITADDED})
+	bus.Publish(Event{Type: EVENTVISITADDED})
+	sub, missed := bus.Resume(EventFilter{}, sub.Sequence)
+	assert.Equal(t, len(missed), 2, "should replay all events after subscription")
+	assert.Equal(t, sub.Sequence, uint64(2), "should keep sequence at time of subscription")
+}
+
+// TestEventBusSlowSubscriber will check that subscribers not keeping up are closed
+func TestEventBusSlowSubscriber(t *testing.T) {
+	bus := NewEventBus(10)
+	sub, _ := bus.Subscribe(EventFilter{}, 0)
+	for i := 0; i <= eventBufferSize; i++ {
+		bus.Publish(Event{Type: EVENTVISITADDED})
+	}
+	count := 0
+	for range sub.Events {
+		count++
+	}
+	assert.Equal(t, count, eventBufferSize, "should close subscription when buffer is full")
+	bus.Unsubscribe(sub)
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/guregu/null"
+)
+
+const (
+	// SCORESUM scoring rule, adding the score of each dart hitting the target
+	SCORESUM = "sum"
+	// SCOREHITS scoring rule, adding one point for each dart hitting the target
+	SCOREHITS = "hits"
+	// SCOREHALVE scoring rule, adding the score of each dart hitting the target, and halving the score if no darts hit
+	SCOREHALVE = "halve"
+
+	// WINHIGH win condition, where the player with the highest score wins
+	WINHIGH = "high"
+	// WINLOW win condition, where the player with the lowest score wins
+	WINLOW = "low"
+)
+
+// GameDefinition struct used for storing custom round-based games, with one target for each round. Definitions are not changed
+// once created, since legs are scored from the definition they were played with
+type GameDefinition struct {
+	ID           int         `json:"id"`
+	Name         string      `json:"name"`
+	Description  null.String `json:"description"`
+	OfficeID     null.Int    `json:"office_id"`
+	ScoringRule  string      `json:"scoring_rule"`
+	WinCondition string      `json:"win_condition"`
+	Targets      []Target    `json:"targets"`
+	CreatedAt    string      `json:"created_at,omitempty"`
+}
+
+// ValidateInput will verify that the game definition can be played
+func (def GameDefinition) ValidateInput() error {
+	if def.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+	if def.ScoringRule != SCORESUM && def.ScoringRule != SCOREHITS && def.ScoringRule != SCOREHALVE {
+		return fmt.Errorf("scoring rule has to be one of '%s', '%s', '%s'", SCORESUM, SCOREHITS, SCOREHALVE)
+	}
+	if def.WinCondition != WINHIGH && def.WinCondition != WINLOW {
+		return fmt.Errorf("win condition has to be one of '%s', '%s'", WINHIGH, WINLOW)
+	}
+	if len(def.Targets) == 0 {
+		return errors.New("at least one target is required")
+	}
+	for i, target := range def.Targets {
+		if err := target.ValidateInput(); err != nil {
+			return fmt.Errorf("invalid target for round %d: %s", i+1, err)
+		}
+	}
+	return nil
+}
+
+// GetHits will return the number of darts in the given visit hitting the target of the given round (starting at 0)
+func (def GameDefinition) GetHits(visit *Visit, round int) int {
+	target := def.Targets[round]
+	hits := 0
+	for _, dart := range []*Dart{visit.FirstDart, visit.SecondDart, visit.ThirdDart} {
+		if dart.IsTargetHit(target) {
+			hits++
+		}
+	}
+	return hits
+}
+
+// CalculateScore will return the score of a player after the given visit in the given round (starting at 0), according to the scoring rule
+func (def GameDefinition) CalculateScore(visit *Visit, round int, currentScore int) int {
+	if round >= len(def.Targets) {
+		return currentScore
+	}
+	if def.ScoringRule == SCOREHITS {
+		return currentScore + def.GetHits(visit, round)
+	}
+
+	target := def.Targets[round]
+	score := 0
+	for _, dart := range []*Dart{visit.FirstDart, visit.SecondDart, visit.ThirdDart} {
+		score += dart.GetCustomScore(target)
+	}
+	if def.ScoringRule == SCOREHALVE && score == 0 {
+		return currentScore / 2
+	}
+	return currentScore + score
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGameDefinitionCalculateScore will check that each scoring rule gives the correct score for a visit
+func TestGameDefinitionCalculateScore(t *testing.T) {
+	def := GameDefinition{ScoringRule: SCORESUM, Targets: []Target{
+		NewTarget(20, null.IntFromPtr(nil)),
+		NewCustomTarget(0, []int{1, 2, 3}, []int64{DOUBLE}, 50)}}
+
+	visit := &Visit{FirstDart: NewDart(null.IntFrom(20), TRIPLE), SecondDart: NewDart(null.IntFrom(5), SINGLE), ThirdDart: NewDart(null.IntFrom(20), SINGLE)}
+	assert.Equal(t, def.CalculateScore(visit, 0, 10), 90, "should add score of darts hitting 20")
+	assert.Equal(t, def.GetHits(visit, 0), 2, "should hit 20 twice")
+
+	visit = &Visit{FirstDart: NewDart(null.IntFrom(2), DOUBLE), SecondDart: NewDart(null.IntFrom(3), SINGLE), ThirdDart: NewDart(null.IntFrom(4), DOUBLE)}
+	assert.Equal(t, def.CalculateScore(visit, 1, 0), 50, "should use fixed score of target")
+
+	def.ScoringRule = SCOREHITS
+	assert.Equal(t, def.CalculateScore(visit, 1, 3), 4, "should add one point for each hit")
+
+	def.ScoringRule = SCOREHALVE
+	visit = &Visit{FirstDart: NewDart(null.IntFrom(1), SINGLE), SecondDart: NewDart(null.IntFrom(0), SINGLE), ThirdDart: NewDart(null.IntFrom(19), TRIPLE)}
+	assert.Equal(t, def.CalculateScore(visit, 0, 41), 20, "should halve score when target is missed")
+}
+
+// TestHalveItDefinition will check that Halve-It is scored as a game definition, where any number can be hit for a value of -1
+func TestHalveItDefinition(t *testing.T) {
+	params := &LegParameters{Targets: []Target{NewTarget(-1, null.IntFrom(DOUBLE)), NewTarget(20, null.IntFromPtr(nil)), NewTarget(-1, null.IntFrom(TRIPLE))}}
+	def := params.GetHalveItDefinition()
+	assert.Equal(t, def.ScoringRule, SCOREHALVE, "should halve on miss")
+	assert.Equal(t, def.WinCondition, WINHIGH, "highest score should win")
+
+	visit := &Visit{FirstDart: NewDart(null.IntFrom(20), DOUBLE), SecondDart: NewDart(null.IntFrom(5), DOUBLE), ThirdDart: NewDart(null.IntFrom(20), SINGLE)}
+	assert.Equal(t, def.CalculateScore(visit, 0, 0), 50, "should score all doubles")
+	assert.Equal(t, def.CalculateScore(visit, 1, 0), 60, "should score all 20s")
+	assert.Equal(t, def.CalculateScore(visit, 2, 40), 20, "should halve score without triples")
+
+	var defaults *LegParameters
+	assert.Equal(t, len(defaults.GetHalveItDefinition().Targets), len(TargetsHalveIt), "should use default targets")
+}
+
+// TestGameDefinitionValidateInput will check that invalid game definitions are rejected
+func TestGameDefinitionValidateInput(t *testing.T) {
+	def := GameDefinition{Name: "Test", ScoringRule: SCORESUM, WinCondition: WINHIGH, Targets: []Target{NewTarget(25, null.IntFrom(DOUBLE))}}
+	assert.Nil(t, def.ValidateInput())
+
+	def.Targets = []Target{NewTarget(21, null.IntFromPtr(nil))}
+	assert.NotNil(t, def.ValidateInput(), "should not allow target above 20")
+
+	def.Targets = nil
+	assert.NotNil(t, def.ValidateInput(), "should require targets")
+
+	def.Targets = []Target{NewTarget(20, null.IntFromPtr(nil))}
+	def.WinCondition = "none"
+	assert.NotNil(t, def.ValidateInput(), "should require a known win condition")
+}
+
+// TestCustomTargetJSON will check that values, multipliers and score of a target can be given as JSON
+func TestCustomTargetJSON(t *testing.T) {
+	var target Target
+	err := json.Unmarshal([]byte(`{"values": [1, 2, 3], "multipliers": [2, 3], "score": 25}`), &target)
+	assert.Nil(t, err)
+	assert.Equal(t, target.Values, []int{1, 2, 3}, "values should be read")
+	assert.Equal(t, target.GetMultipliers(), []int64{2, 3}, "multipliers should be read")
+	assert.Equal(t, target.GetScore(), 25, "score should be read")
+
+	b, err := json.Marshal(target)
+	assert.Nil(t, err)
+	assert.Equal(t, string(b), `{"value":0,"values":[1,2,3],"multiplier":null,"multipliers":[2,3],"score":25}`, "target should be marshalled")
+}
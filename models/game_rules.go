@@ -0,0 +1,77 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/guregu/null"
+)
+
+// ErrInvalidInput is returned when a request cannot be handled with the given input, such as a match which cannot be played with the
+// given players or leg parameters, or a dart thrown in a finished leg
+var ErrInvalidInput = errors.New("invalid input")
+
+// GameRules defines the rules for a given match type, so that adding a match type only requires registering an implementation
+type GameRules interface {
+	// HandleVisit will check if the given visit is a bust, and invalidate any darts which were not thrown
+	HandleVisit(leg *Leg, players map[int]*Player2Leg, visit *Visit)
+	// IsLegFinished will check if the given visit finishes the leg
+	IsLegFinished(leg *Leg, players map[int]*Player2Leg, visit *Visit) bool
+	// GetWinner will return the winner of the leg finished by the given visit, or null if the leg is a draw
+	GetWinner(leg *Leg, players map[int]*Player2Leg, visit Visit) null.Int
+	// IsPlayerOut will check if the given player is out of the leg, and should be skipped when finding the next player
+	IsPlayerOut(player *Player2Leg, visit Visit) bool
+
+	// InitializeScores will set the score of each player before the first visit of a leg with the given parameters
+	InitializeScores(params *LegParameters, players map[int]*Player2Leg)
+	// CalculateScore will add the given visit to the score of the player throwing it, and return the score of the visit.
+	// The given visits are the visits thrown before it
+	CalculateScore(params *LegParameters, players map[int]*Player2Leg, visits []*Visit, visit *Visit) int
+
+	// ValidateLegParameters will check that the given new leg can be played with its players and parameters
+	ValidateLegParameters(leg *Leg) error
+	// InsertLegParameters will write the parameters of the given new leg
+	InsertLegParameters(tx *sql.Tx, leg *Leg) error
+	// GetLegParameters will return the parameters of the given leg, or nil if the leg has none
+	GetLegParameters(legID int) (*LegParameters, error)
+
+	// InsertStatistics will calculate and write statistics for all players in the given leg
+	InsertStatistics(tx *sql.Tx, leg *Leg, visit Visit) error
+	// DeleteStatistics will delete the statistics written when the given leg was finished
+	DeleteStatistics(tx *sql.Tx, legID int) error
+	// GetStatistics will return statistics for all players between the given dates
+	GetStatistics(from string, to string) (interface{}, error)
+	// GetStatisticsForLeg will return statistics for all players in the given leg
+	GetStatisticsForLeg(legID int) (interface{}, error)
+	// GetStatisticsForMatch will return statistics for all players in the given match
+	GetStatisticsForMatch(matchID int) (interface{}, error)
+	// GetStatisticsForPlayer will return statistics for the given player
+	GetStatisticsForPlayer(playerID int) (interface{}, error)
+	// GetHistoryForPlayer will return the last legs played by the given player
+	GetHistoryForPlayer(playerID int, limit int) (interface{}, error)
+}
+
+var (
+	gameRules     = make(map[int]GameRules)
+	gameRulesLock sync.RWMutex
+)
+
+// RegisterGameRules will register the rules used for the given match type
+func RegisterGameRules(matchType int, rules GameRules) {
+	gameRulesLock.Lock()
+	defer gameRulesLock.Unlock()
+	gameRules[matchType] = rules
+}
+
+// GetGameRules will return the rules registered for the given match type
+func GetGameRules(matchType int) (GameRules, error) {
+	gameRulesLock.RLock()
+	defer gameRulesLock.RUnlock()
+	rules, ok := gameRules[matchType]
+	if !ok {
+		return nil, fmt.Errorf("no rules registered for match type %d", matchType)
+	}
+	return rules, nil
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRules only implements the methods needed by the tests, calling any other method will panic
+type testRules struct {
+	GameRules
+}
+
+func (r *testRules) HandleVisit(leg *Leg, players map[int]*Player2Leg, visit *Visit) {}
+func (r *testRules) IsLegFinished(leg *Leg, players map[int]*Player2Leg, visit *Visit) bool {
+	return true
+}
+func (r *testRules) GetWinner(leg *Leg, players map[int]*Player2Leg, visit Visit) null.Int {
+	return null.IntFrom(int64(visit.PlayerID))
+}
+func (r *testRules) InsertStatistics(tx *sql.Tx, leg *Leg, visit Visit) error { return nil }
+
+// TestGetGameRules will check that registered rules are returned for the given match type
+func TestGetGameRules(t *testing.T) {
+	_, err := GetGameRules(-1)
+	assert.NotNil(t, err, "should return error for unknown match type")
+
+	rules := new(testRules)
+	RegisterGameRules(-1, rules)
+	registered, err := GetGameRules(-1)
+	assert.Nil(t, err, "err should be nil")
+	assert.Equal(t, registered, rules, "should return registered rules")
+}
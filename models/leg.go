@@ -2,6 +2,8 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
 	"math/rand"
 	"time"
@@ -34,11 +36,178 @@ type Leg struct {
 
 // LegParameters struct used for storing leg parameters
 type LegParameters struct {
-	LegID         int          `json:"leg_id,omitempty"`
-	OutshotType   *OutshotType `json:"outshot_type,omitempty"`
-	Numbers       []int        `json:"numbers"`
-	Hits          map[int]int  `json:"hits"`
-	StartingLives null.Int     `json:"starting_lives,omitempty"`
+	LegID            int             `json:"leg_id,omitempty"`
+	OutshotType      *OutshotType    `json:"outshot_type,omitempty"`
+	InshotType       *OutshotType    `json:"inshot_type,omitempty"`
+	Numbers          []int           `json:"numbers"`
+	Hits             map[int]int     `json:"hits"`
+	StartingLives    null.Int        `json:"starting_lives,omitempty"`
+	PlayerNumbers    map[int]int     `json:"player_numbers,omitempty"`
+	Killers          map[int]bool    `json:"killers,omitempty"`
+	Holes            null.Int        `json:"holes,omitempty"`
+	Targets          []Target        `json:"targets,omitempty"`
+	Rounds           null.Int        `json:"rounds,omitempty"`
+	Darts            null.Int        `json:"darts,omitempty"`
+	TargetMultiplier null.Int        `json:"target_multiplier,omitempty"`
+	StartingScore    null.Int        `json:"starting_score,omitempty"`
+	GameDefinitionID null.Int        `json:"game_definition_id,omitempty"`
+	GameDefinition   *GameDefinition `json:"game_definition,omitempty"`
+}
+
+// GetHalveItTargets will return the target for each round of Halve-It, defaulting to TargetsHalveIt if not set
+func (params *LegParameters) GetHalveItTargets() []Target {
+	if params != nil && len(params.Targets) > 0 {
+		return params.Targets
+	}
+	return TargetsHalveIt
+}
+
+// GetHalveItDefinition will return Halve-It as a game definition, where the score of the targets of the leg is added each
+// round, and the score is halved when the target is missed
+func (params *LegParameters) GetHalveItDefinition() *GameDefinition {
+	return &GameDefinition{Name: "Halve-It", ScoringRule: SCOREHALVE, WinCondition: WINHIGH, Targets: params.GetHalveItTargets()}
+}
+
+// ValidateHalveItTargets will verify that each given target of Halve-It can be hit. Targets may be left out to use the
+// default targets, but an empty list of targets is rejected
+func (params *LegParameters) ValidateHalveItTargets() error {
+	if params == nil || params.Targets == nil {
+		return nil
+	}
+	if len(params.Targets) == 0 {
+		return errors.New("at least one target is required")
+	}
+	for i, target := range params.Targets {
+		if err := target.ValidateInput(); err != nil {
+			return fmt.Errorf("invalid target for round %d: %s", i+1, err)
+		}
+	}
+	return nil
+}
+
+// GetCountUpRounds will return the number of rounds to play in Count-Up, defaulting to 8 if not set
+func (params *LegParameters) GetCountUpRounds() int {
+	if params != nil && params.Rounds.Int64 > 0 {
+		return int(params.Rounds.Int64)
+	}
+	return 8
+}
+
+// GetCheckout121Rounds will return the number of rounds to play in Checkout 121, defaulting to 15 if not set
+func (params *LegParameters) GetCheckout121Rounds() int {
+	if params != nil && params.Rounds.Int64 > 0 {
+		return int(params.Rounds.Int64)
+	}
+	return 15
+}
+
+// GetShanghaiRounds will return the number of rounds to play in Shanghai, defaulting to 20 if not set
+func (params *LegParameters) GetShanghaiRounds() int {
+	if params != nil && params.Rounds.Int64 > 0 && params.Rounds.Int64 < 20 {
+		return int(params.Rounds.Int64)
+	}
+	return 20
+}
+
+// ValidateShanghai will verify that the number of rounds of Shanghai, if set, is between 1 and 20
+func (params *LegParameters) ValidateShanghai() error {
+	if params != nil && params.Rounds.Valid && (params.Rounds.Int64 < 1 || params.Rounds.Int64 > 20) {
+		return errors.New("rounds has to be between 1 and 20")
+	}
+	return nil
+}
+
+// GetDartsAtXDarts will return the number of darts to throw in Darts at X, rounded up to a full visit, defaulting to 99 if not set
+func (params *LegParameters) GetDartsAtXDarts() int {
+	if params != nil && params.Darts.Int64 > 0 {
+		return int((params.Darts.Int64 + 2) / 3 * 3)
+	}
+	return 99
+}
+
+// ValidateDartsAtX will verify that the number of darts to throw in Darts at X, if set, is above 0
+func (params *LegParameters) ValidateDartsAtX() error {
+	if params != nil && params.Darts.Valid && params.Darts.Int64 < 1 {
+		return errors.New("darts has to be above 0")
+	}
+	return nil
+}
+
+// GetTargetMultiplier will return the multiplier required to hit a target in Around the Clock, defaulting to SINGLE if not set
+func (params *LegParameters) GetTargetMultiplier() int64 {
+	if params != nil && params.TargetMultiplier.Int64 >= SINGLE && params.TargetMultiplier.Int64 <= TRIPLE {
+		return params.TargetMultiplier.Int64
+	}
+	return SINGLE
+}
+
+// ValidateAroundTheClock will verify that the target multiplier of Around the Clock, if set, is a single, double or triple
+func (params *LegParameters) ValidateAroundTheClock() error {
+	if params != nil && params.TargetMultiplier.Valid && (params.TargetMultiplier.Int64 < SINGLE || params.TargetMultiplier.Int64 > TRIPLE) {
+		return errors.New("target_multiplier has to be one of 1 (single), 2 (double), 3 (triple)")
+	}
+	return nil
+}
+
+// GetKillBullStartingScore will return the score each player starts at, and goes back to when missing the bull, in Kill Bull,
+// defaulting to the starting score of the leg if not set
+func (params *LegParameters) GetKillBullStartingScore(legStartingScore int) int {
+	if params != nil && params.StartingScore.Int64 > 0 {
+		return int(params.StartingScore.Int64)
+	}
+	return legStartingScore
+}
+
+// ValidateKillBull will verify that the starting score of Kill Bull, if set, is above 0
+func (params *LegParameters) ValidateKillBull() error {
+	if params != nil && params.StartingScore.Valid && params.StartingScore.Int64 < 1 {
+		return errors.New("starting_score has to be above 0")
+	}
+	return nil
+}
+
+// ValidateKnockout will verify that the starting lives of Knockout are set, and that no other variant settings are given
+func (params *LegParameters) ValidateKnockout() error {
+	if params == nil || params.StartingLives.Int64 < 1 {
+		return errors.New("starting_lives is required for Knockout")
+	}
+	if params.Rounds.Valid || params.Darts.Valid || params.TargetMultiplier.Valid || params.StartingScore.Valid || params.Holes.Valid ||
+		params.Targets != nil || params.GameDefinitionID.Valid || params.OutshotType != nil || params.InshotType != nil {
+		return errors.New("only starting_lives can be set for Knockout")
+	}
+	return nil
+}
+
+// GetGolfHoles will return the number of holes to play in Golf, defaulting to 9 if not set
+func (params *LegParameters) GetGolfHoles() int {
+	if params != nil && params.Holes.Int64 == 18 {
+		return 18
+	}
+	return 9
+}
+
+// ValidateGolf will verify that the number of holes of Golf, if set, is 9 or 18
+func (params *LegParameters) ValidateGolf() error {
+	if params != nil && params.Holes.Valid && params.Holes.Int64 != 9 && params.Holes.Int64 != 18 {
+		return errors.New("holes has to be 9 or 18")
+	}
+	return nil
+}
+
+// GetOutshotTypeID will return the outshot type of the given leg, defaulting to Double Out if not set
+func (leg Leg) GetOutshotTypeID() int {
+	if leg.Parameters != nil && leg.Parameters.OutshotType != nil {
+		return leg.Parameters.OutshotType.ID
+	}
+	return OUTSHOTDOUBLE
+}
+
+// GetInshotTypeID will return the type of dart required to start scoring in the given leg, defaulting to Any In if not set
+func (leg Leg) GetInshotTypeID() int {
+	if leg.Parameters != nil && leg.Parameters.InshotType != nil {
+		return leg.Parameters.InshotType.ID
+	}
+	return OUTSHOTANY
 }
 
 // IsTicTacToeWinner will check if the given player has won a game of Tic Tac Toe
@@ -54,11 +223,35 @@ func (params LegParameters) IsTicTacToeWinner(playerID int) bool {
 	return false
 }
 
+// GenerateKillerNumbers will assign a unique random number between 1 and 20 to each of the given players
+func (params *LegParameters) GenerateKillerNumbers(players []int) error {
+	if len(players) > KillerMaxPlayers {
+		return fmt.Errorf("killer supports at most %d players", KillerMaxPlayers)
+	}
+	rand.Seed(time.Now().UnixNano())
+
+	numbers := rand.Perm(KillerMaxPlayers)
+	params.PlayerNumbers = make(map[int]int)
+	for i, playerID := range players {
+		params.PlayerNumbers[playerID] = numbers[i] + 1
+	}
+	return nil
+}
+
+// GetNumberOwner will return the player assigned the given number in Killer
+func (params *LegParameters) GetNumberOwner(number int) (int, bool) {
+	for playerID, num := range params.PlayerNumbers {
+		if num == number {
+			return playerID, true
+		}
+	}
+	return 0, false
+}
+
 // GenerateTicTacToeNumbers will generate 9 unique numbers for a Tic-Tac-Toe board
 func (params *LegParameters) GenerateTicTacToeNumbers(startingScore int) {
 	rand.Seed(time.Now().UnixNano())
 
-	bogey := []int{169, 168, 166, 165, 163, 162, 159}
 	numbers := make([]int, 9)
 
 	// Get 9 random numbers between the given range
@@ -71,7 +264,7 @@ func (params *LegParameters) GenerateTicTacToeNumbers(startingScore int) {
 		for valid {
 			num := rand.Intn(max-min) + min
 			// Make sure we don't select duplicates, and don't select bogey numbers
-			if !containsInt(numbers, num) && !containsInt(bogey, num) {
+			if !containsInt(numbers, num) && !IsBogeyNumber(num, OUTSHOTDOUBLE) {
 				numbers[i] = num
 				valid = false
 				if i%3 == 0 {
@@ -107,7 +300,7 @@ func (params *LegParameters) GenerateTicTacToeNumbers(startingScore int) {
 		if newMiddle%2 == 0 {
 			newMiddle++
 		}
-		if !containsInt(numbers, newMiddle) && !containsInt(bogey, newMiddle) {
+		if !containsInt(numbers, newMiddle) && !IsBogeyNumber(newMiddle, OUTSHOTDOUBLE) {
 			if newMiddle > 170 {
 				// Numbers got too big, so reset counter
 				iteration -= 10
@@ -217,6 +410,20 @@ type Player2Leg struct {
 	BotConfig       *BotConfig       `json:"bot_config,omitempty"`
 	Hits            map[int]*Hits    `json:"hits"`
 	DartsThrown     int              `json:"darts_thrown,omitempty"`
+	DartsToGetIn    null.Int         `json:"darts_to_get_in,omitempty"`
+	Checkout121     *Checkout121     `json:"checkout_121,omitempty"`
+}
+
+// LegState struct used for storing the state of a leg after a given number of visits
+type LegState struct {
+	LegID           int                 `json:"leg_id"`
+	Visit           int                 `json:"visit"`
+	Round           int                 `json:"round"`
+	CurrentPlayerID int                 `json:"current_player_id"`
+	IsFinished      bool                `json:"is_finished"`
+	Players         map[int]*Player2Leg `json:"players"`
+	Hits            map[int]int         `json:"hits,omitempty"`
+	LastVisit       *Visit              `json:"last_visit,omitempty"`
 }
 
 // BotConfig struct used for storing bot configuration
@@ -253,13 +460,3 @@ func (p2l *Player2Leg) AddVisitStatistics(leg Leg) {
 		}
 	}
 }
-
-// IsOut will check if the given player is out of the current match
-func (player *Player2Leg) IsOut(matchType int, visit Visit) bool {
-	if matchType == KNOCKOUT {
-		// If player has less than 1 life, and is not the current player
-		return player.Lives.Int64 < 1 && player.PlayerID != visit.PlayerID
-	}
-	// For all other types players are never out
-	return false
-}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"github.com/guregu/null"
+)
+
+const (
+	// LIGHTCUEBUST cue, when a visit is a bust
+	LIGHTCUEBUST = "bust"
+	// LIGHTCUECHECKOUT cue, when an X01 leg is checked out
+	LIGHTCUECHECKOUT = "checkout"
+	// LIGHTCUEONEEIGHTY cue, when a visit scores 180
+	LIGHTCUEONEEIGHTY = "one_eighty"
+	// LIGHTCUEPLAYERTURN cue, when it is the turn of a player, using the colour of the player
+	LIGHTCUEPLAYERTURN = "player_turn"
+	// LIGHTCUELEGWON cue, when a leg is won
+	LIGHTCUELEGWON = "leg_won"
+	// LIGHTCUEMATCHWON cue, when a match is won
+	LIGHTCUEMATCHWON = "match_won"
+)
+
+// LightCommand struct used for storing the colour and effect sent to LED controllers
+type LightCommand struct {
+	Color    string `yaml:"color" json:"color"`
+	Effect   string `yaml:"effect" json:"effect"`
+	Duration int    `yaml:"duration_ms" json:"duration_ms,omitempty"`
+}
+
+// LightCue struct used for storing a light cue for a venue
+type LightCue struct {
+	Cue      string       `json:"cue"`
+	VenueID  int          `json:"venue_id"`
+	LegID    null.Int     `json:"leg_id"`
+	PlayerID null.Int     `json:"player_id,omitempty"`
+	Command  LightCommand `json:"command"`
+}
+
+// DefaultLightCommands contains the command used for each cue not configured
+var DefaultLightCommands = map[string]LightCommand{
+	LIGHTCUEBUST:       {Color: "#ff0000", Effect: "flash", Duration: 1000},
+	LIGHTCUECHECKOUT:   {Color: "#00ff00", Effect: "flash", Duration: 2000},
+	LIGHTCUEONEEIGHTY:  {Color: "#ffffff", Effect: "rainbow", Duration: 3000},
+	LIGHTCUEPLAYERTURN: {Color: "#ffffff", Effect: "solid"},
+	LIGHTCUELEGWON:     {Color: "#00ff00", Effect: "pulse", Duration: 3000},
+	LIGHTCUEMATCHWON:   {Color: "#ffd700", Effect: "rainbow", Duration: 5000},
+}
+
+// GetCommand will return the command for the given cue, where the colour of the player is used for player turns if set
+func (config LEDConfig) GetCommand(cue string, playerColor null.String) LightCommand {
+	command, ok := config.Cues[cue]
+	if !ok {
+		command = DefaultLightCommands[cue]
+	}
+	if cue == LIGHTCUEPLAYERTURN && playerColor.Valid && playerColor.String != "" {
+		command.Color = playerColor.String
+	}
+	return command
+}
+
+// GetLightCues will return the cues triggered by the given event, not including player turns, which depend on the state of the leg
+func GetLightCues(event *Event) []string {
+	cues := make([]string, 0)
+	switch event.Type {
+	case EVENTVISITADDED:
+		if visit, ok := event.Data.(Visit); ok && visit.IsBust {
+			cues = append(cues, LIGHTCUEBUST)
+		}
+	case EVENTONEEIGHTY:
+		cues = append(cues, LIGHTCUEONEEIGHTY)
+	case EVENTLEGFINISHED:
+		if data, ok := event.Data.(LegFinishedEvent); ok {
+			if data.MatchTypeID == X01 || data.MatchTypeID == X01HANDICAP {
+				cues = append(cues, LIGHTCUECHECKOUT)
+			}
+			if data.WinnerID.Valid && !data.IsMatchFinished {
+				cues = append(cues, LIGHTCUELEGWON)
+			}
+		}
+	case EVENTMATCHFINISHED:
+		if data, ok := event.Data.(MatchFinishedEvent); ok && data.WinnerID.Valid {
+			cues = append(cues, LIGHTCUEMATCHWON)
+		}
+	}
+	return cues
+}
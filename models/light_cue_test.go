@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetLightCues will check which cues are triggered by game events
+func TestGetLightCues(t *testing.T) {
+	assert.Equal(t, GetLightCues(&Event{Type: EVENTVISITADDED, Data: Visit{IsBust: true}}), []string{LIGHTCUEBUST}, "bust should trigger bust cue")
+	assert.Empty(t, GetLightCues(&Event{Type: EVENTVISITADDED, Data: Visit{}}), "visit should not trigger cue")
+	assert.Equal(t, GetLightCues(&Event{Type: EVENTONEEIGHTY, Data: Visit{}}), []string{LIGHTCUEONEEIGHTY}, "180 should trigger one_eighty cue")
+
+	leg := LegFinishedEvent{WinnerID: null.IntFrom(1), MatchTypeID: X01}
+	assert.Equal(t, GetLightCues(&Event{Type: EVENTLEGFINISHED, Data: leg}), []string{LIGHTCUECHECKOUT, LIGHTCUELEGWON}, "X01 leg should trigger checkout and leg_won")
+	leg.IsMatchFinished = true
+	assert.Equal(t, GetLightCues(&Event{Type: EVENTLEGFINISHED, Data: leg}), []string{LIGHTCUECHECKOUT}, "last leg should only trigger checkout")
+	leg = LegFinishedEvent{WinnerID: null.IntFrom(1), MatchTypeID: SHANGHAI}
+	assert.Equal(t, GetLightCues(&Event{Type: EVENTLEGFINISHED, Data: leg}), []string{LIGHTCUELEGWON}, "non-X01 leg should not trigger checkout")
+
+	assert.Equal(t, GetLightCues(&Event{Type: EVENTMATCHFINISHED, Data: MatchFinishedEvent{WinnerID: null.IntFrom(1)}}), []string{LIGHTCUEMATCHWON}, "match should trigger match_won")
+	assert.Empty(t, GetLightCues(&Event{Type: EVENTMATCHFINISHED, Data: MatchFinishedEvent{}}), "draw should not trigger match_won")
+}
+
+// TestLEDConfigGetCommand will check that configured commands are used, falling back to defaults and the colour of the player
+func TestLEDConfigGetCommand(t *testing.T) {
+	config := LEDConfig{Cues: map[string]LightCommand{LIGHTCUEBUST: {Color: "#ff00ff", Effect: "solid"}}}
+	assert.Equal(t, config.GetCommand(LIGHTCUEBUST, null.StringFromPtr(nil)), LightCommand{Color: "#ff00ff", Effect: "solid"}, "should use configured command")
+	assert.Equal(t, config.GetCommand(LIGHTCUEMATCHWON, null.StringFromPtr(nil)), DefaultLightCommands[LIGHTCUEMATCHWON], "should use default command")
+	assert.Equal(t, config.GetCommand(LIGHTCUEPLAYERTURN, null.StringFrom("#123456")).Color, "#123456", "should use colour of player")
+	assert.Equal(t, config.GetCommand(LIGHTCUEPLAYERTURN, null.StringFrom("")).Color, "#ffffff", "should use default colour without player colour")
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strconv"
 
 	"github.com/guregu/null"
@@ -23,7 +25,7 @@ const (
 	SHOOTOUT = 2
 	// X01HANDICAP constant representing type 3
 	X01HANDICAP = 3
-	// CRICKET constant representing type 4
+	// CRICKET constant representing type 4, scored as Cut-throat Cricket
 	CRICKET = 4
 	// DARTSATX constant representing type 5
 	DARTSATX = 5
@@ -47,8 +49,85 @@ const (
 	JDCPRACTICE = 14
 	// KNOCKOUT constant representing type 15
 	KNOCKOUT = 15
+	// KILLER constant representing type 16
+	KILLER = 16
+	// GOLF constant representing type 17
+	GOLF = 17
+	// HALVEIT constant representing type 18
+	HALVEIT = 18
+	// BASEBALL constant representing type 19
+	BASEBALL = 19
+	// BOBS27 constant representing type 20
+	BOBS27 = 20
+	// COUNTUP constant representing type 21
+	COUNTUP = 21
+	// CHECKOUT121 constant representing type 22
+	CHECKOUT121 = 22
+	// CUSTOM constant representing type 23, played from a game definition
+	CUSTOM = 23
 )
 
+// KillerMaxPlayers is the maximum number of players in Killer, since each player is assigned a unique number between 1 and 20
+const KillerMaxPlayers = 20
+
+// Bobs27StartingScore is the score each player starts with in Bob's 27
+const Bobs27StartingScore = 27
+
+// TargetsBobs27 contains the double to hit in each round of Bob's 27
+var TargetsBobs27 = [21]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 25}
+
+// BaseballInnings is the number of innings played in Baseball before going to extra innings
+const BaseballInnings = 9
+
+// GetBaseballTarget will return the number to hit in the given inning (starting at 1) of Baseball. Extra innings continue
+// with the next numbers, and with bull after 20
+func GetBaseballTarget(inning int) int {
+	if inning > 20 {
+		return 25
+	}
+	return inning
+}
+
+// TargetsHalveIt contains the default target for each round of Halve-It, used when no targets are given for the leg
+var TargetsHalveIt = []Target{
+	{Value: 15, multipliers: []int64{1, 2, 3}},
+	{Value: 16, multipliers: []int64{1, 2, 3}},
+	{Value: -1, multipliers: []int64{2}},
+	{Value: 17, multipliers: []int64{1, 2, 3}},
+	{Value: 18, multipliers: []int64{1, 2, 3}},
+	{Value: -1, multipliers: []int64{3}},
+	{Value: 19, multipliers: []int64{1, 2, 3}},
+	{Value: 20, multipliers: []int64{1, 2, 3}},
+	{Value: 25, multipliers: []int64{1, 2}}}
+
+// GolfDartPenalty is the number of strokes added for each dart thrown before the target was hit in Golf. Since a hit scores
+// between 1 and 3 strokes, any hit is better than a hit with a later dart
+const GolfDartPenalty = 3
+
+// GolfMissStrokes is the number of strokes for a hole where the target was not hit, which is worse than a single on the third dart
+const GolfMissStrokes = 1 + 3*GolfDartPenalty
+
+// TargetsGolf contains the target for each hole of Golf
+var TargetsGolf = [18]Target{
+	{Value: 1, multipliers: []int64{1, 2, 3}},
+	{Value: 2, multipliers: []int64{1, 2, 3}},
+	{Value: 3, multipliers: []int64{1, 2, 3}},
+	{Value: 4, multipliers: []int64{1, 2, 3}},
+	{Value: 5, multipliers: []int64{1, 2, 3}},
+	{Value: 6, multipliers: []int64{1, 2, 3}},
+	{Value: 7, multipliers: []int64{1, 2, 3}},
+	{Value: 8, multipliers: []int64{1, 2, 3}},
+	{Value: 9, multipliers: []int64{1, 2, 3}},
+	{Value: 10, multipliers: []int64{1, 2, 3}},
+	{Value: 11, multipliers: []int64{1, 2, 3}},
+	{Value: 12, multipliers: []int64{1, 2, 3}},
+	{Value: 13, multipliers: []int64{1, 2, 3}},
+	{Value: 14, multipliers: []int64{1, 2, 3}},
+	{Value: 15, multipliers: []int64{1, 2, 3}},
+	{Value: 16, multipliers: []int64{1, 2, 3}},
+	{Value: 17, multipliers: []int64{1, 2, 3}},
+	{Value: 18, multipliers: []int64{1, 2, 3}}}
+
 // TargetsBermudaTriangle contains the target for each round of Bermuda Triangle
 var TargetsBermudaTriangle = [13]Target{
 	{Value: 12, multipliers: []int64{1, 2, 3}},
@@ -133,6 +212,7 @@ type Match struct {
 	TournamentID    null.Int           `json:"tournament_id,omitempty"`
 	Tournament      *MatchTournament   `json:"tournament,omitempty"`
 	Players         []int              `json:"players"`
+	Teams           []*Team            `json:"teams,omitempty"`
 	Legs            []*Leg             `json:"legs,omitempty"`
 	PlayerHandicaps map[int]int        `json:"player_handicaps,omitempty"`
 	BotPlayerConfig map[int]*BotConfig `json:"bot_player_config,omitempty"`
@@ -140,6 +220,8 @@ type Match struct {
 	LastThrow       null.String        `json:"last_throw_time,omitempty"`
 	EloChange       map[int]*PlayerElo `json:"elo_change,omitempty"`
 	LegsWon         []int              `json:"legs_won,omitempty"`
+	Sets            []*MatchSet        `json:"sets,omitempty"`
+	SetsWon         map[int]int        `json:"sets_won,omitempty"`
 }
 
 // MarshalJSON will marshall the given object to JSON
@@ -166,6 +248,7 @@ func (match Match) MarshalJSON() ([]byte, error) {
 		TournamentID     null.Int           `json:"tournament_id,omitempty"`
 		Tournament       *MatchTournament   `json:"tournament,omitempty"`
 		Players          []int              `json:"players"`
+		Teams            []*Team            `json:"teams,omitempty"`
 		Legs             []*Leg             `json:"legs,omitempty"`
 		CurrentLegNumber string             `json:"current_leg_num"`
 		PlayerHandicaps  map[int]int        `json:"player_handicaps,omitempty"`
@@ -174,6 +257,8 @@ func (match Match) MarshalJSON() ([]byte, error) {
 		LastThrow        null.String        `json:"last_throw_time,omitempty"`
 		EloChange        map[int]*PlayerElo `json:"elo_change,omitempty"`
 		LegsWon          []int              `json:"legs_won,omitempty"`
+		Sets             []*MatchSet        `json:"sets,omitempty"`
+		SetsWon          map[int]int        `json:"sets_won,omitempty"`
 	}
 	legPostfix := [4]string{"st", "nd", "rd", "th"}
 	idx := ((len(match.Legs)+90)%100-10)%10 - 1
@@ -205,6 +290,7 @@ func (match Match) MarshalJSON() ([]byte, error) {
 		TournamentID:     match.TournamentID,
 		Tournament:       match.Tournament,
 		Players:          match.Players,
+		Teams:            match.Teams,
 		Legs:             match.Legs,
 		CurrentLegNumber: legNum,
 		PlayerHandicaps:  match.PlayerHandicaps,
@@ -213,9 +299,102 @@ func (match Match) MarshalJSON() ([]byte, error) {
 		LastThrow:        match.LastThrow,
 		EloChange:        match.EloChange,
 		LegsWon:          match.LegsWon,
+		Sets:             match.Sets,
+		SetsWon:          match.SetsWon,
 	})
 }
 
+// SetSets will set the sets and the number of sets won by each player, given the winner of each leg in order of play
+func (match *Match) SetSets(legWinners []null.Int) {
+	if !match.MatchMode.HasSets() {
+		return
+	}
+	match.Sets = GetSets(legWinners, match.MatchMode.WinsRequired)
+	match.SetsWon = make(map[int]int)
+	for _, set := range match.Sets {
+		if set.WinnerID.Valid {
+			match.SetsWon[int(set.WinnerID.Int64)]++
+		}
+	}
+}
+
+// GetTeam will return the team represented by the given player, or nil if the player is not a team
+func (match Match) GetTeam(playerID int) *Team {
+	for _, team := range match.Teams {
+		if team.PlayerID == playerID {
+			return team
+		}
+	}
+	return nil
+}
+
+// SetThrower will set the player throwing the given visit, given the visits already thrown in the leg. Visits of a team must be
+// thrown by the next player of the team, and the thrower is set to that player if not given. Other visits have no thrower
+func (match Match) SetThrower(visit *Visit, visits []*Visit) error {
+	team := match.GetTeam(visit.PlayerID)
+	if team == nil {
+		visit.ThrowerID = null.IntFromPtr(nil)
+		return nil
+	}
+	thrower := team.GetNextThrower(visits)
+	if !visit.ThrowerID.Valid {
+		visit.ThrowerID = null.IntFrom(int64(thrower))
+	} else if !team.HasPlayer(int(visit.ThrowerID.Int64)) {
+		return fmt.Errorf("player %d is not a member of team %d", visit.ThrowerID.Int64, team.PlayerID)
+	} else if int(visit.ThrowerID.Int64) != thrower {
+		return fmt.Errorf("player %d of team %d should throw, not player %d", thrower, team.PlayerID, visit.ThrowerID.Int64)
+	}
+	return nil
+}
+
+// GetThrowerID will return the player to credit statistics of the given visit to. This is the thrower if it is a member of the
+// team throwing the visit, and the player of the visit otherwise
+func (match Match) GetThrowerID(visit Visit) int {
+	if team := match.GetTeam(visit.PlayerID); team != nil && team.HasPlayer(visit.GetThrowerID()) {
+		return visit.GetThrowerID()
+	}
+	return visit.PlayerID
+}
+
+// GetLegWinners will return the winner of each leg from the given player IDs, where 0 is a leg without a winner. Legs without a
+// winner, such as draws, are still played in a set, but are not won by any player
+func GetLegWinners(playerIDs []int) []null.Int {
+	winners := make([]null.Int, len(playerIDs))
+	for i, playerID := range playerIDs {
+		if playerID != 0 {
+			winners[i] = null.IntFrom(int64(playerID))
+		}
+	}
+	return winners
+}
+
+// GetStartingPlayerIndex will return the index of the player starting the given leg (0 based) of the given set (1 based), where
+// the starting player alternates both for each set and for each leg within the set
+func GetStartingPlayerIndex(set int, leg int, numPlayers int) int {
+	return (set - 1 + leg) % numPlayers
+}
+
+// GetSets will group legs, in order of play, into sets, where a set is won by the first player to win the given number of legs
+func GetSets(legWinners []null.Int, legsToWinSet int) []*MatchSet {
+	sets := make([]*MatchSet, 0)
+	var current *MatchSet
+	for _, winnerID := range legWinners {
+		if current == nil || current.WinnerID.Valid {
+			current = &MatchSet{Set: len(sets) + 1, LegsWon: make(map[int]int)}
+			sets = append(sets, current)
+		}
+		current.Legs++
+		if winnerID.Valid {
+			playerID := int(winnerID.Int64)
+			current.LegsWon[playerID]++
+			if current.LegsWon[playerID] == legsToWinSet {
+				current.WinnerID = winnerID
+			}
+		}
+	}
+	return sets
+}
+
 // MatchType struct used for storing match types
 type MatchType struct {
 	ID          int    `json:"id"`
@@ -237,9 +416,23 @@ type MatchMode struct {
 	ShortName           string   `json:"short_name"`
 	WinsRequired        int      `json:"wins_required"`
 	LegsRequired        null.Int `json:"legs_required"`
+	SetsRequired        null.Int `json:"sets_required,omitempty"`
 	TieBreakMatchTypeID null.Int `json:"tiebreak_match_type_id,omitempty"`
 }
 
+// HasSets will check if the match mode is played in sets, in which case WinsRequired is the number of legs required to win a set
+func (mode MatchMode) HasSets() bool {
+	return mode.SetsRequired.Valid && mode.SetsRequired.Int64 > 0
+}
+
+// MatchSet struct used for storing the result of a set
+type MatchSet struct {
+	Set      int         `json:"set"`
+	Legs     int         `json:"legs"`
+	LegsWon  map[int]int `json:"legs_won"`
+	WinnerID null.Int    `json:"winner_id"`
+}
+
 // MatchTournament struct for storing tournament information
 type MatchTournament struct {
 	TournamentID        null.Int    `json:"tournament_id"`
@@ -278,3 +471,107 @@ type Target struct {
 	multipliers []int64
 	score       int
 }
+
+// NewTarget will return a target for the given value, where -1 is any number, and the given multiplier, where null is any multiplier
+func NewTarget(value int, multiplier null.Int) Target {
+	if multiplier.Valid {
+		return Target{Value: value, multipliers: []int64{multiplier.Int64}}
+	}
+	return Target{Value: value, multipliers: []int64{SINGLE, DOUBLE, TRIPLE}}
+}
+
+// NewCustomTarget will return a target hit by the given value, or any of the given values, with one of the given multipliers.
+// A score above 0 is given for each hit instead of the score of the dart
+func NewCustomTarget(value int, values []int, multipliers []int64, score int) Target {
+	return Target{Value: value, Values: values, multipliers: multipliers, score: score}
+}
+
+// GetMultiplier will return the multiplier required to hit the given target, or null if any multiplier can be hit
+func (target Target) GetMultiplier() null.Int {
+	if len(target.multipliers) == 1 {
+		return null.IntFrom(target.multipliers[0])
+	}
+	return null.IntFromPtr(nil)
+}
+
+// targetJSON struct used for reading and writing targets as JSON, where multipliers are only given when more than one is allowed
+type targetJSON struct {
+	Value       int      `json:"value"`
+	Values      []int    `json:"values,omitempty"`
+	Multiplier  null.Int `json:"multiplier"`
+	Multipliers []int64  `json:"multipliers,omitempty"`
+	Score       int      `json:"score,omitempty"`
+}
+
+// MarshalJSON will marshall the given object to JSON
+func (target Target) MarshalJSON() ([]byte, error) {
+	t := targetJSON{Value: target.Value, Values: target.Values, Multiplier: target.GetMultiplier(), Score: target.score}
+	if len(target.multipliers) > 1 {
+		t.Multipliers = target.multipliers
+	}
+	return json.Marshal(t)
+}
+
+// UnmarshalJSON will unmarshall the given JSON to a target
+func (target *Target) UnmarshalJSON(data []byte) error {
+	var t targetJSON
+	err := json.Unmarshal(data, &t)
+	if err != nil {
+		return err
+	}
+	*target = NewTarget(t.Value, t.Multiplier)
+	if len(t.Multipliers) > 0 {
+		target.multipliers = t.Multipliers
+	}
+	target.Values = t.Values
+	target.score = t.Score
+	return nil
+}
+
+// GetMultipliers will return all multipliers which can be hit for the given target
+func (target Target) GetMultipliers() []int64 {
+	return target.multipliers
+}
+
+// GetScore will return the fixed score given for hitting the target, or 0 if the score of the dart is used
+func (target Target) GetScore() int {
+	return target.score
+}
+
+// ValidateInput will verify that the target can be hit
+func (target Target) ValidateInput() error {
+	values := target.Values
+	if len(values) == 0 {
+		if target.Value == -1 {
+			values = []int{1}
+		} else {
+			values = []int{target.Value}
+		}
+	}
+	for _, value := range values {
+		if value < 1 || (value > 20 && value != 25) {
+			return fmt.Errorf("target value %d has to be between 1 and 20, or 25 (bull), or -1 for any number", value)
+		}
+	}
+	if len(target.multipliers) == 0 {
+		return errors.New("target has to allow at least one multiplier")
+	}
+	isBullOnly := true
+	for _, value := range values {
+		isBullOnly = isBullOnly && value == 25
+	}
+	isTripleOnly := true
+	for _, multiplier := range target.multipliers {
+		if multiplier < SINGLE || multiplier > TRIPLE {
+			return errors.New("multiplier has to be one of 1 (single), 2 (double), 3 (triple)")
+		}
+		isTripleOnly = isTripleOnly && multiplier == TRIPLE
+	}
+	if isBullOnly && isTripleOnly {
+		return errors.New("target 25 (bull) cannot have multiplier 3, since there is no triple bull")
+	}
+	if target.score < 0 {
+		return errors.New("score cannot be less than 0")
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetSets will check that legs are grouped into sets, based on legs required to win a set
+func TestGetSets(t *testing.T) {
+	winners := []null.Int{null.IntFrom(1), null.IntFrom(2), null.IntFrom(1), null.IntFrom(2), null.IntFrom(1), null.IntFrom(2)}
+	sets := GetSets(winners, 2)
+	assert.Equal(t, len(sets), 2, "should have two sets")
+	assert.Equal(t, sets[0].Legs, 3, "first set should have three legs")
+	assert.Equal(t, sets[0].WinnerID, null.IntFrom(1), "player 1 should win first set")
+	assert.Equal(t, sets[1].Legs, 3, "second set should have three legs")
+	assert.Equal(t, sets[1].WinnerID, null.IntFrom(2), "player 2 should win second set")
+
+	sets = GetSets(append(winners, null.IntFromPtr(nil)), 2)
+	assert.Equal(t, len(sets), 3, "should have started third set")
+	assert.Equal(t, sets[2].WinnerID.Valid, false, "third set should not have a winner")
+}
+
+// TestGetSetsWithDraws will check that drawn legs are played in a set, without being won by any player
+func TestGetSetsWithDraws(t *testing.T) {
+	winners := GetLegWinners([]int{1, 0, 1, 2, 0, 2, 0})
+	assert.Equal(t, winners[1].Valid, false, "second leg should not have a winner")
+
+	sets := GetSets(winners, 2)
+	assert.Equal(t, len(sets), 3, "should have three sets")
+	assert.Equal(t, sets[0].Legs, 3, "first set should have three legs")
+	assert.Equal(t, sets[0].WinnerID, null.IntFrom(1), "player 1 should win first set")
+	assert.Equal(t, sets[1].Legs, 3, "second set should have three legs")
+	assert.Equal(t, sets[1].WinnerID, null.IntFrom(2), "player 2 should win second set")
+	assert.Equal(t, sets[2].Legs, 1, "third set should have one leg")
+	assert.Equal(t, sets[2].WinnerID.Valid, false, "third set should not have a winner")
+	assert.Equal(t, len(sets[2].LegsWon), 0, "no legs should be won in third set")
+}
+
+// TestSetSets will check that sets won are counted for each player
+func TestSetSets(t *testing.T) {
+	match := Match{MatchMode: &MatchMode{WinsRequired: 1, SetsRequired: null.IntFrom(2)}}
+	match.SetSets([]null.Int{null.IntFrom(1), null.IntFrom(2), null.IntFrom(1)})
+	assert.Equal(t, match.SetsWon[1], 2, "player 1 should have won two sets")
+	assert.Equal(t, match.SetsWon[2], 1, "player 2 should have won one set")
+
+	match = Match{MatchMode: &MatchMode{WinsRequired: 1}}
+	match.SetSets([]null.Int{null.IntFrom(1)})
+	assert.Nil(t, match.Sets, "should not have sets without sets required")
+}
+
+// TestGetStartingPlayerIndex will check that the starting player alternates per set and per leg
+func TestGetStartingPlayerIndex(t *testing.T) {
+	assert.Equal(t, GetStartingPlayerIndex(1, 0, 2), 0, "player 1 should start first leg of first set")
+	assert.Equal(t, GetStartingPlayerIndex(1, 1, 2), 1, "player 2 should start second leg of first set")
+	assert.Equal(t, GetStartingPlayerIndex(2, 0, 2), 1, "player 2 should start first leg of second set")
+	assert.Equal(t, GetStartingPlayerIndex(3, 2, 3), 1, "player 2 should start third leg of third set")
+}
+
+// TestGenerateKillerNumbers will check that each player is assigned a unique number between 1 and 20, and that more than 20 players are rejected
+func TestGenerateKillerNumbers(t *testing.T) {
+	params := new(LegParameters)
+	err := params.GenerateKillerNumbers([]int{1, 2, 3, 4})
+	assert.Nil(t, err)
+
+	seen := make(map[int]bool)
+	for _, playerID := range []int{1, 2, 3, 4} {
+		number := params.PlayerNumbers[playerID]
+		assert.Equal(t, number >= 1 && number <= 20, true, "number should be on the board")
+		assert.Equal(t, seen[number], false, "number should be unique")
+		seen[number] = true
+
+		owner, ok := params.GetNumberOwner(number)
+		assert.Equal(t, ok, true, "number should have an owner")
+		assert.Equal(t, owner, playerID, "owner should be player")
+	}
+
+	players := make([]int, 0)
+	for i := 1; i <= KillerMaxPlayers; i++ {
+		players = append(players, i)
+	}
+	err = params.GenerateKillerNumbers(players)
+	assert.Nil(t, err)
+
+	seen = make(map[int]bool)
+	for _, playerID := range players {
+		seen[params.PlayerNumbers[playerID]] = true
+	}
+	assert.Equal(t, len(seen), KillerMaxPlayers, "all numbers should be assigned once")
+
+	err = params.GenerateKillerNumbers(append(players, KillerMaxPlayers+1))
+	assert.NotNil(t, err, "more than 20 players should be rejected")
+}
+
+// TestTargetJSON will check that a target can be given as JSON and written back with the same value and multiplier
+func TestTargetJSON(t *testing.T) {
+	var targets []Target
+	err := json.Unmarshal([]byte(`[{"value": 20}, {"value": -1, "multiplier": 2}]`), &targets)
+	assert.Nil(t, err)
+	assert.Equal(t, targets[0].GetMultiplier().Valid, false, "any multiplier should be null")
+	assert.Equal(t, targets[1].GetMultiplier(), null.IntFrom(2), "multiplier should be double")
+
+	b, err := json.Marshal(targets[1])
+	assert.Nil(t, err)
+	assert.Equal(t, string(b), `{"value":-1,"multiplier":2}`, "target should be marshalled")
+}
+
+// TestValidateHalveItTargets will check that default targets are allowed, while empty and invalid targets are rejected
+func TestValidateHalveItTargets(t *testing.T) {
+	var params *LegParameters
+	assert.Nil(t, params.ValidateHalveItTargets(), "should allow default targets")
+
+	params = &LegParameters{Targets: []Target{NewTarget(-1, null.IntFrom(DOUBLE)), NewTarget(25, null.IntFromPtr(nil))}}
+	assert.Nil(t, params.ValidateHalveItTargets())
+
+	params.Targets = []Target{}
+	assert.NotNil(t, params.ValidateHalveItTargets(), "should not allow empty targets")
+
+	params.Targets = []Target{NewTarget(20, null.IntFromPtr(nil)), NewTarget(0, null.IntFromPtr(nil))}
+	assert.NotNil(t, params.ValidateHalveItTargets(), "should not allow target below 1")
+
+	params.Targets = []Target{NewTarget(20, null.IntFrom(4))}
+	assert.NotNil(t, params.ValidateHalveItTargets(), "should not allow unknown multiplier")
+
+	params.Targets = []Target{NewTarget(25, null.IntFrom(TRIPLE))}
+	assert.NotNil(t, params.ValidateHalveItTargets(), "should not allow triple bull")
+
+	params.Targets = []Target{NewCustomTarget(0, []int{20, 25}, []int64{TRIPLE}, 0)}
+	assert.Nil(t, params.ValidateHalveItTargets(), "should allow triple of other values than bull")
+}
+
+// TestGetCountUpRounds will check that Count-Up uses the configured number of rounds, defaulting to 8
+func TestGetCountUpRounds(t *testing.T) {
+	var params *LegParameters
+	assert.Equal(t, params.GetCountUpRounds(), 8, "nil parameters should default to 8 rounds")
+	assert.Equal(t, new(LegParameters).GetCountUpRounds(), 8, "missing rounds should default to 8 rounds")
+	assert.Equal(t, (&LegParameters{Rounds: null.IntFrom(12)}).GetCountUpRounds(), 12, "rounds should be used")
+}
+
+// TestGetVariantParameters will check that variant settings are used when set, and fall back to the standard rules otherwise
+func TestGetVariantParameters(t *testing.T) {
+	var params *LegParameters
+	assert.Equal(t, params.GetShanghaiRounds(), 20, "nil parameters should default to 20 rounds")
+	assert.Equal(t, params.GetDartsAtXDarts(), 99, "nil parameters should default to 99 darts")
+	assert.Equal(t, params.GetTargetMultiplier(), int64(SINGLE), "nil parameters should default to singles")
+
+	params = &LegParameters{Rounds: null.IntFrom(7), Darts: null.IntFrom(50), TargetMultiplier: null.IntFrom(TRIPLE)}
+	assert.Equal(t, params.GetShanghaiRounds(), 7, "rounds should be used")
+	assert.Equal(t, params.GetDartsAtXDarts(), 51, "darts should be rounded up to a full visit")
+	assert.Equal(t, params.GetTargetMultiplier(), int64(TRIPLE), "multiplier should be used")
+
+	params = &LegParameters{Rounds: null.IntFrom(25), TargetMultiplier: null.IntFrom(4)}
+	assert.Equal(t, params.GetShanghaiRounds(), 20, "Shanghai cannot be played for more than 20 rounds")
+	assert.Equal(t, params.GetTargetMultiplier(), int64(SINGLE), "invalid multiplier should default to singles")
+}
+
+// TestGetKillBullStartingScore will check that the starting score of the leg parameters is used for Kill Bull when set
+func TestGetKillBullStartingScore(t *testing.T) {
+	var params *LegParameters
+	assert.Equal(t, params.GetKillBullStartingScore(301), 301, "nil parameters should use starting score of leg")
+	assert.Nil(t, params.ValidateKillBull())
+
+	params = &LegParameters{StartingScore: null.IntFrom(200)}
+	assert.Equal(t, params.GetKillBullStartingScore(301), 200, "starting score should be used")
+	assert.Nil(t, params.ValidateKillBull())
+
+	params.StartingScore = null.IntFrom(0)
+	assert.Equal(t, params.GetKillBullStartingScore(301), 301, "zero should use starting score of leg")
+	assert.NotNil(t, params.ValidateKillBull(), "should not allow starting score of zero")
+}
+
+// TestValidateVariantParameters will check that out of range variant settings are rejected instead of replaced by the default
+func TestValidateVariantParameters(t *testing.T) {
+	var params *LegParameters
+	assert.Nil(t, params.ValidateShanghai())
+	assert.Nil(t, params.ValidateDartsAtX())
+	assert.Nil(t, params.ValidateAroundTheClock())
+	assert.Nil(t, params.ValidateGolf())
+
+	params = &LegParameters{Rounds: null.IntFrom(20), Darts: null.IntFrom(1), TargetMultiplier: null.IntFrom(TRIPLE), Holes: null.IntFrom(18)}
+	assert.Nil(t, params.ValidateShanghai())
+	assert.Nil(t, params.ValidateDartsAtX())
+	assert.Nil(t, params.ValidateAroundTheClock())
+	assert.Nil(t, params.ValidateGolf())
+
+	params = &LegParameters{Rounds: null.IntFrom(25), Darts: null.IntFrom(-5), TargetMultiplier: null.IntFrom(4), Holes: null.IntFrom(12)}
+	assert.NotNil(t, params.ValidateShanghai(), "should not allow more than 20 rounds")
+	assert.NotNil(t, params.ValidateDartsAtX(), "should not allow negative darts")
+	assert.NotNil(t, params.ValidateAroundTheClock(), "should not allow unknown multiplier")
+	assert.NotNil(t, params.ValidateGolf(), "should only allow 9 or 18 holes")
+}
+
+// TestValidateKnockout will check that Knockout requires starting lives, and rejects any other variant settings
+func TestValidateKnockout(t *testing.T) {
+	var params *LegParameters
+	assert.NotNil(t, params.ValidateKnockout(), "should require starting lives")
+
+	params = &LegParameters{StartingLives: null.IntFrom(3)}
+	assert.Nil(t, params.ValidateKnockout())
+
+	params.Rounds = null.IntFrom(10)
+	assert.NotNil(t, params.ValidateKnockout(), "should not allow rounds")
+
+	params = &LegParameters{StartingLives: null.IntFrom(3), StartingScore: null.IntFrom(100)}
+	assert.NotNil(t, params.ValidateKnockout(), "should not allow starting score")
+
+	params = &LegParameters{StartingLives: null.IntFrom(3), Targets: []Target{NewTarget(20, null.IntFromPtr(nil))}}
+	assert.NotNil(t, params.ValidateKnockout(), "should not allow targets")
+}
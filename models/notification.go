@@ -0,0 +1,129 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/guregu/null"
+)
+
+const (
+	// NOTIFYMATCHNEXT notification, telling players their official match is next
+	NOTIFYMATCHNEXT = "match_next"
+	// NOTIFYMATCHRESULT notification, posting the result of a match with averages
+	NOTIFYMATCHRESULT = "match_result"
+	// NOTIFYPERSONALBEST notification, posting new personal bests
+	NOTIFYPERSONALBEST = "personal_best"
+	// NOTIFYSTANDINGS notification, posting changes to the tournament standings
+	NOTIFYSTANDINGS = "standings"
+)
+
+// NotificationMessage struct used for storing a Slack-compatible message
+type NotificationMessage struct {
+	Channel  string `json:"channel,omitempty"`
+	Username string `json:"username,omitempty"`
+	Text     string `json:"text"`
+}
+
+// NotificationTransport is used to send notification messages, allowing messages to be sent somewhere other than Slack
+type NotificationTransport interface {
+	// Send will deliver the given message
+	Send(message NotificationMessage) error
+}
+
+// SlackTransport struct used to send messages to a Slack-compatible incoming webhook
+type SlackTransport struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewSlackTransport will return a new transport posting messages to the given webhook URL
+func NewSlackTransport(url string) *SlackTransport {
+	return &SlackTransport{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Send will post the given message as JSON to the webhook URL
+func (transport *SlackTransport) Send(message NotificationMessage) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	resp, err := transport.Client.Post(transport.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unable to send notification: %s", resp.Status)
+	}
+	return nil
+}
+
+// StandingChange struct used for storing a change of rank in the tournament standings
+type StandingChange struct {
+	Player       *Player
+	Rank         int
+	PreviousRank int
+}
+
+// GetMention will return the Slack mention of the given player, or the name of the player if no Slack handle is set
+func GetMention(player *Player) string {
+	if player.SlackHandle.Valid && player.SlackHandle.String != "" {
+		return fmt.Sprintf("<@%s>", strings.TrimPrefix(player.SlackHandle.String, "@"))
+	}
+	return player.GetName()
+}
+
+// GetMatchNextMessage will return the message telling a player that their official match against the given opponent is next
+func GetMatchNextMessage(player *Player, opponent *Player, venue null.String) string {
+	text := fmt.Sprintf("%s, your official match vs %s is next", GetMention(player), opponent.GetName())
+	if venue.Valid && venue.String != "" {
+		text += " on " + venue.String
+	}
+	return text
+}
+
+// GetMatchResultMessage will return the message with the result of the given match, with the legs won and
+// three dart average of each player
+func GetMatchResultMessage(players []*Player, winnerID null.Int, legsWon map[int]int, averages map[int]float32) string {
+	results := make([]string, len(players))
+	for i, player := range players {
+		result := fmt.Sprintf("%s %d", player.GetName(), legsWon[player.ID])
+		if avg, ok := averages[player.ID]; ok {
+			result += fmt.Sprintf(" (%.2f)", avg)
+		}
+		results[i] = result
+	}
+	text := strings.Join(results, " - ")
+	for _, player := range players {
+		if winnerID.Valid && int(winnerID.Int64) == player.ID {
+			return fmt.Sprintf("%s won! %s", GetMention(player), text)
+		}
+	}
+	return "Match ended in a draw: " + text
+}
+
+// GetPersonalBestMessage will return the message for a new highest checkout of the given player
+func GetPersonalBestMessage(player *Player, checkout int, previous null.Int) string {
+	if previous.Valid {
+		return fmt.Sprintf("%s checked out %d, a new personal best (previous %d)", GetMention(player), checkout, previous.Int64)
+	}
+	return fmt.Sprintf("%s checked out %d, a new personal best", GetMention(player), checkout)
+}
+
+// GetStandingsMessage will return the message listing the given changes to the tournament standings
+func GetStandingsMessage(changes []*StandingChange) string {
+	lines := make([]string, len(changes))
+	for i, change := range changes {
+		direction := "up"
+		if change.Rank > change.PreviousRank {
+			direction = "down"
+		}
+		lines[i] = fmt.Sprintf("%s moved %s from #%d to #%d", change.Player.GetName(), direction, change.PreviousRank, change.Rank)
+	}
+	return "Tournament standings changed:\n" + strings.Join(lines, "\n")
+}
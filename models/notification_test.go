@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSlackTransport will check that messages are posted as JSON to the webhook URL
+func TestSlackTransport(t *testing.T) {
+	var received NotificationMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	err := NewSlackTransport(server.URL).Send(NotificationMessage{Channel: "#darts", Username: "kcapp", Text: "Hello"})
+	assert.Nil(t, err)
+	assert.Equal(t, received, NotificationMessage{Channel: "#darts", Username: "kcapp", Text: "Hello"}, "should receive message")
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	assert.NotNil(t, NewSlackTransport(server.URL).Send(NotificationMessage{Text: "Hello"}), "should fail on error response")
+}
+
+// TestNotificationMessages will check that players with a Slack handle are mentioned in messages
+func TestNotificationMessages(t *testing.T) {
+	one := &Player{ID: 1, FirstName: "Jane", LastName: null.StringFrom("Doe"), SlackHandle: null.StringFrom("@jane")}
+	two := &Player{ID: 2, FirstName: "John"}
+
+	assert.Equal(t, GetMatchNextMessage(one, two, null.StringFrom("Board 2")), "<@jane>, your official match vs John is next on Board 2")
+	assert.Equal(t, GetMatchNextMessage(two, one, null.StringFromPtr(nil)), "John, your official match vs Jane Doe is next")
+	assert.Equal(t, GetMatchResultMessage([]*Player{one, two}, null.IntFrom(1), map[int]int{1: 3, 2: 1}, map[int]float32{1: 62.5, 2: 48.123}),
+		"<@jane> won! Jane Doe 3 (62.50) - John 1 (48.12)")
+	assert.Equal(t, GetMatchResultMessage([]*Player{one, two}, null.IntFromPtr(nil), map[int]int{1: 1, 2: 1}, map[int]float32{}),
+		"Match ended in a draw: Jane Doe 1 - John 1")
+	assert.Equal(t, GetPersonalBestMessage(one, 121, null.IntFrom(100)), "<@jane> checked out 121, a new personal best (previous 100)")
+	assert.Equal(t, GetStandingsMessage([]*StandingChange{{Player: two, Rank: 1, PreviousRank: 3}}),
+		"Tournament standings changed:\nJohn moved up from #3 to #1")
+}
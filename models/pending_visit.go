@@ -0,0 +1,61 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/guregu/null"
+)
+
+// PendingVisit struct used for storing darts thrown in a visit which is not yet written to the database
+type PendingVisit struct {
+	LegID      int     `json:"leg_id"`
+	PlayerID   int     `json:"player_id"`
+	Darts      []*Dart `json:"darts"`
+	IsBust     bool    `json:"is_bust"`
+	IsFinished bool    `json:"is_finished"`
+	Visit      *Visit  `json:"visit,omitempty"`
+}
+
+// NewPendingVisit will return a new pending visit without any darts for the given player
+func NewPendingVisit(legID int, playerID int) *PendingVisit {
+	return &PendingVisit{LegID: legID, PlayerID: playerID, Darts: make([]*Dart, 0, 3)}
+}
+
+// AddDart will add the given dart to the pending visit
+func (pending *PendingVisit) AddDart(dart Dart) error {
+	if len(pending.Darts) >= 3 {
+		return errors.New("visit already has three darts")
+	}
+	pending.Darts = append(pending.Darts, &dart)
+	return nil
+}
+
+// UndoDart will remove the last dart thrown from the pending visit
+func (pending *PendingVisit) UndoDart() error {
+	if len(pending.Darts) == 0 {
+		return errors.New("no darts to undo")
+	}
+	pending.Darts = pending.Darts[:len(pending.Darts)-1]
+	return nil
+}
+
+// IsComplete will check if all three darts of the pending visit are thrown
+func (pending PendingVisit) IsComplete() bool {
+	return len(pending.Darts) == 3
+}
+
+// GetVisit will return a visit containing the darts thrown so far, where darts not thrown are
+// either nil (not thrown) or a miss, depending on the given flag
+func (pending PendingVisit) GetVisit(missRemaining bool) Visit {
+	darts := make([]*Dart, 3)
+	for i := range darts {
+		if i < len(pending.Darts) {
+			darts[i] = NewDart(pending.Darts[i].Value, pending.Darts[i].Multiplier)
+		} else if missRemaining {
+			darts[i] = NewDart(null.IntFrom(0), SINGLE)
+		} else {
+			darts[i] = NewDart(null.IntFromPtr(nil), SINGLE)
+		}
+	}
+	return Visit{LegID: pending.LegID, PlayerID: pending.PlayerID, FirstDart: darts[0], SecondDart: darts[1], ThirdDart: darts[2]}
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPendingVisitAddDart will check that darts can be added to a pending visit
+func TestPendingVisitAddDart(t *testing.T) {
+	pending := NewPendingVisit(1, 2)
+	assert.Nil(t, pending.AddDart(Dart{Value: null.IntFrom(20), Multiplier: 3}))
+	assert.Nil(t, pending.AddDart(Dart{Value: null.IntFrom(20), Multiplier: 1}))
+	assert.Equal(t, pending.IsComplete(), false, "should not be complete")
+	assert.Nil(t, pending.AddDart(Dart{Value: null.IntFrom(5), Multiplier: 1}))
+	assert.Equal(t, pending.IsComplete(), true, "should be complete")
+	assert.NotNil(t, pending.AddDart(Dart{Value: null.IntFrom(1), Multiplier: 1}), "should not allow fourth dart")
+}
+
+// TestPendingVisitUndoDart will check that the last dart is removed from a pending visit
+func TestPendingVisitUndoDart(t *testing.T) {
+	pending := NewPendingVisit(1, 2)
+	assert.NotNil(t, pending.UndoDart(), "should not undo without darts")
+
+	pending.AddDart(Dart{Value: null.IntFrom(20), Multiplier: 3})
+	pending.AddDart(Dart{Value: null.IntFrom(1), Multiplier: 1})
+	assert.Nil(t, pending.UndoDart())
+	assert.Equal(t, len(pending.Darts), 1, "should have one dart")
+	assert.Equal(t, pending.Darts[0].GetScore(), 60, "should keep first dart")
+}
+
+// TestPendingVisitGetVisit will check that a visit is created from the darts thrown
+func TestPendingVisitGetVisit(t *testing.T) {
+	pending := NewPendingVisit(1, 2)
+	pending.AddDart(Dart{Value: null.IntFrom(20), Multiplier: 3})
+
+	visit := pending.GetVisit(false)
+	assert.Equal(t, visit.LegID, 1, "leg should be 1")
+	assert.Equal(t, visit.PlayerID, 2, "player should be 2")
+	assert.Equal(t, visit.FirstDart.GetScore(), 60, "first dart should be 60")
+	assert.Equal(t, visit.SecondDart.Value.Valid, false, "second dart should not be thrown")
+	assert.Equal(t, visit.ThirdDart.Value.Valid, false, "third dart should not be thrown")
+
+	visit = pending.GetVisit(true)
+	assert.Equal(t, visit.SecondDart.IsMiss(), true, "second dart should be miss")
+	assert.Equal(t, visit.SecondDart.Value.Valid, true, "second dart should be thrown")
+}
@@ -39,6 +39,15 @@ type PlayerStatistics struct {
 	Shootout *StatisticsShootout `json:"shootout"`
 	Cricket  *StatisticsCricket  `json:"cricket"`
 	DartsAt  *StatisticsDartsAtX `json:"darts_at_x"`
+	Bobs27   *StatisticsBobs27   `json:"bobs_27,omitempty"`
+}
+
+// GetName will return the full name of the player
+func (player Player) GetName() string {
+	if player.LastName.Valid && player.LastName.String != "" {
+		return player.FirstName + " " + player.LastName.String
+	}
+	return player.FirstName
 }
 
 // MarshalJSON will marshall the given object to JSON
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/guregu/null"
+)
+
+const (
+	// SMARTBOARDTHROW event, sent when a dart hits a segment of the board
+	SMARTBOARDTHROW = "throw"
+	// SMARTBOARDBUTTON event, sent when a button on the board is pressed
+	SMARTBOARDBUTTON = "button"
+	// SMARTBOARDTAKEOUT event, sent when darts are removed from the board
+	SMARTBOARDTAKEOUT = "takeout"
+
+	// SMARTBOARDACTIONDART action, adding a dart to the visit of the current player
+	SMARTBOARDACTIONDART = "dart"
+	// SMARTBOARDACTIONNEXT action, ending the visit of the current player
+	SMARTBOARDACTIONNEXT = "next"
+	// SMARTBOARDACTIONUNDO action, removing the last dart thrown
+	SMARTBOARDACTIONUNDO = "undo"
+	// SMARTBOARDACTIONIGNORE action, for events which do not change the leg
+	SMARTBOARDACTIONIGNORE = "ignore"
+)
+
+// SmartboardEvent struct used for storing raw events sent by a smartboard bridge
+type SmartboardEvent struct {
+	Type       string   `json:"type"`
+	Segment    null.Int `json:"segment"`
+	Multiplier int64    `json:"multiplier"`
+	Button     null.Int `json:"button"`
+	LongPress  bool     `json:"long_press"`
+}
+
+// ValidateInput will verify that the event is of a known type, with a valid segment for throws and a button for button presses
+func (event SmartboardEvent) ValidateInput() error {
+	switch event.Type {
+	case SMARTBOARDTHROW:
+		if !event.Segment.Valid {
+			return errors.New("segment is required for throw")
+		}
+		dart := event.GetDart()
+		return dart.ValidateInput()
+	case SMARTBOARDBUTTON:
+		if !event.Button.Valid {
+			return errors.New("button is required for button press")
+		}
+	case SMARTBOARDTAKEOUT:
+	default:
+		return fmt.Errorf("type has to be one of '%s', '%s', '%s'", SMARTBOARDTHROW, SMARTBOARDBUTTON, SMARTBOARDTAKEOUT)
+	}
+	return nil
+}
+
+// GetDart will return the dart thrown in the given event, where a missing multiplier is a single
+func (event SmartboardEvent) GetDart() Dart {
+	multiplier := event.Multiplier
+	if multiplier == 0 {
+		multiplier = SINGLE
+	}
+	return Dart{Value: event.Segment, Multiplier: multiplier}
+}
+
+// GetAction will return what the event should do to the active leg of the venue. The configured smartboard button
+// moves on to the next player, or undoes the last dart on a long press
+func (event SmartboardEvent) GetAction(config *VenueConfig) string {
+	switch event.Type {
+	case SMARTBOARDTHROW:
+		return SMARTBOARDACTIONDART
+	case SMARTBOARDTAKEOUT:
+		return SMARTBOARDACTIONNEXT
+	case SMARTBOARDBUTTON:
+		if config.SmartboardButtonNumber.Valid && config.SmartboardButtonNumber == event.Button {
+			if event.LongPress {
+				return SMARTBOARDACTIONUNDO
+			}
+			return SMARTBOARDACTIONNEXT
+		}
+	}
+	return SMARTBOARDACTIONIGNORE
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSmartboardEventValidateInput will check that throws need a valid segment, and button presses a button
+func TestSmartboardEventValidateInput(t *testing.T) {
+	assert.Nil(t, SmartboardEvent{Type: SMARTBOARDTHROW, Segment: null.IntFrom(20), Multiplier: TRIPLE}.ValidateInput())
+	assert.Nil(t, SmartboardEvent{Type: SMARTBOARDTAKEOUT}.ValidateInput())
+	assert.NotNil(t, SmartboardEvent{Type: SMARTBOARDTHROW}.ValidateInput(), "throw without segment should be invalid")
+	assert.NotNil(t, SmartboardEvent{Type: SMARTBOARDTHROW, Segment: null.IntFrom(22)}.ValidateInput(), "invalid segment should be invalid")
+	assert.NotNil(t, SmartboardEvent{Type: SMARTBOARDBUTTON}.ValidateInput(), "button press without button should be invalid")
+	assert.NotNil(t, SmartboardEvent{Type: "unknown"}.ValidateInput(), "unknown type should be invalid")
+}
+
+// TestSmartboardEventGetDart will check that throws without multiplier are singles
+func TestSmartboardEventGetDart(t *testing.T) {
+	assert.Equal(t, SmartboardEvent{Type: SMARTBOARDTHROW, Segment: null.IntFrom(5)}.GetDart(), Dart{Value: null.IntFrom(5), Multiplier: SINGLE})
+	assert.Equal(t, SmartboardEvent{Type: SMARTBOARDTHROW, Segment: null.IntFrom(25), Multiplier: DOUBLE}.GetDart(), Dart{Value: null.IntFrom(25), Multiplier: DOUBLE})
+}
+
+// TestSmartboardEventGetAction will check that only the configured button moves to the next player or undoes the last dart
+func TestSmartboardEventGetAction(t *testing.T) {
+	config := &VenueConfig{HasSmartboard: true, SmartboardButtonNumber: null.IntFrom(3)}
+	assert.Equal(t, SmartboardEvent{Type: SMARTBOARDTHROW}.GetAction(config), SMARTBOARDACTIONDART)
+	assert.Equal(t, SmartboardEvent{Type: SMARTBOARDTAKEOUT}.GetAction(config), SMARTBOARDACTIONNEXT)
+	assert.Equal(t, SmartboardEvent{Type: SMARTBOARDBUTTON, Button: null.IntFrom(3)}.GetAction(config), SMARTBOARDACTIONNEXT)
+	assert.Equal(t, SmartboardEvent{Type: SMARTBOARDBUTTON, Button: null.IntFrom(3), LongPress: true}.GetAction(config), SMARTBOARDACTIONUNDO)
+	assert.Equal(t, SmartboardEvent{Type: SMARTBOARDBUTTON, Button: null.IntFrom(1)}.GetAction(config), SMARTBOARDACTIONIGNORE)
+	assert.Equal(t, SmartboardEvent{Type: SMARTBOARDBUTTON, Button: null.IntFrom(3)}.GetAction(&VenueConfig{}), SMARTBOARDACTIONIGNORE)
+}
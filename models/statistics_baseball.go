@@ -0,0 +1,21 @@
+package models
+
+import "github.com/guregu/null"
+
+// StatisticsBaseball struct used for storing statistics for Baseball
+type StatisticsBaseball struct {
+	ID              int             `json:"id"`
+	LegID           int             `json:"leg_id"`
+	PlayerID        int             `json:"player_id"`
+	MatchesPlayed   int             `json:"matches_played"`
+	MatchesWon      int             `json:"matches_won"`
+	LegsPlayed      int             `json:"legs_played"`
+	LegsWon         int             `json:"legs_won"`
+	OfficeID        null.Int        `json:"office_id,omitempty"`
+	DartsThrown     int             `json:"darts_thrown,omitempty"`
+	Score           int             `json:"score"`
+	InningsPlayed   int             `json:"innings_played"`
+	TotalHitRate    float64         `json:"total_hit_rate"`
+	Innings         map[int]float64 `json:"innings,omitempty"`
+	ExtraInningRuns float64         `json:"extra_inning_runs"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "github.com/guregu/null"
+
+// StatisticsBobs27 struct used for storing statistics for Bob's 27
+type StatisticsBobs27 struct {
+	ID                  int             `json:"id"`
+	LegID               int             `json:"leg_id"`
+	PlayerID            int             `json:"player_id"`
+	MatchesPlayed       int             `json:"matches_played"`
+	MatchesWon          int             `json:"matches_won"`
+	LegsPlayed          int             `json:"legs_played"`
+	LegsWon             int             `json:"legs_won"`
+	OfficeID            null.Int        `json:"office_id,omitempty"`
+	DartsThrown         int             `json:"darts_thrown,omitempty"`
+	Score               int             `json:"score"`
+	HighestScoreReached int             `json:"highest_score_reached"`
+	RoundsPlayed        int             `json:"rounds_played"`
+	DoublesHit          int             `json:"doubles_hit"`
+	TotalHitRate        float64         `json:"total_hit_rate"`
+	Hitrates            map[int]float64 `json:"hitrates,omitempty"`
+}
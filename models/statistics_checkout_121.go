@@ -0,0 +1,23 @@
+package models
+
+import "github.com/guregu/null"
+
+// StatisticsCheckout121 struct used for storing statistics for Checkout 121
+type StatisticsCheckout121 struct {
+	ID                 int      `json:"id"`
+	LegID              int      `json:"leg_id"`
+	PlayerID           int      `json:"player_id"`
+	MatchesPlayed      int      `json:"matches_played"`
+	MatchesWon         int      `json:"matches_won"`
+	LegsPlayed         int      `json:"legs_played"`
+	LegsWon            int      `json:"legs_won"`
+	OfficeID           null.Int `json:"office_id,omitempty"`
+	DartsThrown        int      `json:"darts_thrown,omitempty"`
+	HighestTarget      int      `json:"highest_target"`
+	TargetsAttempted   int      `json:"targets_attempted"`
+	Checkouts          int      `json:"checkouts"`
+	SuccessRate        float64  `json:"success_rate"`
+	DartsPerCheckout   float64  `json:"darts_per_checkout"`
+	CheckoutAttempts   int      `json:"checkout_attempts"`
+	CheckoutPercentage float64  `json:"checkout_percentage"`
+}
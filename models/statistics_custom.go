@@ -0,0 +1,21 @@
+package models
+
+import "github.com/guregu/null"
+
+// StatisticsCustom struct used for storing statistics for custom games, with one entry for each game definition
+type StatisticsCustom struct {
+	ID               int             `json:"id"`
+	LegID            int             `json:"leg_id"`
+	PlayerID         int             `json:"player_id"`
+	GameDefinitionID int             `json:"game_definition_id"`
+	MatchesPlayed    int             `json:"matches_played"`
+	MatchesWon       int             `json:"matches_won"`
+	LegsPlayed       int             `json:"legs_played"`
+	LegsWon          int             `json:"legs_won"`
+	OfficeID         null.Int        `json:"office_id,omitempty"`
+	DartsThrown      int             `json:"darts_thrown,omitempty"`
+	Score            int             `json:"score"`
+	HitCount         int             `json:"hit_count"`
+	TotalHitRate     float64         `json:"total_hit_rate"`
+	Hitrates         map[int]float64 `json:"hitrates,omitempty"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "github.com/guregu/null"
+
+// StatisticsGolf struct used for storing statistics for Golf
+type StatisticsGolf struct {
+	ID            int             `json:"id"`
+	LegID         int             `json:"leg_id"`
+	PlayerID      int             `json:"player_id"`
+	MatchesPlayed int             `json:"matches_played"`
+	MatchesWon    int             `json:"matches_won"`
+	LegsPlayed    int             `json:"legs_played"`
+	LegsWon       int             `json:"legs_won"`
+	OfficeID      null.Int        `json:"office_id,omitempty"`
+	Holes         int             `json:"holes,omitempty"`
+	Score         int             `json:"score,omitempty"`
+	BestNine      null.Int        `json:"best_nine,omitempty"`
+	BestEighteen  null.Int        `json:"best_eighteen,omitempty"`
+	HoleScores    map[int]float64 `json:"hole_scores,omitempty"`
+}
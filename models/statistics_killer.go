@@ -0,0 +1,20 @@
+package models
+
+import "github.com/guregu/null"
+
+// StatisticsKiller struct used for storing statistics for Killer
+type StatisticsKiller struct {
+	ID                  int      `json:"id"`
+	LegID               int      `json:"leg_id"`
+	PlayerID            int      `json:"player_id"`
+	MatchesPlayed       int      `json:"matches_played"`
+	MatchesWon          int      `json:"matches_won"`
+	LegsPlayed          int      `json:"legs_played"`
+	LegsWon             int      `json:"legs_won"`
+	OfficeID            null.Int `json:"office_id,omitempty"`
+	DartsThrown         int      `json:"darts_thrown,omitempty"`
+	DartsToBecomeKiller null.Int `json:"darts_to_become_killer"`
+	LivesLost           int      `json:"lives_lost"`
+	LivesTaken          int      `json:"lives_taken"`
+	FinalPosition       int      `json:"final_position"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Team struct used for storing a side of two or more players sharing one score. The team is represented in legs
+// and visits by the player given by PlayerID, and players throw in the order given by Players
+type Team struct {
+	PlayerID int   `json:"player_id"`
+	Players  []int `json:"players"`
+}
+
+// Validate will check that the team has at least two players, including the player representing the team
+func (team Team) Validate() error {
+	if len(team.Players) < 2 {
+		return errors.New("team must have at least two players")
+	}
+	if !team.HasPlayer(team.PlayerID) {
+		return fmt.Errorf("team %d must include player %d", team.PlayerID, team.PlayerID)
+	}
+	return nil
+}
+
+// ValidateTeams will check the teams of the match. Each team must be a player in the match, and each member can only be listed once,
+// in one team, and cannot also be another player in the match, to keep the rotation of each team
+func (match Match) ValidateTeams() error {
+	teams := make(map[int]int)
+	for _, team := range match.Teams {
+		err := team.Validate()
+		if err != nil {
+			return err
+		}
+		if !containsInt(match.Players, team.PlayerID) {
+			return fmt.Errorf("team %d is not a player in the match", team.PlayerID)
+		}
+		for _, memberID := range team.Players {
+			if teamID, ok := teams[memberID]; ok {
+				if teamID == team.PlayerID {
+					return fmt.Errorf("player %d is listed twice in team %d", memberID, team.PlayerID)
+				}
+				return fmt.Errorf("player %d is a member of both team %d and team %d", memberID, teamID, team.PlayerID)
+			}
+			if memberID != team.PlayerID && containsInt(match.Players, memberID) {
+				return fmt.Errorf("player %d of team %d is also a player in the match", memberID, team.PlayerID)
+			}
+			teams[memberID] = team.PlayerID
+		}
+	}
+	return nil
+}
+
+// HasPlayer will check if the given player is a member of the team
+func (team Team) HasPlayer(playerID int) bool {
+	for _, id := range team.Players {
+		if id == playerID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetNextThrower will return the player of the team to throw next, given the visits already thrown in the leg
+func (team Team) GetNextThrower(visits []*Visit) int {
+	thrown := 0
+	for _, visit := range visits {
+		if visit.PlayerID == team.PlayerID {
+			thrown++
+		}
+	}
+	return team.Players[thrown%len(team.Players)]
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTeamValidate will check that a team has at least two players, including the player representing it
+func TestTeamValidate(t *testing.T) {
+	assert.Nil(t, Team{PlayerID: 1, Players: []int{1, 2}}.Validate())
+	assert.NotNil(t, Team{PlayerID: 1, Players: []int{1}}.Validate(), "should require two players")
+	assert.NotNil(t, Team{PlayerID: 1, Players: []int{2, 3}}.Validate(), "should include representing player")
+}
+
+// TestMatchValidateTeams will check that each member of a team throws for one side only
+func TestMatchValidateTeams(t *testing.T) {
+	match := Match{Players: []int{1, 2}, Teams: []*Team{{PlayerID: 1, Players: []int{1, 3}}, {PlayerID: 2, Players: []int{2, 4}}}}
+	assert.Nil(t, match.ValidateTeams())
+
+	match.Teams = []*Team{{PlayerID: 5, Players: []int{5, 3}}}
+	assert.NotNil(t, match.ValidateTeams(), "team should be a player in the match")
+
+	match.Teams = []*Team{{PlayerID: 1, Players: []int{1, 3, 3}}}
+	assert.NotNil(t, match.ValidateTeams(), "member should not be listed twice")
+
+	match.Teams = []*Team{{PlayerID: 1, Players: []int{1, 2}}}
+	assert.NotNil(t, match.ValidateTeams(), "member should not be another player in the match")
+
+	match.Teams = []*Team{{PlayerID: 1, Players: []int{1, 3}}, {PlayerID: 2, Players: []int{2, 3}}}
+	assert.NotNil(t, match.ValidateTeams(), "member should not be shared by two teams")
+}
+
+// TestTeamGetNextThrower will check that players in a team throw in rotation
+func TestTeamGetNextThrower(t *testing.T) {
+	team := Team{PlayerID: 1, Players: []int{1, 3}}
+	visits := []*Visit{}
+	assert.Equal(t, team.GetNextThrower(visits), 1, "player 1 should throw first")
+
+	visits = append(visits, &Visit{PlayerID: 1}, &Visit{PlayerID: 2})
+	assert.Equal(t, team.GetNextThrower(visits), 3, "player 3 should throw second")
+
+	visits = append(visits, &Visit{PlayerID: 1}, &Visit{PlayerID: 2})
+	assert.Equal(t, team.GetNextThrower(visits), 1, "player 1 should throw third")
+}
+
+// TestMatchSetThrower will check that visits of a team are only accepted from the next player of the team
+func TestMatchSetThrower(t *testing.T) {
+	match := Match{Teams: []*Team{{PlayerID: 1, Players: []int{1, 3}}}}
+	visits := []*Visit{{PlayerID: 1, ThrowerID: null.IntFrom(1)}, {PlayerID: 2}}
+
+	visit := &Visit{PlayerID: 1}
+	assert.Nil(t, match.SetThrower(visit, visits))
+	assert.Equal(t, visit.ThrowerID, null.IntFrom(3), "player 3 should be set as thrower")
+
+	visit = &Visit{PlayerID: 1, ThrowerID: null.IntFrom(3)}
+	assert.Nil(t, match.SetThrower(visit, visits), "player 3 should be allowed to throw")
+
+	visit = &Visit{PlayerID: 1, ThrowerID: null.IntFrom(1)}
+	assert.NotNil(t, match.SetThrower(visit, visits), "wrong partner should be rejected")
+
+	visit = &Visit{PlayerID: 1, ThrowerID: null.IntFrom(4)}
+	assert.NotNil(t, match.SetThrower(visit, visits), "player not in team should be rejected")
+
+	visit = &Visit{PlayerID: 2, ThrowerID: null.IntFrom(4)}
+	assert.Nil(t, match.SetThrower(visit, visits))
+	assert.Equal(t, visit.ThrowerID.Valid, false, "thrower should be cleared for players not in a team")
+}
+
+// TestMatchGetThrowerID will check that statistics are only credited to members of the team throwing the visit
+func TestMatchGetThrowerID(t *testing.T) {
+	match := Match{Teams: []*Team{{PlayerID: 1, Players: []int{1, 3}}}}
+	assert.Equal(t, match.GetThrowerID(Visit{PlayerID: 1, ThrowerID: null.IntFrom(3)}), 3, "should credit team member")
+	assert.Equal(t, match.GetThrowerID(Visit{PlayerID: 1, ThrowerID: null.IntFrom(4)}), 1, "should not credit player outside team")
+	assert.Equal(t, match.GetThrowerID(Visit{PlayerID: 2, ThrowerID: null.IntFrom(3)}), 2, "should not credit thrower of player without team")
+}
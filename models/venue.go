@@ -19,4 +19,5 @@ type VenueConfig struct {
 	HasSmartboard          bool        `json:"has_smartboard"`
 	SmartboardUUID         null.String `json:"smartboard_uuid,omitempty"`
 	SmartboardButtonNumber null.Int    `json:"smartboard_button_number,omitempty"`
+	LEDControllerURL       null.String `json:"led_controller_url,omitempty"`
 }
@@ -13,6 +13,7 @@ type Visit struct {
 	ID          int         `json:"id"`
 	LegID       int         `json:"leg_id"`
 	PlayerID    int         `json:"player_id"`
+	ThrowerID   null.Int    `json:"thrower_id,omitempty"`
 	FirstDart   *Dart       `json:"first_dart"`
 	SecondDart  *Dart       `json:"second_dart"`
 	ThirdDart   *Dart       `json:"third_dart"`
@@ -28,6 +29,14 @@ type Visit struct {
 
 type comparingMatrix [][]bool
 
+// GetThrowerID will return the player who threw the visit, which for teams is one of the players in the team
+func (visit Visit) GetThrowerID() int {
+	if visit.ThrowerID.Valid {
+		return int(visit.ThrowerID.Int64)
+	}
+	return visit.PlayerID
+}
+
 // GetDarts returns all darts for the given visit
 func (visit Visit) GetDarts() []Dart {
 	darts := []Dart{*visit.FirstDart, *visit.SecondDart, *visit.ThirdDart}
@@ -65,16 +74,16 @@ func (visit Visit) ValidateInput() error {
 	return nil
 }
 
-// SetIsBust will set IsBust for the given visit
-func (visit *Visit) SetIsBust(currentScore int) {
+// SetIsBust will set IsBust for the given visit, using the given outshot type
+func (visit *Visit) SetIsBust(currentScore int, outshotType int) {
 	isBust := false
-	isBust = visit.FirstDart.IsBust(currentScore)
+	isBust = visit.FirstDart.IsBust(currentScore, outshotType)
 	currentScore = currentScore - visit.FirstDart.GetScore()
 	if !isBust && currentScore > 0 {
-		isBust = visit.SecondDart.IsBust(currentScore)
+		isBust = visit.SecondDart.IsBust(currentScore, outshotType)
 		currentScore = currentScore - visit.SecondDart.GetScore()
 		if !isBust && currentScore > 0 {
-			isBust = visit.ThirdDart.IsBust(currentScore)
+			isBust = visit.ThirdDart.IsBust(currentScore, outshotType)
 		} else {
 			// Invalidate third dart if second was bust
 			visit.ThirdDart.Value = null.IntFromPtr(nil)
@@ -139,16 +148,71 @@ func (visit *Visit) SetIsBustAbove(currentScore int, targetScore int) {
 	visit.IsBust = isBust
 }
 
-// IsCheckout will check if the given visit is a checkout (remaining score is 0 and last dart thrown is a double)
-func (visit Visit) IsCheckout(currentScore int) bool {
+// getInDart will return the index of the first dart valid for getting in with the given inshot type, or 3 if no dart is valid
+func (visit *Visit) getInDart(inshotType int) int {
+	for i, dart := range []*Dart{visit.FirstDart, visit.SecondDart, visit.ThirdDart} {
+		if dart.Value.Valid && !dart.IsMiss() && dart.IsValidOutshot(inshotType) {
+			return i
+		}
+	}
+	return 3
+}
+
+// GetScoreBeforeIn will return the score of darts thrown before the first dart valid for getting in with the given inshot type.
+// These darts are stored as thrown, but do not count towards the score of a player who is not in yet
+func (visit *Visit) GetScoreBeforeIn(inshotType int) int {
+	score := 0
+	for _, dart := range []*Dart{visit.FirstDart, visit.SecondDart, visit.ThirdDart}[:visit.getInDart(inshotType)] {
+		score += dart.GetScore()
+	}
+	return score
+}
+
+// SetIsBustBeforeIn will set IsBust for the given visit of a player who is not in yet, using the given outshot and inshot type
+func (visit *Visit) SetIsBustBeforeIn(currentScore int, outshotType int, inshotType int) {
+	// Darts thrown before the player is in do not score, so add them back to make sure they can never bust
+	visit.SetIsBust(currentScore+visit.GetScoreBeforeIn(inshotType), outshotType)
+}
+
+// GetRound will return the round (starting at 1) of the next visit of the given player. Players might be knocked out,
+// so the round is given by the visits of the player, and not by the total number of visits
+func GetRound(visits []*Visit, playerID int) int {
+	round := 1
+	for _, visit := range visits {
+		if visit.PlayerID == playerID {
+			round++
+		}
+	}
+	return round
+}
+
+// GetDartsToGetIn will return the number of darts the given player needed to start scoring with the given inshot type,
+// or null if the player has not started scoring yet
+func GetDartsToGetIn(visits []*Visit, playerID int, inshotType int) null.Int {
+	darts := 0
+	for _, visit := range visits {
+		if visit.PlayerID != playerID {
+			continue
+		}
+		in := visit.getInDart(inshotType)
+		if !visit.IsBust && in < 3 {
+			return null.IntFrom(int64(darts + in + 1))
+		}
+		darts += visit.GetDartsThrown()
+	}
+	return null.IntFromPtr(nil)
+}
+
+// IsCheckout will check if the given visit is a checkout (remaining score is 0 and last dart thrown is valid for the given outshot type)
+func (visit Visit) IsCheckout(currentScore int, outshotType int) bool {
 	remaining := currentScore - visit.GetScore()
 	if remaining == 0 {
 		if visit.ThirdDart.Value.Valid {
-			return visit.ThirdDart.IsDouble()
+			return visit.ThirdDart.IsValidOutshot(outshotType)
 		} else if visit.SecondDart.Value.Valid {
-			return visit.SecondDart.IsDouble()
+			return visit.SecondDart.IsValidOutshot(outshotType)
 		} else {
-			return visit.FirstDart.IsDouble()
+			return visit.FirstDart.IsValidOutshot(outshotType)
 		}
 	}
 	return false
@@ -373,20 +437,14 @@ func (visit *Visit) CalculateCricketScore(scores map[int]*Player2Leg) int {
 	return points
 }
 
-// CalculateAroundTheClockScore will calculate the score for the given visit
-func (visit *Visit) CalculateAroundTheClockScore(currentScore int) int {
+// CalculateAroundTheClockScore will calculate the score for the given visit, where each target must be hit with the given multiplier
+func (visit *Visit) CalculateAroundTheClockScore(currentScore int, multiplier int64) int {
 	score := 0
-	if visit.FirstDart.ValueRaw() == currentScore+1 && visit.FirstDart.IsSingle() || (currentScore+1 == 21 && visit.FirstDart.IsBull()) {
-		score++
-		currentScore++
-	}
-	if visit.SecondDart.ValueRaw() == currentScore+1 && visit.SecondDart.IsSingle() || (currentScore+1 == 21 && visit.SecondDart.IsBull()) {
-		score++
-		currentScore++
-	}
-	if visit.ThirdDart.ValueRaw() == currentScore+1 && visit.ThirdDart.IsSingle() || (currentScore+1 == 21 && visit.ThirdDart.IsBull()) {
-		score++
-		currentScore++
+	for _, dart := range []*Dart{visit.FirstDart, visit.SecondDart, visit.ThirdDart} {
+		if dart.IsAroundTheClockHit(currentScore+1, multiplier) {
+			score++
+			currentScore++
+		}
 	}
 	return score
 }
@@ -428,6 +486,47 @@ func (visit *Visit) Calculate420Score(round int) int {
 	return score
 }
 
+// CalculateGolfScore will calculate the strokes for the given hole, based on the first dart hitting the target.
+// A hole where the target is not hit counts as GolfMissStrokes strokes
+func (visit *Visit) CalculateGolfScore(hole int) int {
+	target := TargetsGolf[hole]
+	for i, dart := range []*Dart{visit.FirstDart, visit.SecondDart, visit.ThirdDart} {
+		if strokes := dart.GetGolfStrokes(target, i); strokes > 0 {
+			return strokes
+		}
+	}
+	return GolfMissStrokes
+}
+
+// CalculateBaseballScore will calculate the runs for the given inning (starting at 1), where each dart hitting the number of the
+// inning scores runs equal to the multiplier
+func (visit *Visit) CalculateBaseballScore(inning int) int {
+	target := GetBaseballTarget(inning)
+	runs := 0
+	for _, dart := range []*Dart{visit.FirstDart, visit.SecondDart, visit.ThirdDart} {
+		if dart.ValueRaw() == target {
+			runs += int(dart.Multiplier)
+		}
+	}
+	return runs
+}
+
+// CalculateBobs27Score will calculate the score for the given round of Bob's 27, where each dart hitting the double adds
+// the value of the double, and missing with all darts subtracts it
+func (visit *Visit) CalculateBobs27Score(round int) int {
+	target := TargetsBobs27[round]
+	score := 0
+	for _, dart := range []*Dart{visit.FirstDart, visit.SecondDart, visit.ThirdDart} {
+		if dart.IsDouble() && dart.ValueRaw() == target {
+			score += target * 2
+		}
+	}
+	if score == 0 {
+		return -target * 2
+	}
+	return score
+}
+
 // CalculateKillBullScore will calculate the score for the given visit
 func (visit *Visit) CalculateKillBullScore() int {
 	score := 0
@@ -510,6 +609,48 @@ func (visit *Visit) CalculateJDCPracticeScore(round int) int {
 	return score
 }
 
+// CalculateKillerScore will make the player a killer when hitting the double of their own number. A killer takes a life from
+// the owner of each double hit, including themselves. It returns the number of lives taken from other players
+func (visit *Visit) CalculateKillerScore(scores map[int]*Player2Leg, params *LegParameters) int {
+	if params.Killers == nil {
+		params.Killers = make(map[int]bool)
+	}
+	taken := 0
+	for _, dart := range []*Dart{visit.FirstDart, visit.SecondDart, visit.ThirdDart} {
+		alive := 0
+		for _, player := range scores {
+			if player.Lives.Int64 > 0 {
+				alive++
+			}
+		}
+		if alive < 2 {
+			// Leg is finished, so remaining darts don't count
+			break
+		}
+		if !dart.IsDouble() {
+			continue
+		}
+		owner, ok := params.GetNumberOwner(dart.ValueRaw())
+		if !ok {
+			continue
+		}
+		if !params.Killers[visit.PlayerID] {
+			if owner == visit.PlayerID {
+				params.Killers[visit.PlayerID] = true
+			}
+			continue
+		}
+		player := scores[owner]
+		if player.Lives.Int64 > 0 {
+			player.Lives = null.IntFrom(player.Lives.Int64 - 1)
+			if owner != visit.PlayerID {
+				taken++
+			}
+		}
+	}
+	return taken
+}
+
 // IsShanghai will check if the given visit is a "Shanghai". A Shanghai visit is one where a single, double and triple multipler is hit with each dart
 func (visit *Visit) IsShanghai() bool {
 	first := visit.FirstDart
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetScoreBeforeIn will check that darts thrown before the player is in do not score
+func TestGetScoreBeforeIn(t *testing.T) {
+	visit := Visit{
+		FirstDart:  NewDart(null.IntFrom(20), SINGLE),
+		SecondDart: NewDart(null.IntFrom(20), TRIPLE),
+		ThirdDart:  NewDart(null.IntFrom(16), DOUBLE)}
+	assert.Equal(t, visit.GetScoreBeforeIn(OUTSHOTDOUBLE), 80, "first and second dart should not score")
+	assert.Equal(t, visit.GetScore()-visit.GetScoreBeforeIn(OUTSHOTDOUBLE), 32, "third dart should score")
+
+	visit = Visit{
+		FirstDart:  NewDart(null.IntFrom(20), SINGLE),
+		SecondDart: NewDart(null.IntFrom(20), TRIPLE),
+		ThirdDart:  NewDart(null.IntFrom(20), SINGLE)}
+	assert.Equal(t, visit.GetScoreBeforeIn(OUTSHOTMASTER), 20, "first dart should not score")
+	assert.Equal(t, visit.GetScoreBeforeIn(OUTSHOTDOUBLE), 100, "no dart should score")
+	assert.Equal(t, visit.GetScoreBeforeIn(OUTSHOTANY), 0, "all darts should score")
+}
+
+// TestSetIsBustBeforeIn will check that darts thrown before the player is in are kept as thrown, and still count as hits
+func TestSetIsBustBeforeIn(t *testing.T) {
+	visit := &Visit{
+		FirstDart:  NewDart(null.IntFrom(20), TRIPLE),
+		SecondDart: NewDart(null.IntFrom(20), SINGLE),
+		ThirdDart:  NewDart(null.IntFrom(16), DOUBLE)}
+	visit.SetIsBustBeforeIn(40, OUTSHOTDOUBLE, OUTSHOTDOUBLE)
+	assert.Equal(t, visit.IsBust, false, "darts before in should not bust")
+	assert.Equal(t, visit.FirstDart.GetScore(), 60, "first dart should be stored as thrown")
+
+	hits, dartsThrown := GetHitsMap([]*Visit{visit})
+	assert.Equal(t, hits[20].Triples, 1, "treble 20 should count as a hit")
+	assert.Equal(t, hits[20].Singles, 1, "single 20 should count as a hit")
+	assert.Equal(t, hits[16].Doubles, 1, "double 16 should count as a hit")
+	assert.Equal(t, dartsThrown, 3, "should throw 3 darts")
+
+	visit = &Visit{
+		FirstDart:  NewDart(null.IntFrom(20), TRIPLE),
+		SecondDart: NewDart(null.IntFrom(19), DOUBLE),
+		ThirdDart:  NewDart(null.IntFrom(1), SINGLE)}
+	visit.SetIsBustBeforeIn(40, OUTSHOTDOUBLE, OUTSHOTDOUBLE)
+	assert.Equal(t, visit.IsBust, true, "darts after in should bust")
+}
+
+// TestGetDartsToGetIn will check the number of darts a player needed to start scoring
+func TestGetDartsToGetIn(t *testing.T) {
+	visits := []*Visit{
+		{PlayerID: 1, FirstDart: NewDart(null.IntFrom(0), SINGLE), SecondDart: NewDart(null.IntFrom(0), SINGLE), ThirdDart: NewDart(null.IntFrom(0), SINGLE)},
+		{PlayerID: 2, FirstDart: NewDart(null.IntFrom(20), DOUBLE), SecondDart: NewDart(null.IntFrom(20), SINGLE), ThirdDart: NewDart(null.IntFrom(20), SINGLE)},
+		{PlayerID: 1, FirstDart: NewDart(null.IntFrom(0), SINGLE), SecondDart: NewDart(null.IntFrom(16), DOUBLE), ThirdDart: NewDart(null.IntFrom(20), SINGLE)},
+	}
+	assert.Equal(t, GetDartsToGetIn(visits, 1, OUTSHOTDOUBLE), null.IntFrom(5), "should need 5 darts")
+	assert.Equal(t, GetDartsToGetIn(visits, 2, OUTSHOTDOUBLE), null.IntFrom(1), "should need 1 dart")
+	assert.Equal(t, GetDartsToGetIn(visits, 3, OUTSHOTDOUBLE).Valid, false, "should not be in")
+}
+
+// TestCalculateKillerScore will check that a player becomes a killer on their own double, and then takes lives on other doubles
+func TestCalculateKillerScore(t *testing.T) {
+	params := &LegParameters{PlayerNumbers: map[int]int{1: 20, 2: 5, 3: 1}}
+	scores := map[int]*Player2Leg{
+		1: {PlayerID: 1, Lives: null.IntFrom(3)},
+		2: {PlayerID: 2, Lives: null.IntFrom(3)},
+		3: {PlayerID: 3, Lives: null.IntFrom(1)},
+	}
+
+	visit := Visit{PlayerID: 1, FirstDart: NewDart(null.IntFrom(5), DOUBLE), SecondDart: NewDart(null.IntFrom(20), DOUBLE), ThirdDart: NewDart(null.IntFrom(5), DOUBLE)}
+	assert.Equal(t, visit.CalculateKillerScore(scores, params), 1, "should take 1 life after becoming killer")
+	assert.Equal(t, params.Killers[1], true, "player should be killer")
+	assert.Equal(t, scores[2].Lives.Int64, int64(2), "player 2 should lose a life")
+
+	visit = Visit{PlayerID: 1, FirstDart: NewDart(null.IntFrom(20), DOUBLE), SecondDart: NewDart(null.IntFrom(1), DOUBLE), ThirdDart: NewDart(null.IntFrom(5), DOUBLE)}
+	assert.Equal(t, visit.CalculateKillerScore(scores, params), 2, "should only count lives taken from others")
+	assert.Equal(t, scores[1].Lives.Int64, int64(2), "killer should lose a life on own double")
+	assert.Equal(t, scores[3].Lives.Int64, int64(0), "player 3 should be out")
+	assert.Equal(t, scores[2].Lives.Int64, int64(1), "player 2 should lose a life")
+
+	visit = Visit{PlayerID: 2, FirstDart: NewDart(null.IntFrom(20), DOUBLE), SecondDart: NewDart(null.IntFrom(20), DOUBLE), ThirdDart: NewDart(null.IntFrom(20), DOUBLE)}
+	assert.Equal(t, visit.CalculateKillerScore(scores, params), 0, "non killer should not take lives")
+	assert.Equal(t, scores[1].Lives.Int64, int64(2), "player 1 should keep lives")
+}
+
+// TestCalculateGolfScore will check that the first dart hitting the target decides the strokes for the hole, so that a hit
+// with an earlier dart is always better than a hit with a later dart, and any hit is better than a miss
+func TestCalculateGolfScore(t *testing.T) {
+	miss := func() *Dart { return NewDart(null.IntFrom(20), SINGLE) }
+	tests := []struct {
+		position   int
+		multiplier int64
+		strokes    int
+	}{
+		{0, DOUBLE, 1}, {0, TRIPLE, 2}, {0, SINGLE, 3},
+		{1, DOUBLE, 4}, {1, TRIPLE, 5}, {1, SINGLE, 6},
+		{2, DOUBLE, 7}, {2, TRIPLE, 8}, {2, SINGLE, 9},
+	}
+	for _, test := range tests {
+		darts := []*Dart{miss(), miss(), miss()}
+		darts[test.position] = NewDart(null.IntFrom(1), test.multiplier)
+		visit := Visit{FirstDart: darts[0], SecondDart: darts[1], ThirdDart: darts[2]}
+		assert.Equal(t, visit.CalculateGolfScore(0), test.strokes, "dart %d with multiplier %d", test.position+1, test.multiplier)
+		assert.Equal(t, test.strokes < GolfMissStrokes, true, "hit should be better than a miss")
+	}
+
+	visit := Visit{FirstDart: NewDart(null.IntFrom(20), SINGLE), SecondDart: NewDart(null.IntFrom(1), TRIPLE), ThirdDart: NewDart(null.IntFrom(1), DOUBLE)}
+	assert.Equal(t, visit.CalculateGolfScore(0), 5, "first dart hitting the target should decide the strokes")
+
+	visit = Visit{FirstDart: miss(), SecondDart: miss(), ThirdDart: miss()}
+	assert.Equal(t, visit.CalculateGolfScore(0), GolfMissStrokes, "missing the target should be the miss strokes")
+}
+
+// TestCalculateBaseballScore will check that runs are scored for darts hitting the number of the inning
+func TestCalculateBaseballScore(t *testing.T) {
+	visit := Visit{FirstDart: NewDart(null.IntFrom(3), TRIPLE), SecondDart: NewDart(null.IntFrom(3), SINGLE), ThirdDart: NewDart(null.IntFrom(17), DOUBLE)}
+	assert.Equal(t, visit.CalculateBaseballScore(3), 4, "should score 4 runs")
+	assert.Equal(t, visit.CalculateBaseballScore(4), 0, "should score no runs")
+
+	visit = Visit{FirstDart: NewDart(null.IntFrom(25), DOUBLE), SecondDart: NewDart(null.IntFrom(25), SINGLE), ThirdDart: NewDart(null.IntFrom(0), SINGLE)}
+	assert.Equal(t, visit.CalculateBaseballScore(21), 3, "extra innings after 20 should be played on bull")
+}
+
+// TestCalculateBobs27Score will check that each hit on the double adds its value, and missing all darts subtracts it
+func TestCalculateBobs27Score(t *testing.T) {
+	visit := Visit{FirstDart: NewDart(null.IntFrom(5), DOUBLE), SecondDart: NewDart(null.IntFrom(5), SINGLE), ThirdDart: NewDart(null.IntFrom(5), DOUBLE)}
+	assert.Equal(t, visit.CalculateBobs27Score(4), 20, "should add D5 twice")
+	assert.Equal(t, visit.CalculateBobs27Score(5), -12, "should subtract D6")
+
+	visit = Visit{FirstDart: NewDart(null.IntFrom(25), DOUBLE), SecondDart: NewDart(null.IntFrom(0), SINGLE), ThirdDart: NewDart(null.IntFrom(0), SINGLE)}
+	assert.Equal(t, visit.CalculateBobs27Score(20), 50, "last round should be bull")
+}
+
+// TestCalculateAroundTheClockScore will check that targets are only hit with the required multiplier, and that bull needs a double when doubles are required
+func TestCalculateAroundTheClockScore(t *testing.T) {
+	visit := Visit{FirstDart: NewDart(null.IntFrom(1), SINGLE), SecondDart: NewDart(null.IntFrom(2), DOUBLE), ThirdDart: NewDart(null.IntFrom(2), SINGLE)}
+	assert.Equal(t, visit.CalculateAroundTheClockScore(0, SINGLE), 2, "should hit 1 and 2 as singles")
+	assert.Equal(t, visit.CalculateAroundTheClockScore(0, DOUBLE), 0, "should not hit 1 as a single")
+	assert.Equal(t, visit.CalculateAroundTheClockScore(1, DOUBLE), 1, "should hit D2")
+
+	visit = Visit{FirstDart: NewDart(null.IntFrom(25), SINGLE), SecondDart: NewDart(null.IntFrom(25), DOUBLE), ThirdDart: NewDart(null.IntFrom(0), SINGLE)}
+	assert.Equal(t, visit.CalculateAroundTheClockScore(20, TRIPLE), 1, "any bull should count for trebles")
+	assert.Equal(t, visit.CalculateAroundTheClockScore(20, DOUBLE), 1, "only double bull should count for doubles")
+}
+
+// TestGetRound will check that the round is given by the visits of the player, also when other players are knocked out
+func TestGetRound(t *testing.T) {
+	visits := []*Visit{{PlayerID: 1}, {PlayerID: 2}, {PlayerID: 3}}
+	assert.Equal(t, GetRound(visits, 1), 2, "player 1 should be in second round")
+	assert.Equal(t, GetRound(visits[:1], 2), 1, "player 2 should be in first round")
+
+	// Player 3 is knocked out in Knockout after the first round, so later rounds only have two visits
+	visits = append(visits, &Visit{PlayerID: 1}, &Visit{PlayerID: 2}, &Visit{PlayerID: 1}, &Visit{PlayerID: 2})
+	assert.Equal(t, GetRound(visits, 1), 4, "player 1 should be in fourth round")
+	assert.Equal(t, GetRound(visits[:6], 2), 3, "player 2 should be in third round")
+}
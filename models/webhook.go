@@ -0,0 +1,91 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/guregu/null"
+)
+
+const (
+	// WebhookMaxAttempts is the number of times a delivery is attempted before giving up
+	WebhookMaxAttempts = 5
+	// webhookRetryDelay is the delay before the first retry, doubled for each following attempt
+	webhookRetryDelay = 5 * time.Second
+)
+
+// Webhook struct used for storing webhook subscriptions, receiving events within the given office and venue.
+// If no events are given, all events are sent
+type Webhook struct {
+	ID        int         `json:"id"`
+	URL       string      `json:"url"`
+	Secret    string      `json:"secret,omitempty"`
+	Events    []string    `json:"events"`
+	OfficeID  null.Int    `json:"office_id"`
+	VenueID   null.Int    `json:"venue_id"`
+	IsActive  bool        `json:"is_active"`
+	CreatedAt null.String `json:"created_at,omitempty"`
+}
+
+// WebhookDelivery struct used for storing each attempt of delivering an event to a webhook
+type WebhookDelivery struct {
+	ID            int         `json:"id"`
+	WebhookID     int         `json:"webhook_id"`
+	EventSequence uint64      `json:"event_sequence"`
+	EventType     string      `json:"event_type"`
+	Attempt       int         `json:"attempt"`
+	StatusCode    null.Int    `json:"status_code"`
+	Error         null.String `json:"error"`
+	IsSuccess     bool        `json:"is_success"`
+	CreatedAt     string      `json:"created_at,omitempty"`
+}
+
+// ValidateInput will verify that the webhook has a valid URL and only subscribes to known events. The secret is only
+// required when creating a webhook
+func (webhook Webhook) ValidateInput() error {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url has to be an absolute http or https URL")
+	}
+	for _, event := range webhook.Events {
+		if !containsString(EventTypes, event) {
+			return fmt.Errorf("unknown event '%s'", event)
+		}
+	}
+	return nil
+}
+
+// Matches will check if the given event should be sent to the webhook
+func (webhook Webhook) Matches(event *Event) bool {
+	filter := EventFilter{OfficeID: webhook.OfficeID, VenueID: webhook.VenueID, Types: webhook.Events}
+	return webhook.IsActive && event.Type != EVENTRESYNC && filter.Matches(event)
+}
+
+// Sign will return the signature of the given payload, as a hex encoded HMAC-SHA256 using the secret of the webhook
+func (webhook Webhook) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(webhook.Secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// GetWebhookRetryDelay will return how long to wait before the given attempt (starting at 1), doubling the delay for each retry
+func GetWebhookRetryDelay(attempt int) time.Duration {
+	if attempt <= 1 {
+		return 0
+	}
+	return webhookRetryDelay * time.Duration(1<<uint(attempt-2))
+}
+
+func containsString(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWebhookValidateInput will check that webhooks need an absolute URL and known events
+func TestWebhookValidateInput(t *testing.T) {
+	assert.Nil(t, Webhook{URL: "https://example.com/hook", Events: []string{EVENTLEGFINISHED, EVENTONEEIGHTY}}.ValidateInput())
+	assert.NotNil(t, Webhook{URL: "/hook"}.ValidateInput(), "relative URL should be invalid")
+	assert.NotNil(t, Webhook{URL: "ftp://example.com/hook"}.ValidateInput(), "non-http URL should be invalid")
+	assert.NotNil(t, Webhook{URL: "http://example.com", Events: []string{"unknown"}}.ValidateInput(), "unknown event should be invalid")
+}
+
+// TestWebhookMatches will check that webhooks only receive events within their scope, and never resync events
+func TestWebhookMatches(t *testing.T) {
+	webhook := Webhook{IsActive: true, OfficeID: null.IntFrom(1), Events: []string{EVENTMATCHFINISHED}}
+	assert.True(t, webhook.Matches(&Event{Type: EVENTMATCHFINISHED, OfficeID: null.IntFrom(1)}), "should match event in office")
+	assert.False(t, webhook.Matches(&Event{Type: EVENTMATCHFINISHED, OfficeID: null.IntFrom(2)}), "should not match other office")
+	assert.False(t, webhook.Matches(&Event{Type: EVENTLEGFINISHED, OfficeID: null.IntFrom(1)}), "should not match other event")
+
+	webhook = Webhook{IsActive: true}
+	assert.True(t, webhook.Matches(&Event{Type: EVENTONEEIGHTY}), "should match all events")
+	assert.False(t, webhook.Matches(&Event{Type: EVENTRESYNC}), "should not match resync")
+	webhook.IsActive = false
+	assert.False(t, webhook.Matches(&Event{Type: EVENTONEEIGHTY}), "inactive webhook should not match")
+}
+
+// TestWebhookSign will check that payloads are signed with HMAC-SHA256 of the secret
+func TestWebhookSign(t *testing.T) {
+	webhook := Webhook{Secret: "key"}
+	assert.Equal(t, webhook.Sign([]byte("The quick brown fox jumps over the lazy dog")),
+		"sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8", "should sign payload")
+}
+
+// TestGetWebhookRetryDelay will check that the first attempt is immediate, and the delay doubles for each retry
+func TestGetWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, GetWebhookRetryDelay(1), time.Duration(0), "first attempt should be immediate")
+	assert.Equal(t, GetWebhookRetryDelay(2), 5*time.Second, "first retry should wait 5 seconds")
+	assert.Equal(t, GetWebhookRetryDelay(4), 20*time.Second, "third retry should wait 20 seconds")
+}
@@ -14,3 +14,12 @@ func StringToIntArray(s string) []int {
 	}
 	return ints
 }
+
+// IntArrayToString will convert the given int array into a comma separated string
+func IntArrayToString(ints []int) string {
+	strs := make([]string, len(ints))
+	for i, v := range ints {
+		strs[i] = strconv.Itoa(v)
+	}
+	return strings.Join(strs, ",")
+}